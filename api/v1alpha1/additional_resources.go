@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AdditionalResourceKinds lists kinds of objects which may be supplied
+// in `spec.additionalResources`. All of them are namespaced.
+var AdditionalResourceKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+}
+
+// reservedObjectNameSuffixes are suffixes of names of objects which operator
+// creates for the cluster itself, e.g. Services `<name>-grpc`
+var reservedObjectNameSuffixes = []string{
+	"",
+	"-grpc",
+	"-interconnect",
+	"-status",
+	"-datastreams",
+	"-postgres",
+	"-encryption-key",
+}
+
+// isReservedObjectName checks whether objects named objectName may be
+// created by operator for the cluster clusterName
+func isReservedObjectName(clusterName, objectName string) bool {
+	for _, suffix := range reservedObjectNameSuffixes {
+		if objectName == clusterName+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAdditionalResource decodes object supplied in `spec.additionalResources`.
+func ParseAdditionalResource(raw runtime.RawExtension) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+		return nil, fmt.Errorf("failed to parse additional resource: %w", err)
+	}
+	return obj, nil
+}
+
+func validateAdditionalResources(clusterName, namespace string, resources []runtime.RawExtension) error {
+	for i, raw := range resources {
+		obj, err := ParseAdditionalResource(raw)
+		if err != nil {
+			return fmt.Errorf("spec.additionalResources[%d]: %w", i, err)
+		}
+
+		groupKind := obj.GroupVersionKind().GroupKind()
+		allowed := false
+		for _, kind := range AdditionalResourceKinds {
+			if groupKind == kind.GroupKind() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("spec.additionalResources[%d]: kind %s is not allowed", i, groupKind)
+		}
+
+		if obj.GetName() == "" {
+			return fmt.Errorf("spec.additionalResources[%d]: metadata.name is required", i)
+		}
+		if isReservedObjectName(clusterName, obj.GetName()) {
+			return fmt.Errorf("spec.additionalResources[%d]: name %s is reserved for objects managed by operator", i, obj.GetName())
+		}
+		if obj.GetNamespace() != "" && obj.GetNamespace() != namespace {
+			return fmt.Errorf("spec.additionalResources[%d]: namespace must be %s", i, namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+)
+
+// durationAnnotations are annotations of Storage and Database holding
+// durations, their values are checked by webhooks
+var durationAnnotations = []string{
+	AnnotationRequeueDelay,
+	AnnotationRolloutStuckTimeout,
+}
+
+// ParseDurationAnnotation returns positive duration set by annotation key,
+// ok is false when annotation is not set.
+func ParseDurationAnnotation(annotations map[string]string, key string) (time.Duration, bool, error) {
+	value, ok := annotations[key]
+	if !ok {
+		return 0, false, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid %s annotation: %w", key, err)
+	}
+	if duration <= 0 {
+		return 0, true, fmt.Errorf("invalid %s annotation: duration must be positive, got %s", key, value)
+	}
+	return duration, true, nil
+}
+
+func validateDurationAnnotations(annotations map[string]string) error {
+	for _, key := range durationAnnotations {
+		if _, _, err := ParseDurationAnnotation(annotations, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -20,6 +24,29 @@ type NamespacedRef struct {
 	Namespace string `json:"namespace"`
 }
 
+// StorageClusterRef is a reference to Storage, which is looked up in the
+// cluster of Kubernetes API described by kubeconfig, if any
+type StorageClusterRef struct {
+	NamespacedRef `json:",inline"`
+
+	// (Optional) Reference to Secret key with kubeconfig of the Kubernetes
+	// cluster where Storage is located. Secret is read from the namespace of
+	// Database. Secrets referenced by Storage (e.g. of operator connection)
+	// are read from the cluster of Database, and `storageEndpoint` must be set
+	// to the endpoint of Storage reachable from it.
+	// Default: (not specified), Storage is located in the same cluster
+	// +optional
+	KubeconfigSecretRef *corev1.SecretKeySelector `json:"kubeconfigSecretRef,omitempty"`
+
+	// (Optional) Endpoint of Storage used by operator for CMS requests of
+	// Database, e.g. a stable VIP or external load balancer, instead of
+	// `storageEndpoint`. Storage is still read for readiness of Database.
+	// Default: (not specified), `storageEndpoint` is used
+	// +kubebuilder:validation:Pattern:=^grpcs?://.+
+	// +optional
+	EndpointOverride string `json:"endpointOverride,omitempty"`
+}
+
 // PodImage represents the image information for a container that is used
 // to build the StatefulSet.
 type PodImage struct {
@@ -30,6 +57,8 @@ type PodImage struct {
 	Name string `json:"name,omitempty"`
 
 	// (Optional) PullPolicy for the image, which defaults to IfNotPresent.
+	// Images with a mutable tag (e.g. `latest`) default to Always, images
+	// pinned by digest are never pulled by tag.
 	// Default: IfNotPresent
 	// +optional
 	PullPolicyName *corev1.PullPolicy `json:"pullPolicy,omitempty"`
@@ -40,6 +69,123 @@ type PodImage struct {
 	PullSecret *string `json:"pullSecret,omitempty"`
 }
 
+// MutableImageTags is a set of image tags which may point to different images
+// over time. Images with such tags are always pulled by default.
+var MutableImageTags = []string{"latest"}
+
+func (r *PodImage) defaultPullPolicy() corev1.PullPolicy {
+	if r.hasMutableTag() {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+// HasDigest reports whether the image is pinned by digest, e.g.
+// `cr.yandex/ydb/ydb@sha256:...`, so that it never refers to another image
+func (r *PodImage) HasDigest() bool {
+	return r != nil && strings.Contains(r.Name, "@")
+}
+
+func (r *PodImage) hasMutableTag() bool {
+	if r.HasDigest() {
+		return false
+	}
+
+	tag := r.tag()
+	for _, mutableTag := range MutableImageTags {
+		if tag == mutableTag {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PodImage) tag() string {
+	// Image without tag refers to `latest`
+	tag := "latest"
+	if i := strings.LastIndex(r.Name, ":"); i > strings.LastIndex(r.Name, "/") {
+		tag = r.Name[i+1:]
+	}
+	return tag
+}
+
+// MinYDBVersion is the minimum version of YDB supported by the operator,
+// images of older versions are not rolled out. Empty value disables the check.
+var MinYDBVersion = "22.2"
+
+// CheckYDBVersion returns error if YDB version of the image is older than
+// MinYDBVersion. The version is taken from the image tag, or from explicit
+// version if the tag is not a version, e.g. `latest`. Images pinned by
+// digest and images of unknown version are accepted, explicit version is
+// ignored for images pinned by digest.
+func CheckYDBVersion(image *PodImage, ydbVersion string) error {
+	minVersion, ok := parseYDBVersion(MinYDBVersion)
+	if !ok || image.HasDigest() {
+		return nil
+	}
+
+	version := ""
+	if image != nil {
+		version = image.tag()
+	}
+	parsed, ok := parseYDBVersion(version)
+	if !ok {
+		version = ydbVersion
+		if parsed, ok = parseYDBVersion(version); !ok {
+			return nil
+		}
+	}
+
+	for i := range minVersion {
+		if parsed[i] != minVersion[i] {
+			if parsed[i] < minVersion[i] {
+				return fmt.Errorf("YDB version %s is not supported, minimum supported version is %s", version, MinYDBVersion)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// parseYDBVersion parses major, minor and patch components of YDB version,
+// e.g. `24.2.7` or `v24.2.7-hotfix`, missing components are zero
+func parseYDBVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return parsed, false
+	}
+
+	components := strings.Split(version, ".")
+	if len(components) > len(parsed) {
+		return parsed, false
+	}
+	for i, component := range components {
+		value, err := strconv.Atoi(component)
+		if err != nil || value < 0 {
+			return parsed, false
+		}
+		parsed[i] = value
+	}
+	return parsed, true
+}
+
+// ManagedResource refers to an object created by the operator for a resource
+type ManagedResource struct {
+	// Kind of the object, e.g. StatefulSet
+	Kind string `json:"kind"`
+
+	// Name of the object in the namespace of the resource
+	Name string `json:"name"`
+
+	// Error of the last sync of the object, empty when the object is synced
+	// +optional
+	SyncError string `json:"syncError,omitempty"`
+}
+
 type RemoteSpec struct {
 	// Remote cluster to deploy NodeSet into
 	// +required
@@ -54,3 +200,17 @@ type RemoteResource struct {
 	State      constants.RemoteResourceState `json:"state"`
 	Conditions []metav1.Condition            `json:"conditions,omitempty"`
 }
+
+// uniqueSecretNames drops empty and repeated names of referenced Secrets.
+func uniqueSecretNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := []string{}
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
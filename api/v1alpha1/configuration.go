@@ -5,21 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"text/template"
 
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration/schema"
 )
 
-func generateHosts(cr *Storage) []schema.Host {
+func generateHosts(cr *Storage, withHostConfigs bool) []schema.Host {
 	var hosts []schema.Host
 
+	dataCenters := cr.GetDataCenters()
 	for i := 0; i < int(cr.Spec.Nodes); i++ {
-		datacenter := "az-1"
-		if cr.Spec.Erasure == ErasureMirror3DC {
-			datacenter = fmt.Sprintf("az-%d", i%3)
-		}
-
 		hosts = append(hosts, schema.Host{
 			Host:         fmt.Sprintf("%v-%d", cr.GetName(), i),
 			HostConfigID: 1, // TODO
@@ -27,7 +26,7 @@ func generateHosts(cr *Storage) []schema.Host {
 			Port:         InterconnectPort,
 			WalleLocation: schema.WalleLocation{
 				Body:       12340 + i,
-				DataCenter: datacenter,
+				DataCenter: dataCenters[i%len(dataCenters)],
 				Rack:       strconv.Itoa(i),
 			},
 		})
@@ -35,10 +34,14 @@ func generateHosts(cr *Storage) []schema.Host {
 
 	if cr.Spec.NodeSets != nil {
 		hostIndex := 0
-		for _, nodeSetSpec := range cr.Spec.NodeSets {
+		for nodeSetIndex, nodeSetSpec := range cr.Spec.NodeSets {
 			for podIndex := 0; podIndex < int(nodeSetSpec.Nodes); podIndex++ {
 				podName := cr.GetName() + "-" + nodeSetSpec.Name + "-" + strconv.Itoa(podIndex)
 				hosts[hostIndex].Host = podName
+				// Every NodeSet has its own host config generated by operator
+				if withHostConfigs {
+					hosts[hostIndex].HostConfigID = nodeSetIndex + 1
+				}
 				hostIndex++
 			}
 		}
@@ -47,6 +50,262 @@ func generateHosts(cr *Storage) []schema.Host {
 	return hosts
 }
 
+// hasHostConfigs reports whether drives of storage nodes are generated
+// into `host_configs`, which is required with storage pools or several
+// pdisks per node.
+func hasHostConfigs(cr *Storage) bool {
+	return hasStoragePools(cr) || cr.Spec.PDisksPerNode > 1
+}
+
+// hasStoragePools reports whether storage pools are set for Storage
+// or any of its NodeSets.
+func hasStoragePools(cr *Storage) bool {
+	if len(cr.Spec.StoragePools) > 0 {
+		return true
+	}
+	for _, nodeSetSpec := range cr.Spec.NodeSets {
+		if len(nodeSetSpec.StoragePools) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// generateDrives lists pdisks in the same order as volumes are attached
+// to the StatefulSet: DataStore devices first, then storage pools.
+// DataStore with Filesystem volume mode has no device and is skipped.
+func generateDrives(dataStore []corev1.PersistentVolumeClaimSpec, storagePools []StoragePool) []schema.Drive {
+	var drives []schema.Drive
+	for i, spec := range dataStore {
+		if spec.VolumeMode == nil || *spec.VolumeMode != corev1.PersistentVolumeBlock {
+			continue
+		}
+		drives = append(drives, schema.Drive{
+			Path: fmt.Sprintf("%s_%0*d", DiskPathPrefix, DiskNumberMaxDigits, i),
+			Type: DefaultDriveType,
+		})
+	}
+	for i, pool := range storagePools {
+		drives = append(drives, schema.Drive{
+			Path: fmt.Sprintf("%s_%0*d", DiskPathPrefix, DiskNumberMaxDigits, len(dataStore)+i),
+			Type: strings.ToUpper(pool.Kind),
+		})
+	}
+	return drives
+}
+
+func generateHostConfigs(cr *Storage) []schema.HostConfig {
+	if cr.Spec.NodeSets == nil {
+		return []schema.HostConfig{{
+			HostConfigID: 1,
+			Drive:        generateDrives(ExpandDataStore(cr.Spec.DataStore, cr.Spec.PDisksPerNode), cr.Spec.StoragePools),
+		}}
+	}
+
+	var hostConfigs []schema.HostConfig
+	for i, nodeSetSpec := range cr.Spec.NodeSets {
+		dataStore := cr.Spec.DataStore
+		if nodeSetSpec.DataStore != nil {
+			dataStore = nodeSetSpec.DataStore
+		}
+		storagePools := cr.Spec.StoragePools
+		if nodeSetSpec.StoragePools != nil {
+			storagePools = nodeSetSpec.StoragePools
+		}
+		hostConfigs = append(hostConfigs, schema.HostConfig{
+			HostConfigID: i + 1,
+			Drive:        generateDrives(ExpandDataStore(dataStore, cr.Spec.PDisksPerNode), storagePools),
+		})
+	}
+	return hostConfigs
+}
+
+// generateNodeDrives lists pdisks backed by DataStore of every storage
+// node in the order of generated hosts
+func generateNodeDrives(cr *Storage) [][]schema.Drive {
+	drives := generateDrives(ExpandDataStore(cr.Spec.DataStore, cr.Spec.PDisksPerNode), nil)
+	nodeDrives := make([][]schema.Drive, cr.Spec.Nodes)
+	for i := range nodeDrives {
+		nodeDrives[i] = drives
+	}
+
+	hostIndex := 0
+	for _, nodeSetSpec := range cr.Spec.NodeSets {
+		nodeSetDrives := drives
+		if nodeSetSpec.DataStore != nil {
+			nodeSetDrives = generateDrives(ExpandDataStore(nodeSetSpec.DataStore, cr.Spec.PDisksPerNode), nil)
+		}
+		for podIndex := 0; podIndex < int(nodeSetSpec.Nodes) && hostIndex < len(nodeDrives); podIndex++ {
+			nodeDrives[hostIndex] = nodeSetDrives
+			hostIndex++
+		}
+	}
+	return nodeDrives
+}
+
+// generateStaticGroup defines static group of blobstorage on pdisks of
+// storage nodes. Rings of mirror-3-dc erasure are placed to data centers
+// with 3 fail domains each, other erasures have a single ring with a fail
+// domain per node. Vdisks of fail domains sharing a node are spread over
+// its pdisks
+func generateStaticGroup(cr *Storage, hosts []schema.Host) (*schema.BlobStorageConfig, error) {
+	erasureSpecies, err := getErasureSpecies(cr.Spec.Erasure)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeDrives := generateNodeDrives(cr)
+	vdiskLocation := func(hostIndex, vdiskIndex int) (schema.VDiskLocation, error) {
+		drives := nodeDrives[hostIndex]
+		if len(drives) == 0 {
+			return schema.VDiskLocation{}, fmt.Errorf(
+				"static group can not be placed to host %s without pdisks, `Block` volume mode of dataStore is required",
+				hosts[hostIndex].Host,
+			)
+		}
+		drive := drives[vdiskIndex%len(drives)]
+		return schema.VDiskLocation{
+			NodeID:        hosts[hostIndex].NodeID,
+			PDiskCategory: drive.Type,
+			Path:          drive.Path,
+		}, nil
+	}
+
+	var ringHosts [][]int
+	failDomains := 1
+	switch cr.Spec.Erasure {
+	case ErasureMirror3DC:
+		failDomains = 3
+		for _, dataCenter := range cr.GetDataCenters() {
+			var dataCenterHosts []int
+			for i := range hosts {
+				if hosts[i].WalleLocation.DataCenter == dataCenter {
+					dataCenterHosts = append(dataCenterHosts, i)
+				}
+			}
+			ringHosts = append(ringHosts, dataCenterHosts)
+		}
+	case ErasureBlock42, ErasureMirror3of4:
+		failDomains = 8
+		fallthrough
+	default:
+		var allHosts []int
+		for i := range hosts {
+			allHosts = append(allHosts, i)
+		}
+		ringHosts = append(ringHosts, allHosts)
+	}
+
+	group := schema.StaticGroup{ErasureSpecies: erasureSpecies}
+	for _, hostIndexes := range ringHosts {
+		if len(hostIndexes) == 0 {
+			return nil, errors.New("static group can not be placed to data center without storage nodes")
+		}
+		ring := schema.Ring{}
+		for i := 0; i < failDomains; i++ {
+			location, err := vdiskLocation(hostIndexes[i%len(hostIndexes)], i/len(hostIndexes))
+			if err != nil {
+				return nil, err
+			}
+			ring.FailDomains = append(ring.FailDomains, schema.FailDomain{
+				VDiskLocations: []schema.VDiskLocation{location},
+			})
+		}
+		group.Rings = append(group.Rings, ring)
+	}
+
+	return &schema.BlobStorageConfig{
+		ServiceSet: &schema.ServiceSet{
+			Groups: []schema.StaticGroup{group},
+		},
+	}, nil
+}
+
+// getErasureSpecies returns erasure species of blobstorage for the erasure
+// type, so that unsupported erasure fails the build of configuration
+// instead of the box definition at runtime
+func getErasureSpecies(erasure ErasureType) (string, error) {
+	if _, ok := MinNodesPerErasure[erasure]; !ok {
+		return "", fmt.Errorf("unsupported erasure type %v", erasure)
+	}
+	return string(erasure), nil
+}
+
+// generateStoragePoolTypes adds definitions of storage pools which kinds
+// are not declared in `domains_config`, so blobstorage init defines them
+// in the box together with the pdisks of host configs.
+func generateStoragePoolTypes(cr *Storage, config map[string]interface{}) error {
+	erasureSpecies, err := getErasureSpecies(cr.Spec.Erasure)
+	if err != nil {
+		return err
+	}
+
+	var kinds []string
+	seenKinds := make(map[string]bool)
+	addKinds := func(storagePools []StoragePool) {
+		for _, pool := range storagePools {
+			if !seenKinds[pool.Kind] {
+				seenKinds[pool.Kind] = true
+				kinds = append(kinds, pool.Kind)
+			}
+		}
+	}
+	addKinds(cr.Spec.StoragePools)
+	for _, nodeSetSpec := range cr.Spec.NodeSets {
+		addKinds(nodeSetSpec.StoragePools)
+	}
+
+	domainsConfig, ok := config["domains_config"].(map[string]interface{})
+	if !ok {
+		domainsConfig = map[string]interface{}{
+			"domain": []interface{}{
+				map[string]interface{}{"name": cr.Spec.Domain},
+			},
+		}
+		config["domains_config"] = domainsConfig
+	}
+	domains, _ := domainsConfig["domain"].([]interface{})
+
+	for _, item := range domains {
+		domain, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		poolTypes, _ := domain["storage_pool_types"].([]interface{})
+		declaredKinds := make(map[string]bool)
+		for _, poolType := range poolTypes {
+			if poolType, ok := poolType.(map[string]interface{}); ok {
+				if kind, ok := poolType["kind"].(string); ok {
+					declaredKinds[kind] = true
+				}
+			}
+		}
+		for _, kind := range kinds {
+			if declaredKinds[kind] {
+				continue
+			}
+			poolTypes = append(poolTypes, map[string]interface{}{
+				"kind": kind,
+				"pool_config": map[string]interface{}{
+					"box_id":          1,
+					"erasure_species": erasureSpecies,
+					"kind":            kind,
+					"pdisk_filter": []interface{}{
+						map[string]interface{}{
+							"property": []interface{}{
+								map[string]interface{}{"type": strings.ToUpper(kind)},
+							},
+						},
+					},
+					"vdisk_kind": "Default",
+				},
+			})
+		}
+		domain["storage_pool_types"] = poolTypes
+	}
+	return nil
+}
+
 func BuildConfiguration(cr *Storage, crDB *Database) ([]byte, error) {
 	config := make(map[string]interface{})
 
@@ -61,16 +320,42 @@ func BuildConfiguration(cr *Storage, crDB *Database) ([]byte, error) {
 		rawYamlConfiguration = cr.Spec.Configuration
 	}
 
+	// Configuration rendered from user template is used as is
+	if cr.Spec.ConfigurationTemplate != nil && (crDB == nil || crDB.Spec.Configuration == "") {
+		return []byte(rawYamlConfiguration), nil
+	}
+
+	if cr.Spec.ConfigurationVersion == ConfigurationV2 {
+		return buildConfigurationV2(cr, crDB, rawYamlConfiguration)
+	}
+
 	success, dynConfig, err := ParseDynConfig(rawYamlConfiguration)
 	if success {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse dynconfig, error: %w", err)
 		}
+		withHostConfigs := dynConfig.Config["host_configs"] == nil && hasHostConfigs(cr)
+		if withHostConfigs {
+			dynConfig.Config["host_configs"] = generateHostConfigs(cr)
+		}
+
 		if dynConfig.Config["hosts"] == nil {
-			hosts := generateHosts(cr)
+			hosts := generateHosts(cr, withHostConfigs)
 			dynConfig.Config["hosts"] = hosts
 		}
 
+		if hasStoragePools(cr) {
+			if err := generateStoragePoolTypes(cr, dynConfig.Config); err != nil {
+				return nil, err
+			}
+		}
+
+		setSpillingRoot(crDB, dynConfig.Config)
+		setPostgresEndpoint(crDB, dynConfig.Config)
+		setFeatureFlags(crDB, dynConfig.Config)
+		setInterconnectEncryption(cr, crDB, dynConfig.Config)
+		setKeepalive(cr, dynConfig.Config)
+
 		return yaml.Marshal(dynConfig)
 	}
 
@@ -79,14 +364,309 @@ func BuildConfiguration(cr *Storage, crDB *Database) ([]byte, error) {
 		return nil, fmt.Errorf("failed to serialize YAML config, error: %w", err)
 	}
 
+	withHostConfigs := config["host_configs"] == nil && hasHostConfigs(cr)
+	if withHostConfigs {
+		config["host_configs"] = generateHostConfigs(cr)
+	}
+
 	if config["hosts"] == nil {
-		hosts := generateHosts(cr)
+		hosts := generateHosts(cr, withHostConfigs)
 		config["hosts"] = hosts
+
+		// Static group is only generated together with hosts it is placed to
+		if cr.Spec.Topology != nil && config["blob_storage_config"] == nil {
+			blobStorageConfig, err := generateStaticGroup(cr, hosts)
+			if err != nil {
+				return nil, err
+			}
+			config["blob_storage_config"] = blobStorageConfig
+		}
+	}
+
+	if hasStoragePools(cr) {
+		if err := generateStoragePoolTypes(cr, config); err != nil {
+			return nil, err
+		}
 	}
 
+	setSpillingRoot(crDB, config)
+	setPostgresEndpoint(crDB, config)
+	setFeatureFlags(crDB, config)
+	setInterconnectEncryption(cr, crDB, config)
+	setKeepalive(cr, config)
+
 	return yaml.Marshal(config)
 }
 
+// setSpillingRoot points query spilling of database nodes to the mounted
+// scratch space, unless spilling service is configured explicitly.
+func setSpillingRoot(crDB *Database, config map[string]interface{}) {
+	if crDB == nil || crDB.Spec.ScratchSpace == nil {
+		return
+	}
+
+	tableServiceConfig, ok := config["table_service_config"].(map[string]interface{})
+	if !ok {
+		tableServiceConfig = make(map[string]interface{})
+		config["table_service_config"] = tableServiceConfig
+	}
+
+	if tableServiceConfig["spilling_service_config"] == nil {
+		tableServiceConfig["spilling_service_config"] = map[string]interface{}{
+			"local_file_config": map[string]interface{}{
+				"enable": true,
+				"root":   ScratchSpaceDir,
+			},
+		}
+	}
+}
+
+// setPostgresEndpoint makes database nodes listen PostgreSQL wire protocol
+// and enables PostgreSQL syntax, unless configured explicitly.
+func setPostgresEndpoint(crDB *Database, config map[string]interface{}) {
+	if crDB == nil || !crDB.Spec.IsPostgresEnabled() {
+		return
+	}
+
+	if config["local_pg_wire_config"] == nil {
+		config["local_pg_wire_config"] = map[string]interface{}{
+			"listening_port": crDB.Spec.Postgres.GetPort(),
+		}
+	}
+
+	featureFlags, ok := config["feature_flags"].(map[string]interface{})
+	if !ok {
+		featureFlags = make(map[string]interface{})
+		config["feature_flags"] = featureFlags
+	}
+	if featureFlags["enable_pg_syntax"] == nil {
+		featureFlags["enable_pg_syntax"] = true
+	}
+}
+
+// setFeatureFlags sets `spec.featureFlags` of Database in configuration of
+// database nodes, flags of the spec override the configured ones.
+func setFeatureFlags(crDB *Database, config map[string]interface{}) {
+	if crDB == nil || len(crDB.Spec.FeatureFlags) == 0 {
+		return
+	}
+
+	featureFlags, ok := config["feature_flags"].(map[string]interface{})
+	if !ok {
+		featureFlags = make(map[string]interface{})
+		config["feature_flags"] = featureFlags
+	}
+	for name, value := range crDB.Spec.FeatureFlags {
+		featureFlags[name] = value
+	}
+}
+
+// setInterconnectEncryption requires encryption of interconnect traffic
+// with certificates mounted from `spec.service.interconnect.tlsConfiguration`.
+// Configuration of Database nodes follows the settings of Database, as only
+// its own certificates are mounted into Database pods.
+func setInterconnectEncryption(cr *Storage, crDB *Database, config map[string]interface{}) {
+	var tlsConfiguration *TLSConfiguration
+	if crDB != nil {
+		if crDB.Spec.Service != nil {
+			tlsConfiguration = crDB.Spec.Service.Interconnect.TLSConfiguration
+		}
+	} else if cr.Spec.Service != nil {
+		tlsConfiguration = cr.Spec.Service.Interconnect.TLSConfiguration
+	}
+	if tlsConfiguration == nil || !tlsConfiguration.Enabled {
+		return
+	}
+
+	interconnectConfig, ok := config["interconnect_config"].(map[string]interface{})
+	if !ok {
+		interconnectConfig = make(map[string]interface{})
+		config["interconnect_config"] = interconnectConfig
+	}
+
+	defaults := map[string]interface{}{
+		"start_tcp":                true,
+		"encryption_mode":          InterconnectEncryptionRequired,
+		"path_to_certificate_file": fmt.Sprintf("%s/tls.crt", InterconnectTLSDir),
+		"path_to_private_key_file": fmt.Sprintf("%s/tls.key", InterconnectTLSDir),
+		"path_to_ca_file":          fmt.Sprintf("%s/ca.crt", InterconnectTLSDir),
+	}
+	for key, value := range defaults {
+		if interconnectConfig[key] == nil {
+			interconnectConfig[key] = value
+		}
+	}
+}
+
+// setKeepalive sets keepalive of gRPC and interconnect connections from
+// `spec.keepalive` of Storage, settings of the spec override the configured ones.
+func setKeepalive(cr *Storage, config map[string]interface{}) {
+	if cr.Spec.Keepalive == nil {
+		return
+	}
+
+	if keepalive := cr.Spec.Keepalive.GRPC; keepalive != nil {
+		grpcConfig, ok := config["grpc_config"].(map[string]interface{})
+		if !ok {
+			grpcConfig = make(map[string]interface{})
+			config["grpc_config"] = grpcConfig
+		}
+		grpcConfig["keep_alive_enable"] = true
+		if keepalive.IdleTimeoutSeconds != nil {
+			grpcConfig["keep_alive_idle_timeout_trigger_sec"] = *keepalive.IdleTimeoutSeconds
+		}
+		if keepalive.ProbeIntervalSeconds != nil {
+			grpcConfig["keep_alive_probe_interval_sec"] = *keepalive.ProbeIntervalSeconds
+		}
+		if keepalive.MaxProbeCount != nil {
+			grpcConfig["keep_alive_max_probe_count"] = *keepalive.MaxProbeCount
+		}
+	}
+
+	if keepalive := cr.Spec.Keepalive.Interconnect; keepalive != nil {
+		interconnectConfig, ok := config["interconnect_config"].(map[string]interface{})
+		if !ok {
+			interconnectConfig = make(map[string]interface{})
+			config["interconnect_config"] = interconnectConfig
+		}
+		if keepalive.HeartbeatIntervalSeconds != nil {
+			interconnectConfig["heartbeat_interval_duration"] = map[string]interface{}{
+				"seconds": *keepalive.HeartbeatIntervalSeconds,
+			}
+		}
+		if keepalive.DeadPeerTimeoutSeconds != nil {
+			interconnectConfig["dead_peer_timeout_duration"] = map[string]interface{}{
+				"seconds": *keepalive.DeadPeerTimeoutSeconds,
+			}
+		}
+	}
+}
+
+// RenderConfigurationTemplate executes Go template with the Storage object
+// as context and checks that the result is a valid YAML document.
+func RenderConfigurationTemplate(cr *Storage, configurationTemplate string) ([]byte, error) {
+	tmpl, err := template.New("configuration").Option("missingkey=error").Parse(configurationTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration template, error: %w", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, cr); err != nil {
+		return nil, fmt.Errorf("failed to render configuration template, error: %w", err)
+	}
+
+	config := make(map[string]interface{})
+	if err := yaml.Unmarshal(rendered.Bytes(), &config); err != nil {
+		return nil, fmt.Errorf("rendered configuration template is not a valid YAML, error: %w", err)
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// MergeConfigurationOverlays merges YDB configuration overlays over the base
+// configuration in the given order and returns the result as YAML document.
+func MergeConfigurationOverlays(configuration []byte, overlays []string) ([]byte, error) {
+	config := make(map[string]interface{})
+	if err := yaml.Unmarshal(configuration, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse base configuration, error: %w", err)
+	}
+
+	for i, overlay := range overlays {
+		overlayConfig := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(overlay), &overlayConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration overlay %d, error: %w", i, err)
+		}
+		mergeConfiguration(config, overlayConfig)
+	}
+
+	return yaml.Marshal(config)
+}
+
+// mergeConfiguration merges nested mappings of overlay into config,
+// any other value of overlay replaces the one of config.
+func mergeConfiguration(config, overlay map[string]interface{}) {
+	for key, value := range overlay {
+		overlayMap, ok := value.(map[string]interface{})
+		if !ok {
+			config[key] = value
+			continue
+		}
+		configMap, ok := config[key].(map[string]interface{})
+		if !ok {
+			config[key] = overlayMap
+			continue
+		}
+		mergeConfiguration(configMap, overlayMap)
+	}
+}
+
+// buildConfigurationV2 renders the unified configuration. Plain configuration
+// body is wrapped into `metadata`/`config` sections and the fields managed by
+// the operator (erasure, hosts, self-management) are filled in when omitted.
+func buildConfigurationV2(cr *Storage, crDB *Database, rawYamlConfiguration string) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(rawYamlConfiguration)))
+	dec.KnownFields(true)
+
+	var dynConfig schema.DynConfig
+	if err := dec.Decode(&dynConfig); err != nil || dynConfig.Config == nil {
+		config := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(rawYamlConfiguration), &config); err != nil {
+			return nil, fmt.Errorf("failed to serialize YAML config, error: %w", err)
+		}
+		dynConfig = schema.DynConfig{Config: config}
+	}
+
+	if dynConfig.Metadata == nil {
+		dynConfig.Metadata = &schema.Metadata{
+			Kind:    "MainConfig",
+			Cluster: "",
+			Version: 0,
+		}
+	}
+
+	if dynConfig.Config["yaml_config_enabled"] == nil {
+		dynConfig.Config["yaml_config_enabled"] = true
+	}
+
+	if dynConfig.Config["erasure"] == nil {
+		dynConfig.Config["erasure"] = string(cr.Spec.Erasure)
+	}
+
+	if dynConfig.Config["self_management_config"] == nil {
+		dynConfig.Config["self_management_config"] = map[string]interface{}{
+			"enabled": true,
+		}
+	}
+
+	// Unified config derives storage pools from drive types of host_configs
+	withHostConfigs := dynConfig.Config["host_configs"] == nil && hasHostConfigs(cr)
+	if withHostConfigs {
+		dynConfig.Config["host_configs"] = generateHostConfigs(cr)
+	}
+
+	if dynConfig.Config["hosts"] == nil {
+		dynConfig.Config["hosts"] = generateHosts(cr, withHostConfigs)
+	}
+
+	setSpillingRoot(crDB, dynConfig.Config)
+	setPostgresEndpoint(crDB, dynConfig.Config)
+	setFeatureFlags(crDB, dynConfig.Config)
+	setInterconnectEncryption(cr, crDB, dynConfig.Config)
+	setKeepalive(cr, dynConfig.Config)
+
+	if err := validateDynConfig(dynConfig); err != nil {
+		return nil, fmt.Errorf("failed to validate unified config, error: %w", err)
+	}
+
+	return yaml.Marshal(dynConfig)
+}
+
+// IsUnifiedConfig reports whether dynconfig uses the unified (v2) format.
+func IsUnifiedConfig(dynConfig schema.DynConfig) bool {
+	_, exist := dynConfig.Config["self_management_config"]
+	return exist
+}
+
 func ParseConfiguration(rawYamlConfiguration string) (schema.Configuration, error) {
 	dec := yaml.NewDecoder(bytes.NewReader([]byte(rawYamlConfiguration)))
 	dec.KnownFields(false)
@@ -123,6 +703,18 @@ func validateDynConfig(dynConfig schema.DynConfig) error {
 		return errors.New("failed to find mandatory `yaml_config_enabled` field inside config")
 	}
 
+	if IsUnifiedConfig(dynConfig) {
+		if _, exist := dynConfig.Config["erasure"]; !exist {
+			return errors.New("failed to find mandatory `erasure` field inside config")
+		}
+
+		if _, exist := dynConfig.Config["host_configs"]; !exist {
+			return errors.New("failed to find mandatory `host_configs` field inside config")
+		}
+
+		return nil
+	}
+
 	if _, exist := dynConfig.Config["static_erasure"]; !exist {
 		return errors.New("failed to find mandatory `static_erasure` field inside config")
 	}
@@ -139,6 +731,11 @@ func validateDynConfig(dynConfig schema.DynConfig) error {
 }
 
 func GetConfigForCMS(dynConfig schema.DynConfig) ([]byte, error) {
+	// Unified config is managed as a whole, including static part
+	if IsUnifiedConfig(dynConfig) {
+		return yaml.Marshal(dynConfig)
+	}
+
 	delete(dynConfig.Config, "static_erasure")
 	delete(dynConfig.Config, "host_configs")
 	delete(dynConfig.Config, "nameservice_config")
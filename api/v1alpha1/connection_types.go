@@ -8,6 +8,7 @@ type ConnectionOptions struct {
 	AccessToken         *AccessTokenAuth       `json:"accessToken,omitempty"`
 	StaticCredentials   *StaticCredentialsAuth `json:"staticCredentials,omitempty"`
 	Oauth2TokenExchange *Oauth2TokenExchange   `json:"oauth2TokenExchange,omitempty"`
+	ServiceAccountKey   *ServiceAccountKeyAuth `json:"serviceAccountKey,omitempty"`
 }
 
 type AccessTokenAuth struct {
@@ -26,6 +27,23 @@ type Oauth2TokenExchange struct {
 	JWTClaims  `json:",inline"`
 }
 
+// ServiceAccountKeyAuth mints IAM tokens from the authorized key of
+// a service account
+type ServiceAccountKeyAuth struct {
+	// Authorized key of the service account in JSON format
+	*CredentialSource `json:",inline"`
+
+	// (Optional) IAM token service endpoint
+	// Default: https://iam.api.cloud.yandex.net/iam/v1/tokens
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// (Optional) Audience of the JWT exchanged for IAM token
+	// Default: IAM token service endpoint
+	// +optional
+	Audience string `json:"audience,omitempty"`
+}
+
 type JWTHeader struct {
 	KeyID   *string `json:"keyID"`
 	SignAlg string  `json:"signAlg,omitempty"`
@@ -40,3 +58,32 @@ type JWTClaims struct {
 type CredentialSource struct {
 	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef"`
 }
+
+// secretNames lists Secrets which credentials are read from.
+func (r *ConnectionOptions) secretNames() []string {
+	if r == nil {
+		return nil
+	}
+
+	var sources []*CredentialSource
+	if r.AccessToken != nil {
+		sources = append(sources, r.AccessToken.CredentialSource)
+	}
+	if r.StaticCredentials != nil {
+		sources = append(sources, r.StaticCredentials.Password)
+	}
+	if r.Oauth2TokenExchange != nil {
+		sources = append(sources, r.Oauth2TokenExchange.PrivateKey)
+	}
+	if r.ServiceAccountKey != nil {
+		sources = append(sources, r.ServiceAccountKey.CredentialSource)
+	}
+
+	var names []string
+	for _, source := range sources {
+		if source != nil && source.SecretKeyRef != nil {
+			names = append(names, source.SecretKeyRef.Name)
+		}
+	}
+	return names
+}
@@ -16,15 +16,23 @@ const (
 	InterconnectServicePortName   = "interconnect"
 	InterconnectServiceFQDNFormat = "%s-interconnect.%s.svc.cluster.local"
 
-	StatusPort            = 8765
-	StatusServicePortName = "status"
+	StatusPort                 = 8765
+	StatusServicePortName      = "status"
+	StatusServiceFQDNFormat    = "%s-status.%s.svc.cluster.local"
+	DefaultHealthCheckHTTPPath = "/viewer/json/healthcheck"
+	HealthCheckHTTPPathPrefix  = "/viewer/json/"
 
 	DatastreamsPort            = 8443
 	DatastreamsServicePortName = "datastreams"
 
+	PostgresPort              = 5432
+	PostgresServicePortName   = "postgres"
+	PostgresServiceFQDNFormat = "%s-postgres.%s.svc.cluster.local"
+
 	DiskPathPrefix      = "/dev/kikimr_ssd"
 	DiskNumberMaxDigits = 2
 	DiskFilePath        = "/data"
+	DefaultDriveType    = "SSD"
 	YdbAuthToken        = "ydb-auth-token-file"
 
 	ConfigDir      = "/opt/ydb/cfg"
@@ -36,18 +44,43 @@ const (
 
 	BinariesDir      = "/opt/ydb/bin"
 	DaemonBinaryName = "ydbd"
+	CLIBinaryName    = "ydb"
+
+	StorageContainerName         = "ydb-storage"
+	DatabaseContainerName        = "ydb-dynamic"
+	CaStorePatchingContainerName = "ydb-storage-init-container"
+
+	ScratchSpaceDir = "/opt/ydb/spilling"
+	LogVolumeDir    = "/opt/ydb/logs"
+	LogFileName     = "ydbd.log"
+
+	InterconnectTLSDir             = "/tls/interconnect"
+	InterconnectEncryptionRequired = "REQUIRED"
 
 	DefaultRootUsername          = "root"
 	DefaultRootPassword          = ""
 	DefaultDatabaseDomain        = "Root"
+	DefaultInitJobBackoffLimit   = 6
+	DefaultStartupProbePeriod    = 10
+	DefaultStartupProbeFailures  = 60
 	DefaultDatabaseEncryptionPin = "EmptyPin"
 	DefaultSignAlgorithm         = "RS256"
+	DefaultTargetCPUUtilization  = 80
+	DefaultStorageAffinityWeight = 100
+	DefaultRevisionHistoryLimit  = 3
+
+	DefaultScaleDownPeriodSeconds        = 60
+	DefaultScaleDownStabilizationSeconds = 300
+
+	DefaultStorageAffinityTopologyKey = "topology.kubernetes.io/zone"
+	DefaultNodePoolLabelKey           = "ydb.tech/node-pool"
 
 	LabelDeploymentKey             = "deployment"
 	LabelDeploymentValueKubernetes = "kubernetes"
 	LabelSharedDatabaseKey         = "shared"
 	LabelSharedDatabaseValueTrue   = "true"
 	LabelSharedDatabaseValueFalse  = "false"
+	LabelNodePoolKey               = "node_pool"
 
 	AnnotationUpdateStrategyOnDelete = "ydb.tech/update-strategy-on-delete"
 	AnnotationUpdateDNSPolicy        = "ydb.tech/update-dns-policy"
@@ -58,6 +91,29 @@ const (
 	AnnotationNodeHost               = "ydb.tech/node-host"
 	AnnotationNodeDomain             = "ydb.tech/node-domain"
 
+	// AnnotationReinitialize requests to rerun Storage initialization pipeline,
+	// annotation is removed by the operator when initialization is completed.
+	// Blobstorage init Job is destructive and is rerun only together with
+	// AnnotationReinitializeBlobstorage.
+	AnnotationReinitialize            = "ydb.tech/reinitialize"
+	AnnotationReinitializeBlobstorage = "ydb.tech/reinitialize-blobstorage"
+
+	// AnnotationSkipConfigValidation disables validation of changed Storage
+	// configuration against the running cluster, e.g. for YDB versions
+	// without `config validate` command
+	AnnotationSkipConfigValidation = "ydb.tech/skip-config-validation"
+
+	// AnnotationRequeueDelay overrides steady-state requeue delays of Storage
+	// or Database reconcile, i.e. the default one and the one of Ready cluster,
+	// value is a positive Go duration, e.g. "5m"
+	AnnotationRequeueDelay = "ydb.tech/requeue-delay"
+
+	// AnnotationRolloutStuckTimeout overrides the period after which rollout
+	// of StatefulSet of Storage or Database, or of their node sets, without
+	// progress is reported in RolloutStuck condition, value is a positive Go
+	// duration, e.g. "30m"
+	AnnotationRolloutStuckTimeout = "ydb.tech/rollout-stuck-timeout"
+
 	AnnotationValueTrue = "true"
 
 	legacyTenantNameFormat = "/%s/%s"
@@ -66,7 +122,81 @@ const (
 type ErasureType string
 
 const (
-	ErasureBlock42   ErasureType = "block-4-2"
-	ErasureMirror3DC ErasureType = "mirror-3-dc"
-	None             ErasureType = "none"
+	ErasureBlock42    ErasureType = "block-4-2"
+	ErasureMirror3DC  ErasureType = "mirror-3-dc"
+	ErasureMirror3of4 ErasureType = "mirror-3of4"
+	None              ErasureType = "none"
 )
+
+// MinNodesPerErasure is the minimal number of Storage nodes required by
+// each supported erasure type
+var MinNodesPerErasure = map[ErasureType]int32{
+	ErasureBlock42:    8,
+	ErasureMirror3DC:  3,
+	ErasureMirror3of4: 8,
+	None:              1,
+}
+
+type ConfigurationVersion string
+
+const (
+	ConfigurationV1 ConfigurationVersion = "v1"
+	ConfigurationV2 ConfigurationVersion = "v2"
+)
+
+type RollingUpdateMode string
+
+const (
+	RollingUpdateModeAuto        RollingUpdateMode = "Auto"
+	RollingUpdateModeManual      RollingUpdateMode = "Manual"
+	RollingUpdateModeMaintenance RollingUpdateMode = "Maintenance"
+)
+
+type ConfigChangePolicy string
+
+const (
+	ConfigChangePolicyImmediate    ConfigChangePolicy = "Immediate"
+	ConfigChangePolicyManual       ConfigChangePolicy = "Manual"
+	ConfigChangePolicyOnPodRestart ConfigChangePolicy = "OnPodRestart"
+)
+
+type PauseMode string
+
+const (
+	PauseModeScaleToZero PauseMode = "ScaleToZero"
+	PauseModeFreeze      PauseMode = "Freeze"
+)
+
+type StorageAffinityMode string
+
+const (
+	StorageAffinityPreferred StorageAffinityMode = "Preferred"
+	StorageAffinityRequired  StorageAffinityMode = "Required"
+)
+
+type HealthCheckMechanism string
+
+const (
+	HealthCheckGRPC HealthCheckMechanism = "GRPC"
+	HealthCheckHTTP HealthCheckMechanism = "HTTP"
+)
+
+// KnownFeatureFlags are feature flags of YDB accepted in `spec.featureFlags`
+// of Database
+var KnownFeatureFlags = map[string]struct{}{
+	"enable_changefeed_debezium_json_format":    {},
+	"enable_changefeed_dynamodb_streams_format": {},
+	"enable_changefeed_initial_scan":            {},
+	"enable_external_data_sources":              {},
+	"enable_olap_schema_operations":             {},
+	"enable_pg_syntax":                          {},
+	"enable_resource_pools":                     {},
+	"enable_script_execution_operations":        {},
+	"enable_table_datetime64":                   {},
+	"enable_table_pg_types":                     {},
+	"enable_temp_tables":                        {},
+	"enable_topic_service_tx":                   {},
+	"enable_uuid_as_primary_key":                {},
+	"enable_vector_index":                       {},
+	"enable_views":                              {},
+}
@@ -0,0 +1,31 @@
+package v1alpha1
+
+// v1alpha1 is the storage version of the ydb.tech API group and acts as the
+// conversion hub: every future version has to implement
+// sigs.k8s.io/controller-runtime/pkg/conversion.Convertible against these
+// types. The conversion webhook is served by controller-runtime as soon as
+// a second version of a kind is registered in the manager scheme.
+
+// Hub marks Storage as a conversion hub.
+func (*Storage) Hub() {}
+
+// Hub marks StorageNodeSet as a conversion hub.
+func (*StorageNodeSet) Hub() {}
+
+// Hub marks RemoteStorageNodeSet as a conversion hub.
+func (*RemoteStorageNodeSet) Hub() {}
+
+// Hub marks Database as a conversion hub.
+func (*Database) Hub() {}
+
+// Hub marks DatabaseNodeSet as a conversion hub.
+func (*DatabaseNodeSet) Hub() {}
+
+// Hub marks RemoteDatabaseNodeSet as a conversion hub.
+func (*RemoteDatabaseNodeSet) Hub() {}
+
+// Hub marks StorageMonitoring as a conversion hub.
+func (*StorageMonitoring) Hub() {}
+
+// Hub marks DatabaseMonitoring as a conversion hub.
+func (*DatabaseMonitoring) Hub() {}
@@ -0,0 +1,97 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	webhookconversion "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// storageSpoke mimics a future version of Storage which is converted
+// to and from the v1alpha1 hub without any changes.
+type storageSpoke struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   v1alpha1.StorageSpec   `json:"spec,omitempty"`
+	Status v1alpha1.StorageStatus `json:"status,omitempty"`
+}
+
+func (s *storageSpoke) DeepCopyObject() runtime.Object {
+	out := &storageSpoke{TypeMeta: s.TypeMeta}
+	s.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	s.Spec.DeepCopyInto(&out.Spec)
+	s.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
+func (s *storageSpoke) ConvertTo(dst conversion.Hub) error {
+	hub := dst.(*v1alpha1.Storage)
+	s.ObjectMeta.DeepCopyInto(&hub.ObjectMeta)
+	s.Spec.DeepCopyInto(&hub.Spec)
+	s.Status.DeepCopyInto(&hub.Status)
+	return nil
+}
+
+func (s *storageSpoke) ConvertFrom(src conversion.Hub) error {
+	hub := src.(*v1alpha1.Storage)
+	hub.ObjectMeta.DeepCopyInto(&s.ObjectMeta)
+	hub.Spec.DeepCopyInto(&s.Spec)
+	hub.Status.DeepCopyInto(&s.Status)
+	return nil
+}
+
+var _ = Describe("Conversion", func() {
+	It("marks all kinds as conversion hubs", func() {
+		for _, obj := range []runtime.Object{
+			&v1alpha1.Storage{},
+			&v1alpha1.StorageNodeSet{},
+			&v1alpha1.RemoteStorageNodeSet{},
+			&v1alpha1.Database{},
+			&v1alpha1.DatabaseNodeSet{},
+			&v1alpha1.RemoteDatabaseNodeSet{},
+			&v1alpha1.StorageMonitoring{},
+			&v1alpha1.DatabaseMonitoring{},
+		} {
+			_, ok := obj.(conversion.Hub)
+			Expect(ok).To(BeTrue(), "%T is not a conversion hub", obj)
+		}
+	})
+
+	It("does not require conversion while v1alpha1 is the only version", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		convertible, err := webhookconversion.IsConvertible(scheme, &v1alpha1.Storage{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(convertible).To(BeFalse())
+	})
+
+	It("serves v1alpha1 alongside a future version", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		scheme.AddKnownTypeWithName(
+			schema.GroupVersionKind{Group: v1alpha1.GroupVersion.Group, Version: "v1beta1", Kind: "Storage"},
+			&storageSpoke{},
+		)
+
+		convertible, err := webhookconversion.IsConvertible(scheme, &v1alpha1.Storage{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(convertible).To(BeTrue())
+
+		storage := newTestStorage()
+		spoke := &storageSpoke{}
+		Expect(spoke.ConvertFrom(storage)).To(Succeed())
+
+		restored := &v1alpha1.Storage{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.ObjectMeta).To(Equal(storage.ObjectMeta))
+		Expect(restored.Spec).To(Equal(storage.Spec))
+		Expect(restored.Status).To(Equal(storage.Status))
+	})
+})
@@ -2,7 +2,9 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants"
 )
@@ -17,12 +19,136 @@ type DatabaseSpec struct {
 	// Default: (not specified)
 	// +optional
 	NodeSets []DatabaseNodeSetSpecInline `json:"nodeSets,omitempty"`
+
+	// (Optional) Secret with connection parameters (endpoint and database path)
+	// created after database initialization. Credentials are never written to it
+	// Default: (not specified)
+	// +optional
+	ConnectionSecret *ConnectionSecretSpec `json:"connectionSecret,omitempty"`
+
+	// (Optional) Additional objects applied by operator alongside Database
+	// resources and owned by Database, e.g. NetworkPolicy. Only ConfigMap,
+	// Service and NetworkPolicy kinds are supported, objects are created in
+	// the namespace of Database. Names of objects created by operator itself
+	// can not be used. Objects removed from the list are deleted.
+	// Default: (not specified)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	// +optional
+	AdditionalResources []runtime.RawExtension `json:"additionalResources,omitempty"`
+
+	// (Optional) Horizontal autoscaling of Database nodes by CPU utilization.
+	// Operator creates HorizontalPodAutoscaler which changes `spec.nodes`
+	// through the scale subresource of Database, so manual changes of
+	// `spec.nodes` are overridden by the autoscaler within its bounds.
+	// Autoscaler removes nodes one by one, not more often than once per
+	// `terminationGracePeriodSeconds` (at least 60s), so that tablets are
+	// drained from the removed node. Not supported with `nodeSets` and for
+	// serverless Database.
+	// Default: (not specified)
+	// +optional
+	Autoscaling *DatabaseAutoscaling `json:"autoscaling,omitempty"`
+
+	// (Optional) Pod affinity of Database nodes towards the pods of referenced
+	// Storage, so that scheduler places compute in the same topology domains
+	// (zones by default) as storage. Not applied to remote nodeSets.
+	// Default: (not specified)
+	// +optional
+	StorageAffinity *StorageAffinity `json:"storageAffinity,omitempty"`
+
+	// (Optional) Existing Database served by the same Storage to copy schema,
+	// and optionally data, from once the Database is created, e.g. to prepare
+	// staging from production. Copy is performed by Job running `ydb tools
+	// dump` and `ydb tools restore`, progress is tracked in `DatabaseCloned`
+	// condition. Cannot be changed after creation.
+	// Default: (not specified)
+	// +optional
+	SourceDatabaseRef *SourceDatabaseRef `json:"sourceDatabaseRef,omitempty"`
+
+	// (Optional) YQL scripts executed once, in list order, after the
+	// Database is initialized, e.g. to create schema. Scripts are tracked
+	// by checksum, so a changed script is executed again. Progress is
+	// tracked in `SchemaInitialized` condition.
+	// Default: (not specified)
+	// +optional
+	InitScripts []InitScript `json:"initScripts,omitempty"`
+}
+
+// InitScript is a YQL script, specified either inline or by reference
+// to ConfigMap key
+type InitScript struct {
+	// (Optional) Text of the script, e.g. `CREATE TABLE ...`
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// (Optional) Reference to ConfigMap key with text of the script
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+type SourceDatabaseRef struct {
+	NamespacedRef `json:",inline"`
+
+	// (Optional) Copy data of tables along with schema
+	// Default: false
+	// +optional
+	WithData bool `json:"withData,omitempty"`
+}
+
+type StorageAffinity struct {
+	// (Optional) Whether the affinity is a scheduling preference or a requirement
+	// Default: Preferred
+	// +kubebuilder:validation:Enum=Preferred;Required
+	// +optional
+	Mode StorageAffinityMode `json:"mode,omitempty"`
+
+	// (Optional) Node label defining the topology domain shared with Storage pods
+	// Default: topology.kubernetes.io/zone
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// (Optional) Weight of the preferred affinity term, ignored in Required mode
+	// Default: 100
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
+type DatabaseAutoscaling struct {
+	// Minimum number of Database nodes
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	MinNodes int32 `json:"minNodes"`
+
+	// Maximum number of Database nodes
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	MaxNodes int32 `json:"maxNodes"`
+
+	// (Optional) Target average CPU utilization of Database nodes,
+	// in percent of requested CPU
+	// Default: 80
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+type ConnectionSecretSpec struct {
+	// Whether the connection Secret should be created
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// (Optional) Name of the connection Secret
+	// Default: <database-name>-connection
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 type DatabaseClusterSpec struct {
 	// YDB Storage cluster reference
 	// +required
-	StorageClusterRef NamespacedRef `json:"storageClusterRef"`
+	StorageClusterRef StorageClusterRef `json:"storageClusterRef"`
 
 	// YDB Storage Node broker address
 	// +optional
@@ -43,6 +169,13 @@ type DatabaseClusterSpec struct {
 	// +optional
 	Path string `json:"path,omitempty"`
 
+	// (Optional) Path of an existing parent database to create a nested database in
+	// Database path becomes /<spec.parentPath>/<metadata.name> when spec.path is not set
+	// +kubebuilder:validation:Pattern:=^/[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?(/[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?)*$
+	// +kubebuilder:validation:MaxLength:=255
+	// +optional
+	ParentPath string `json:"parentPath,omitempty"`
+
 	// (Optional) If specified, created database will be "serverless".
 	// +optional
 	ServerlessResources *ServerlessDatabaseResources `json:"serverlessResources,omitempty"`
@@ -55,7 +188,8 @@ type DatabaseClusterSpec struct {
 	// +optional
 	Image *PodImage `json:"image,omitempty"`
 
-	// (Optional) YDBVersion sets the explicit version of the YDB image
+	// (Optional) YDBVersion sets the explicit version of the YDB image,
+	// ignored for images pinned by digest
 	// Default: ""
 	// +optional
 	YDBVersion string `json:"version,omitempty"`
@@ -66,10 +200,21 @@ type DatabaseClusterSpec struct {
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
 
+	// (Optional) List of additional containers run alongside YDB container
+	// in the pod, e.g. log shippers or proxies. Names must not clash with
+	// containers managed by the operator.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
 	// YDB configuration in YAML format. Will be applied on top of generated one in internal/configuration
 	// +optional
 	Configuration string `json:"configuration"`
 
+	// (Optional) Directory inside the container where the configuration is mounted (read-only)
+	// Default: /opt/ydb/cfg
+	// +optional
+	ConfigDir string `json:"configDir,omitempty"`
+
 	// (Optional) Storage services parameter overrides
 	// Default: (not specified)
 	// +optional
@@ -79,8 +224,20 @@ type DatabaseClusterSpec struct {
 	// +optional
 	Datastreams *DatastreamsConfig `json:"datastreams,omitempty"`
 
+	// (Optional) PostgreSQL wire protocol endpoint of the Database, which
+	// allows to connect with standard PostgreSQL drivers
+	// +optional
+	Postgres *PostgresConfig `json:"postgres,omitempty"`
+
+	// (Optional) Feature flags of the tenant, set in `feature_flags` of
+	// database nodes configuration over the ones of `configuration`.
+	// Only flags known to the operator are accepted.
+	// +optional
+	FeatureFlags map[string]bool `json:"featureFlags,omitempty"`
+
 	// The state of the Database processes.
 	// `true` means all the Database Pods are being killed, but the Database resource is persisted.
+	// Tenant is kept in CMS while paused and Pods are restored to `nodes` on resume.
 	// `false` means the default state of the system, all Pods running.
 	// +kubebuilder:default:=false
 	// +optional
@@ -114,6 +271,52 @@ type DatabaseClusterSpec struct {
 	// Only `hostPath` volume type is supported for now.
 	// +optional
 	Volumes []*corev1.Volume `json:"volumes,omitempty"`
+
+	// (Optional) Local scratch space for query spilling, mounted into
+	// every database pod at `/opt/ydb/spilling` which is set as spilling
+	// root in `table_service_config` unless configured explicitly.
+	// Default: (not specified)
+	// +optional
+	ScratchSpace *ScratchSpaceSpec `json:"scratchSpace,omitempty"`
+
+	// (Optional) Number of old revisions of the Database StatefulSet kept
+	// to allow rollback
+	// Default: 3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// (Optional) Whether the service account token is mounted into database pods.
+	// Database nodes do not use Kubernetes API, the token may be disabled for
+	// security hardening.
+	// Default: (not specified, cluster default is used)
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// (Optional) Set `GOMAXPROCS` environment variable of the database container
+	// and sidecars to their CPU limit rounded up to a whole CPU, e.g. `2`
+	// for `1500m`, so that processes respect cgroup CPU quota. Containers
+	// without CPU limit and explicitly set `GOMAXPROCS` are not changed.
+	// Default: false
+	// +optional
+	InjectGOMAXPROCS bool `json:"injectGOMAXPROCS,omitempty"`
+}
+
+type ScratchSpaceSpec struct {
+	// (Optional) Storage medium of the emptyDir volume, e.g. `Memory`
+	// Default: "" (node default medium)
+	// +optional
+	Medium corev1.StorageMedium `json:"medium,omitempty"`
+
+	// (Optional) Size limit of the emptyDir volume
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// (Optional) Template of the PersistentVolumeClaim used instead of emptyDir.
+	// Can not be combined with `medium` and `sizeLimit` and can not be
+	// changed after the Database is created.
+	// +optional
+	VolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate,omitempty"`
 }
 
 type DatabaseNodeSpec struct {
@@ -139,6 +342,14 @@ type DatabaseNodeSpec struct {
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 
+	// (Optional) Pool of Kubernetes nodes dedicated to Database nodes. Pods
+	// are scheduled only to Kubernetes nodes labeled with the pool name and
+	// Database nodes are registered with `node_pool` label. Kubernetes nodes
+	// of the pool must exist in the cluster. Not applied to remote nodeSets.
+	// Default: (not specified)
+	// +optional
+	NodePool *NodePool `json:"nodePool,omitempty"`
+
 	// (Optional) If specified, the pod's tolerations.
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
@@ -157,6 +368,12 @@ type DatabaseNodeSpec struct {
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// (Optional) If specified, the pod is dispatched by the specified scheduler,
+	// e.g. Volcano or YuniKorn for gang scheduling.
+	// Default: (not specified), default scheduler is used
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
 	// (Optional) If specified, the pod's terminationGracePeriodSeconds.
 	// +optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
@@ -170,6 +387,26 @@ type DatabaseNodeSpec struct {
 	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
 }
 
+type NodePool struct {
+	// Name of the pool, value of the Kubernetes node label
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// (Optional) Key of the Kubernetes node label with the name of the pool
+	// Default: ydb.tech/node-pool
+	// +optional
+	LabelKey string `json:"labelKey,omitempty"`
+}
+
+// GetLabelKey returns key of the Kubernetes node label selecting the pool
+func (p *NodePool) GetLabelKey() string {
+	if p.LabelKey != "" {
+		return p.LabelKey
+	}
+	return DefaultNodePoolLabelKey
+}
+
 type DatabaseResources struct {
 	// (Optional) Database container resource limits. Any container limits
 	// can be specified.
@@ -182,6 +419,44 @@ type DatabaseResources struct {
 	// throughput, IOPS etc.
 	// +required
 	StorageUnits []StorageUnit `json:"storageUnits,omitempty"`
+
+	// (Optional) Computational units allocated by CMS for the tenant, only
+	// supported by dedicated database and can not be changed after creation
+	// +optional
+	ComputationalUnits []ComputationalUnit `json:"computationalUnits,omitempty"`
+
+	// (Optional) Quotas of schema operations of the tenant, only supported
+	// by dedicated database, changes are applied with AlterDatabase
+	// +optional
+	SchemaOperationQuotas []SchemaOperationQuota `json:"schemaOperationQuotas,omitempty"`
+}
+
+type ComputationalUnit struct {
+	// Kind of the computational unit. Determine main
+	// unit parameters like available memory, CPU, etc.
+	// +required
+	UnitKind string `json:"unitKind"`
+
+	// (Optional) Availability zone all units should be located in
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// Number of units in this set.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Count uint64 `json:"count"`
+}
+
+type SchemaOperationQuota struct {
+	// Number of schema operations allowed per bucketSeconds
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	BucketSize uint64 `json:"bucketSize"`
+
+	// Duration of the bucket in seconds
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	BucketSeconds uint64 `json:"bucketSeconds"`
 }
 
 type ServerlessDatabaseResources struct {
@@ -206,11 +481,87 @@ type StorageUnit struct {
 type DatabaseStatus struct {
 	State      constants.ClusterState `json:"state"`
 	Conditions []metav1.Condition     `json:"conditions,omitempty"`
+
+	// Generation of the Database spec which was fully reconciled, Ready
+	// condition is not True until the latest generation is reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Checksum of the rendered configuration mounted into database nodes
+	// (`config.yaml` key of the Database ConfigMap, or of the Storage one
+	// when Database has no own configuration)
+	// +optional
+	ObservedConfigHash string `json:"observedConfigHash,omitempty"`
+
+	// Number of ready database nodes, reported by the scale subresource
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Label selector of database pods, reported by the scale subresource
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// Number of ready database nodes of the StatefulSet
+	// +optional
+	ReadyNodes int32 `json:"readyNodes,omitempty"`
+
+	// Number of database nodes created by the StatefulSet
+	// +optional
+	TotalNodes int32 `json:"totalNodes,omitempty"`
+
+	// Connection string of PostgreSQL endpoint, set when `spec.postgres`
+	// is enabled
+	// +optional
+	PostgresConnectionString string `json:"postgresConnectionString,omitempty"`
+
+	// Stage of the tenant creation operation in CMS, set while Database
+	// is initialized
+	// +optional
+	// +kubebuilder:validation:Enum=Submitted;Allocating;Ready;Failed
+	TenantCreationProgress TenantCreationProgress `json:"tenantCreationProgress,omitempty"`
+
+	// Checksum of `spec.resources.schemaOperationQuotas` applied to the
+	// tenant in CMS
+	// +optional
+	AppliedSchemaOperationQuotasHash string `json:"appliedSchemaOperationQuotasHash,omitempty"`
+
+	// Objects created by the operator for the Database, e.g. Services,
+	// ConfigMap and StatefulSet
+	// +optional
+	ManagedResources []ManagedResource `json:"managedResources,omitempty"`
+
+	// Version of the encryption key from `spec.encryption.key` used by
+	// Database nodes. Version is increased when content of the key changes,
+	// previous versions are kept for decryption
+	// +optional
+	EncryptionKeyVersion int32 `json:"encryptionKeyVersion,omitempty"`
+
+	// Checksums of `spec.initScripts` which were executed on the Database
+	// and are not executed again
+	// +optional
+	AppliedInitScripts []string `json:"appliedInitScripts,omitempty"`
 }
 
+// TenantCreationProgress is a stage of the CMS operation creating tenant
+type TenantCreationProgress string
+
+const (
+	// Tenant creation request is accepted by CMS
+	TenantCreationSubmitted TenantCreationProgress = "Submitted"
+	// CMS operation is still running, resources of tenant are allocated
+	TenantCreationAllocating TenantCreationProgress = "Allocating"
+	// CMS operation is completed successfully
+	TenantCreationReady TenantCreationProgress = "Ready"
+	// CMS operation is completed with error
+	TenantCreationFailed TenantCreationProgress = "Failed"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.nodes,statuspath=.status.replicas,selectorpath=.status.selector
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of this DB"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyNodes",description="Number of ready database nodes"
+//+kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.totalNodes",description="Number of database nodes"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Database is the Schema for the databases API
@@ -238,6 +589,9 @@ type EncryptionConfig struct {
 	// +required
 	Enabled bool `json:"enabled"`
 
+	// (Optional) Reference to Secret key with encryption key. When content
+	// of the key changes, it is rotated: new key is used for encryption and
+	// previous keys are kept for decryption of existing data
 	// +optional
 	Key *corev1.SecretKeySelector `json:"key,omitempty"`
 
@@ -251,11 +605,33 @@ type DatastreamsConfig struct {
 	Enabled bool `json:"enabled"`
 }
 
+// PostgresConfig enables PostgreSQL wire protocol endpoint of database nodes
+type PostgresConfig struct {
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// (Optional) Port of PostgreSQL wire protocol endpoint
+	// Default: 5432
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// GetPort returns port of PostgreSQL endpoint, 5432 unless overridden
+func (c *PostgresConfig) GetPort() int32 {
+	if c.Port == 0 {
+		return PostgresPort
+	}
+	return c.Port
+}
+
 type DatabaseServices struct {
 	GRPC         GRPCService         `json:"grpc,omitempty"`
 	Interconnect InterconnectService `json:"interconnect,omitempty"`
 	Status       StatusService       `json:"status,omitempty"`
 	Datastreams  DatastreamsService  `json:"datastreams,omitempty"`
+	Postgres     PostgresService     `json:"postgres,omitempty"`
 }
 
 func init() {
@@ -268,3 +644,37 @@ func (r *Database) AnyCertificatesAdded() bool {
 		r.Spec.Service.Interconnect.TLSConfiguration.Enabled ||
 		r.Spec.Service.Status.TLSConfiguration.Enabled
 }
+
+// GetReferencedSecrets lists names of Secrets referenced from Database spec:
+// additional secrets, TLS certificates, image pull secret and encryption key.
+func (r *Database) GetReferencedSecrets() []string {
+	var names []string
+	for _, secret := range r.Spec.Secrets {
+		names = append(names, secret.Name)
+	}
+	if r.Spec.Service != nil {
+		names = append(names, r.Spec.Service.GRPC.TLSConfiguration.secretNames()...)
+		names = append(names, r.Spec.Service.Interconnect.TLSConfiguration.secretNames()...)
+		names = append(names, r.Spec.Service.Status.TLSConfiguration.secretNames()...)
+		names = append(names, r.Spec.Service.Datastreams.TLSConfiguration.secretNames()...)
+	}
+	if r.Spec.Image != nil && r.Spec.Image.PullSecret != nil {
+		names = append(names, *r.Spec.Image.PullSecret)
+	}
+	if r.Spec.Encryption != nil && r.Spec.Encryption.Key != nil {
+		names = append(names, r.Spec.Encryption.Key.Name)
+	}
+	return uniqueSecretNames(names)
+}
+
+// GetReferencedConfigMaps returns names of ConfigMaps which initScripts
+// of Database are read from
+func (r *Database) GetReferencedConfigMaps() []string {
+	var names []string
+	for _, initScript := range r.Spec.InitScripts {
+		if initScript.ConfigMapKeyRef != nil {
+			names = append(names, initScript.ConfigMapKeyRef.Name)
+		}
+	}
+	return uniqueSecretNames(names)
+}
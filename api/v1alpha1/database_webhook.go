@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -30,10 +33,53 @@ func (r *Database) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Complete()
 }
 
+func (r *DatabaseClusterSpec) GetConfigDir() string {
+	if r.ConfigDir != "" {
+		return r.ConfigDir
+	}
+	return ConfigDir
+}
+
+// HasOwnConfiguration reports whether database nodes use their own ConfigMap
+// instead of the Storage one. Scratch space requires own ConfigMap to set
+// the spilling root, Storage in another cluster has no ConfigMap in the
+// cluster of Database.
+func (r *DatabaseClusterSpec) HasOwnConfiguration() bool {
+	return r.Configuration != "" || r.ScratchSpace != nil || r.IsPostgresEnabled() || len(r.FeatureFlags) > 0 ||
+		r.StorageClusterRef.KubeconfigSecretRef != nil
+}
+
+// GetOperatorStorageEndpoint returns endpoint of Storage which is used by
+// operator for requests on behalf of Database, e.g. CMS requests
+func (r *DatabaseClusterSpec) GetOperatorStorageEndpoint() string {
+	if r.StorageClusterRef.EndpointOverride != "" {
+		return r.StorageClusterRef.EndpointOverride
+	}
+	return r.StorageEndpoint
+}
+
+// IsPostgresEnabled reports whether PostgreSQL endpoint is enabled
+func (r *DatabaseClusterSpec) IsPostgresEnabled() bool {
+	return r.Postgres != nil && r.Postgres.Enabled
+}
+
+// GetPostgresConnectionString returns libpq connection string of the
+// PostgreSQL endpoint served by the Database Service
+func (r *Database) GetPostgresConnectionString() string {
+	return fmt.Sprintf("host=%s port=%d dbname=%s",
+		fmt.Sprintf(PostgresServiceFQDNFormat, r.Name, r.Namespace),
+		r.Spec.Postgres.GetPort(),
+		r.GetDatabasePath(),
+	)
+}
+
 func (r *Database) GetDatabasePath() string {
 	if r.Spec.Path != "" {
 		return r.Spec.Path
 	}
+	if r.Spec.ParentPath != "" {
+		return fmt.Sprintf("%s/%s", r.Spec.ParentPath, r.Name)
+	}
 	return r.GetLegacyDatabasePath()
 }
 
@@ -68,6 +114,10 @@ func (r *DatabaseDefaulter) Default(ctx context.Context, obj runtime.Object) err
 		}
 	}
 
+	if database.Spec.SourceDatabaseRef != nil && database.Spec.SourceDatabaseRef.Namespace == "" {
+		database.Spec.SourceDatabaseRef.Namespace = database.Namespace
+	}
+
 	if database.Spec.Image == nil {
 		database.Spec.Image = &PodImage{}
 	}
@@ -81,7 +131,7 @@ func (r *DatabaseDefaulter) Default(ctx context.Context, obj runtime.Object) err
 	}
 
 	if database.Spec.Image.PullPolicyName == nil {
-		policy := v1.PullIfNotPresent
+		policy := database.Spec.Image.defaultPullPolicy()
 		database.Spec.Image.PullPolicyName = &policy
 	}
 
@@ -115,7 +165,7 @@ func (r *DatabaseDefaulter) Default(ctx context.Context, obj runtime.Object) err
 	}
 
 	if database.Spec.Path == "" {
-		database.Spec.Path = database.GetLegacyDatabasePath()
+		database.Spec.Path = database.GetDatabasePath()
 	}
 
 	if database.Spec.Encryption == nil {
@@ -139,6 +189,12 @@ func (r *DatabaseDefaulter) Default(ctx context.Context, obj runtime.Object) err
 		}
 	}
 
+	// Storage located in another cluster is not available to the webhook,
+	// endpoint and configuration are left as is
+	if database.Spec.StorageClusterRef.KubeconfigSecretRef != nil {
+		return nil
+	}
+
 	storage := &Storage{}
 	err := r.Client.Get(ctx, types.NamespacedName{
 		Namespace: database.Spec.StorageClusterRef.Namespace,
@@ -171,12 +227,70 @@ var _ webhook.Validator = &Database{}
 func (r *Database) ValidateCreate() error {
 	databaselog.Info("validate create", "name", r.Name)
 
+	if err := validateDurationAnnotations(r.Annotations); err != nil {
+		return err
+	}
+
+	if r.Spec.Domain != "" {
+		if err := validateDomainName(r.Spec.Domain); err != nil {
+			return err
+		}
+	}
+
 	if r.Spec.Domain != "" && r.Spec.Path != "" {
 		if !strings.HasPrefix(r.Spec.Path, fmt.Sprintf("/%s", r.Spec.Domain)) {
 			return fmt.Errorf("incorrect database path, must start with domain: \"/%s\"", r.Spec.Domain)
 		}
 	}
 
+	if err := r.validateParentPath(); err != nil {
+		return err
+	}
+
+	if err := r.validateScratchSpace(); err != nil {
+		return err
+	}
+
+	if err := r.validatePostgres(); err != nil {
+		return err
+	}
+
+	if err := r.validateFeatureFlags(); err != nil {
+		return err
+	}
+
+	if err := r.validateStorageClusterRef(); err != nil {
+		return err
+	}
+
+	if err := r.validateNodePool(); err != nil {
+		return err
+	}
+
+	if err := validateAdditionalResources(r.Name, r.Namespace, r.Spec.AdditionalResources); err != nil {
+		return err
+	}
+
+	if err := validateSidecars(r.Spec.Sidecars, r.Spec.InitContainers, DatabaseContainerName); err != nil {
+		return err
+	}
+
+	if err := validateImageDigest(r.Spec.Image); err != nil {
+		return err
+	}
+
+	if err := r.validateAutoscaling(); err != nil {
+		return err
+	}
+
+	if err := r.validateSourceDatabase(); err != nil {
+		return err
+	}
+
+	if err := r.validateTenantOptions(); err != nil {
+		return err
+	}
+
 	if r.Spec.Resources == nil && r.Spec.SharedResources == nil && r.Spec.ServerlessResources == nil {
 		return errors.New("incorrect database resources configuration, must be one of: Resources, SharedResources, ServerlessResources")
 	}
@@ -207,10 +321,198 @@ func (r *Database) ValidateCreate() error {
 	return nil
 }
 
+func (r *Database) validateParentPath() error {
+	if r.Spec.ParentPath == "" {
+		return nil
+	}
+
+	domainPath := fmt.Sprintf("/%s", r.Spec.Domain)
+	if r.Spec.ParentPath != domainPath && !strings.HasPrefix(r.Spec.ParentPath, domainPath+"/") {
+		return fmt.Errorf("incorrect database parentPath, must start with domain: \"%s\"", domainPath)
+	}
+
+	// Database must be a direct child of the parent, otherwise intermediate
+	// directories would be created without a database owning them
+	parent, name := path.Split(r.GetDatabasePath())
+	if strings.TrimSuffix(parent, "/") != r.Spec.ParentPath || name == "" {
+		return fmt.Errorf("incorrect database path, must be a direct child of parentPath: \"%s\"", r.Spec.ParentPath)
+	}
+
+	return nil
+}
+
+// validateTenantOptions checks CMS options of the tenant, which are only
+// supported by dedicated database
+func (r *Database) validateTenantOptions() error {
+	if r.Spec.SharedResources != nil {
+		if len(r.Spec.SharedResources.ComputationalUnits) > 0 {
+			return errors.New("field 'spec.sharedResources.computationalUnits' is not supported, use 'spec.resources'")
+		}
+		if len(r.Spec.SharedResources.SchemaOperationQuotas) > 0 {
+			return errors.New("field 'spec.sharedResources.schemaOperationQuotas' is not supported, use 'spec.resources'")
+		}
+	}
+	if r.Spec.Resources == nil {
+		return nil
+	}
+
+	for i, unit := range r.Spec.Resources.ComputationalUnits {
+		if unit.UnitKind == "" {
+			return fmt.Errorf("field 'spec.resources.computationalUnits[%d].unitKind' must be specified", i)
+		}
+		if unit.Count == 0 {
+			return fmt.Errorf("field 'spec.resources.computationalUnits[%d].count' must be positive", i)
+		}
+	}
+	for i, quota := range r.Spec.Resources.SchemaOperationQuotas {
+		if quota.BucketSize == 0 {
+			return fmt.Errorf("field 'spec.resources.schemaOperationQuotas[%d].bucketSize' must be positive", i)
+		}
+		if quota.BucketSeconds == 0 {
+			return fmt.Errorf("field 'spec.resources.schemaOperationQuotas[%d].bucketSeconds' must be positive", i)
+		}
+	}
+
+	return nil
+}
+
+func (r *Database) getComputationalUnits() []ComputationalUnit {
+	if r.Spec.Resources == nil || len(r.Spec.Resources.ComputationalUnits) == 0 {
+		return nil
+	}
+	return r.Spec.Resources.ComputationalUnits
+}
+
+func (r *Database) validateAutoscaling() error {
+	if r.Spec.Autoscaling == nil {
+		return nil
+	}
+
+	if r.Spec.NodeSets != nil {
+		return errors.New("field 'spec.autoscaling' is not supported with 'spec.nodeSets'")
+	}
+
+	if r.Spec.ServerlessResources != nil {
+		return errors.New("field 'spec.autoscaling' is not supported for serverless Database")
+	}
+
+	if r.Spec.Autoscaling.MinNodes > r.Spec.Autoscaling.MaxNodes {
+		return fmt.Errorf("autoscaling minNodes %d is greater than maxNodes %d",
+			r.Spec.Autoscaling.MinNodes, r.Spec.Autoscaling.MaxNodes)
+	}
+
+	return nil
+}
+
+func (r *Database) validateSourceDatabase() error {
+	if r.Spec.SourceDatabaseRef == nil {
+		return nil
+	}
+
+	if r.Spec.ServerlessResources != nil {
+		return errors.New("field 'spec.sourceDatabaseRef' is not supported for serverless Database")
+	}
+
+	namespace := r.Spec.SourceDatabaseRef.Namespace
+	if namespace == "" {
+		namespace = r.Namespace
+	}
+	if r.Spec.SourceDatabaseRef.Name == r.Name && namespace == r.Namespace {
+		return errors.New("database cannot be cloned from itself")
+	}
+
+	return nil
+}
+
+func (r *Database) validatePostgres() error {
+	if !r.Spec.IsPostgresEnabled() {
+		return nil
+	}
+
+	if r.Spec.ServerlessResources != nil {
+		return errors.New("field 'spec.postgres' is not supported for serverless Database")
+	}
+
+	switch port := r.Spec.Postgres.GetPort(); port {
+	case GRPCPort, InterconnectPort, StatusPort:
+		return fmt.Errorf("postgres port %d conflicts with ports of database nodes", port)
+	case DatastreamsPort:
+		if r.Spec.Datastreams != nil && r.Spec.Datastreams.Enabled {
+			return fmt.Errorf("postgres port %d conflicts with datastreams port", port)
+		}
+	}
+
+	return nil
+}
+
+// validateStorageClusterRef checks that Storage located in another cluster
+// is reachable by explicit endpoint, as endpoint is not defaulted for it
+func (r *Database) validateStorageClusterRef() error {
+	if r.Spec.StorageClusterRef.KubeconfigSecretRef == nil {
+		return nil
+	}
+
+	if r.Spec.StorageEndpoint == "" {
+		return errors.New("storageEndpoint must be set for Storage referenced with kubeconfigSecretRef")
+	}
+
+	if r.Spec.StorageAffinity != nil {
+		return errors.New("storageAffinity is not supported for Storage referenced with kubeconfigSecretRef")
+	}
+
+	return nil
+}
+
+// validateNodePool checks that node pool is usable as a node selector,
+// existence of Kubernetes nodes of the pool is checked by controller
+func (r *Database) validateNodePool() error {
+	if r.Spec.NodePool == nil {
+		return nil
+	}
+
+	if errs := validation.IsQualifiedName(r.Spec.NodePool.GetLabelKey()); len(errs) > 0 {
+		return fmt.Errorf("invalid 'spec.nodePool.labelKey': %s", strings.Join(errs, "; "))
+	}
+
+	if errs := validation.IsValidLabelValue(r.Spec.NodePool.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid 'spec.nodePool.name': %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// validateFeatureFlags rejects unknown feature flags, which would be
+// silently ignored by database nodes
+func (r *Database) validateFeatureFlags() error {
+	for name := range r.Spec.FeatureFlags {
+		if _, ok := KnownFeatureFlags[name]; !ok {
+			return fmt.Errorf("unknown feature flag '%s' in 'spec.featureFlags', "+
+				"use 'spec.configuration' to set flags unknown to operator", name)
+		}
+	}
+	return nil
+}
+
+func (r *Database) validateScratchSpace() error {
+	if r.Spec.ScratchSpace == nil || r.Spec.ScratchSpace.VolumeClaimTemplate == nil {
+		return nil
+	}
+
+	if r.Spec.ScratchSpace.Medium != "" || r.Spec.ScratchSpace.SizeLimit != nil {
+		return errors.New("fields 'spec.scratchSpace.medium' and 'spec.scratchSpace.sizeLimit' are not supported with 'spec.scratchSpace.volumeClaimTemplate'")
+	}
+
+	return nil
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Database) ValidateUpdate(old runtime.Object) error {
 	databaselog.Info("validate update", "name", r.Name)
 
+	if err := validateDurationAnnotations(r.Annotations); err != nil {
+		return err
+	}
+
 	oldDatabase, _ := old.(*Database)
 	if r.Spec.Domain != oldDatabase.Spec.Domain {
 		return errors.New("database domain cannot be changed")
@@ -220,6 +522,64 @@ func (r *Database) ValidateUpdate(old runtime.Object) error {
 		return errors.New("database path cannot be changed")
 	}
 
+	if oldDatabase.Spec.ParentPath != r.Spec.ParentPath {
+		return errors.New("database parentPath cannot be changed")
+	}
+
+	if !equality.Semantic.DeepEqual(oldDatabase.Spec.SourceDatabaseRef, r.Spec.SourceDatabaseRef) {
+		return errors.New("database sourceDatabaseRef cannot be changed")
+	}
+
+	if err := r.validateScratchSpace(); err != nil {
+		return err
+	}
+
+	if err := r.validatePostgres(); err != nil {
+		return err
+	}
+
+	if err := r.validateFeatureFlags(); err != nil {
+		return err
+	}
+
+	if err := r.validateStorageClusterRef(); err != nil {
+		return err
+	}
+
+	if err := r.validateNodePool(); err != nil {
+		return err
+	}
+
+	if err := validateAdditionalResources(r.Name, r.Namespace, r.Spec.AdditionalResources); err != nil {
+		return err
+	}
+
+	if err := validateSidecars(r.Spec.Sidecars, r.Spec.InitContainers, DatabaseContainerName); err != nil {
+		return err
+	}
+
+	if err := validateImageDigest(r.Spec.Image); err != nil {
+		return err
+	}
+
+	if err := r.validateAutoscaling(); err != nil {
+		return err
+	}
+
+	if err := r.validateTenantOptions(); err != nil {
+		return err
+	}
+
+	// Computational units are allocated by CMS once on tenant creation
+	if !equality.Semantic.DeepEqual(oldDatabase.getComputationalUnits(), r.getComputationalUnits()) {
+		return errors.New("field 'spec.resources.computationalUnits' cannot be changed")
+	}
+
+	// StatefulSet volumeClaimTemplates are immutable
+	if !equality.Semantic.DeepEqual(oldDatabase.getScratchSpaceVolumeClaimTemplate(), r.getScratchSpaceVolumeClaimTemplate()) {
+		return errors.New("field 'spec.scratchSpace.volumeClaimTemplate' cannot be changed")
+	}
+
 	if r.Spec.NodeSets != nil {
 		var nodesInSetsCount int32
 		for _, nodeSetInline := range r.Spec.NodeSets {
@@ -238,6 +598,13 @@ func (r *Database) ValidateUpdate(old runtime.Object) error {
 	return nil
 }
 
+func (r *Database) getScratchSpaceVolumeClaimTemplate() *v1.PersistentVolumeClaimSpec {
+	if r.Spec.ScratchSpace == nil {
+		return nil
+	}
+	return r.Spec.ScratchSpace.VolumeClaimTemplate
+}
+
 func (r *Database) ValidateDelete() error {
 	if r.Status.State != DatabasePaused {
 		return fmt.Errorf("database deletion is only possible from `Paused` state, current state %v", r.Status.State)
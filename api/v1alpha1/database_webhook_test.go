@@ -0,0 +1,229 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+func newTestDatabase() *v1alpha1.Database {
+	database := &v1alpha1.Database{}
+	database.Name = "database"
+	database.Namespace = "ydb"
+	database.Spec.Domain = "Root"
+	database.Spec.Nodes = 1
+	database.Spec.Resources = &v1alpha1.DatabaseResources{}
+	// Skip lookup of monitoring CRDs which requires a manager
+	database.Spec.Monitoring = &v1alpha1.MonitoringOptions{}
+	return database
+}
+
+var _ = Describe("Database webhook", func() {
+	Context("scratch space", func() {
+		It("rejects emptyDir settings together with volumeClaimTemplate", func() {
+			database := newTestDatabase()
+			sizeLimit := resource.MustParse("10Gi")
+			database.Spec.ScratchSpace = &v1alpha1.ScratchSpaceSpec{
+				SizeLimit:           &sizeLimit,
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{},
+			}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("not supported")))
+		})
+
+		It("accepts emptyDir scratch space", func() {
+			database := newTestDatabase()
+			sizeLimit := resource.MustParse("10Gi")
+			database.Spec.ScratchSpace = &v1alpha1.ScratchSpaceSpec{
+				Medium:    corev1.StorageMediumMemory,
+				SizeLimit: &sizeLimit,
+			}
+			Expect(database.ValidateCreate()).To(Succeed())
+		})
+
+		It("rejects adding volumeClaimTemplate to existing Database", func() {
+			oldDatabase := newTestDatabase()
+			database := newTestDatabase()
+			database.Spec.ScratchSpace = &v1alpha1.ScratchSpaceSpec{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{},
+			}
+			Expect(database.ValidateUpdate(oldDatabase)).To(MatchError(ContainSubstring("cannot be changed")))
+		})
+
+		It("allows changing emptyDir scratch space of existing Database", func() {
+			oldDatabase := newTestDatabase()
+			database := newTestDatabase()
+			database.Spec.ScratchSpace = &v1alpha1.ScratchSpaceSpec{
+				Medium: corev1.StorageMediumMemory,
+			}
+			Expect(database.ValidateUpdate(oldDatabase)).To(Succeed())
+		})
+	})
+
+	Context("parent path", func() {
+		It("accepts direct child of parentPath", func() {
+			database := newTestDatabase()
+			database.Spec.ParentPath = "/Root/parent"
+			Expect(database.ValidateCreate()).To(Succeed())
+		})
+
+		It("rejects parentPath outside of domain", func() {
+			database := newTestDatabase()
+			database.Spec.ParentPath = "/Other/parent"
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("must start with domain")))
+		})
+
+		It("rejects parentPath sharing only a prefix with domain", func() {
+			database := newTestDatabase()
+			database.Spec.ParentPath = "/RootOther"
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("must start with domain")))
+		})
+
+		It("rejects path which is not a direct child of parentPath", func() {
+			database := newTestDatabase()
+			database.Spec.ParentPath = "/Root/parent"
+			database.Spec.Path = "/Root/parent/dir/database"
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("direct child")))
+		})
+
+		It("rejects changing parentPath of existing Database", func() {
+			oldDatabase := newTestDatabase()
+			oldDatabase.Spec.ParentPath = "/Root/parent"
+			oldDatabase.Spec.Path = "/Root/parent/database"
+			database := newTestDatabase()
+			database.Spec.ParentPath = "/Root"
+			database.Spec.Path = "/Root/parent/database"
+			Expect(database.ValidateUpdate(oldDatabase)).To(MatchError(ContainSubstring("parentPath cannot be changed")))
+		})
+	})
+
+	Context("autoscaling", func() {
+		It("accepts autoscaling bounds", func() {
+			database := newTestDatabase()
+			database.Spec.Autoscaling = &v1alpha1.DatabaseAutoscaling{MinNodes: 1, MaxNodes: 3}
+			Expect(database.ValidateCreate()).To(Succeed())
+		})
+
+		It("rejects minNodes greater than maxNodes", func() {
+			database := newTestDatabase()
+			database.Spec.Autoscaling = &v1alpha1.DatabaseAutoscaling{MinNodes: 4, MaxNodes: 3}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("greater than maxNodes")))
+		})
+
+		It("rejects autoscaling together with nodeSets", func() {
+			database := newTestDatabase()
+			database.Spec.Autoscaling = &v1alpha1.DatabaseAutoscaling{MinNodes: 1, MaxNodes: 3}
+			database.Spec.NodeSets = []v1alpha1.DatabaseNodeSetSpecInline{}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("not supported with 'spec.nodeSets'")))
+		})
+	})
+
+	Context("postgres", func() {
+		It("rejects postgres port conflicting with ports of database nodes", func() {
+			database := newTestDatabase()
+			database.Spec.Postgres = &v1alpha1.PostgresConfig{Enabled: true}
+			Expect(database.ValidateCreate()).To(Succeed())
+
+			database.Spec.Postgres.Port = v1alpha1.GRPCPort
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("conflicts with ports of database nodes")))
+		})
+
+		It("enables postgres endpoint in database configuration", func() {
+			database := newTestDatabase()
+			database.Spec.Postgres = &v1alpha1.PostgresConfig{Enabled: true, Port: 5433}
+			storage := &v1alpha1.Storage{}
+			storage.Spec.Configuration = "feature_flags:\n  enable_pg_syntax: false\n"
+
+			configuration, err := v1alpha1.BuildConfiguration(storage, database)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(configuration)).To(ContainSubstring("listening_port: 5433"))
+			Expect(string(configuration)).To(ContainSubstring("enable_pg_syntax: false"))
+			Expect(database.GetPostgresConnectionString()).To(Equal(
+				"host=database-postgres.ydb.svc.cluster.local port=5433 dbname=/Root/database",
+			))
+		})
+	})
+
+	Context("tenant options", func() {
+		It("rejects schema operation quotas of shared database", func() {
+			database := newTestDatabase()
+			database.Spec.Resources = nil
+			database.Spec.SharedResources = &v1alpha1.DatabaseResources{
+				SchemaOperationQuotas: []v1alpha1.SchemaOperationQuota{{BucketSize: 10, BucketSeconds: 60}},
+			}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("is not supported")))
+		})
+
+		It("rejects empty bucket of schema operation quota", func() {
+			database := newTestDatabase()
+			database.Spec.Resources.SchemaOperationQuotas = []v1alpha1.SchemaOperationQuota{{BucketSize: 10}}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("bucketSeconds' must be positive")))
+		})
+
+		It("allows changing schema operation quotas of existing Database", func() {
+			oldDatabase := newTestDatabase()
+			database := newTestDatabase()
+			database.Spec.Resources.SchemaOperationQuotas = []v1alpha1.SchemaOperationQuota{{BucketSize: 10, BucketSeconds: 60}}
+			Expect(database.ValidateUpdate(oldDatabase)).To(Succeed())
+		})
+
+		It("rejects changing computational units of existing Database", func() {
+			oldDatabase := newTestDatabase()
+			database := newTestDatabase()
+			database.Spec.Resources.ComputationalUnits = []v1alpha1.ComputationalUnit{{UnitKind: "slot", Count: 1}}
+			Expect(database.ValidateUpdate(oldDatabase)).To(MatchError(ContainSubstring("cannot be changed")))
+		})
+	})
+
+	Context("feature flags", func() {
+		It("rejects unknown feature flag", func() {
+			database := newTestDatabase()
+			database.Spec.FeatureFlags = map[string]bool{"enable_colum_tables": true}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("unknown feature flag 'enable_colum_tables'")))
+		})
+
+		It("sets feature flags in database configuration over configured ones", func() {
+			database := newTestDatabase()
+			database.Spec.FeatureFlags = map[string]bool{"enable_views": true, "enable_pg_syntax": false}
+			Expect(database.ValidateCreate()).To(Succeed())
+			Expect(database.Spec.HasOwnConfiguration()).To(BeTrue())
+
+			storage := &v1alpha1.Storage{}
+			storage.Spec.Configuration = "feature_flags:\n  enable_pg_syntax: true\n  enable_temp_tables: true\n"
+			configuration, err := v1alpha1.BuildConfiguration(storage, database)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(configuration)).To(ContainSubstring("enable_views: true"))
+			Expect(string(configuration)).To(ContainSubstring("enable_pg_syntax: false"))
+			Expect(string(configuration)).To(ContainSubstring("enable_temp_tables: true"))
+		})
+	})
+
+	Context("storage in another cluster", func() {
+		It("requires storageEndpoint for Storage referenced with kubeconfig", func() {
+			database := newTestDatabase()
+			database.Spec.StorageClusterRef.KubeconfigSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "storage-kubeconfig"},
+				Key:                  "kubeconfig",
+			}
+			Expect(database.ValidateCreate()).To(MatchError(ContainSubstring("storageEndpoint must be set")))
+
+			database.Spec.StorageEndpoint = "grpcs://storage.example.com:2135"
+			Expect(database.ValidateCreate()).To(Succeed())
+			Expect(database.Spec.HasOwnConfiguration()).To(BeTrue())
+		})
+	})
+
+	Context("storage endpoint override", func() {
+		It("is used by operator instead of storageEndpoint", func() {
+			database := newTestDatabase()
+			database.Spec.StorageEndpoint = "grpc://storage-grpc.ydb.svc.cluster.local:2135"
+			Expect(database.Spec.GetOperatorStorageEndpoint()).To(Equal(database.Spec.StorageEndpoint))
+
+			database.Spec.StorageClusterRef.EndpointOverride = "grpcs://storage.example.com:2135"
+			Expect(database.Spec.GetOperatorStorageEndpoint()).To(Equal("grpcs://storage.example.com:2135"))
+			Expect(database.Spec.StorageEndpoint).To(Equal("grpc://storage-grpc.ydb.svc.cluster.local:2135"))
+		})
+	})
+})
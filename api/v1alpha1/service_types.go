@@ -3,7 +3,10 @@ package v1alpha1
 import corev1 "k8s.io/api/core/v1"
 
 type Service struct {
-	AdditionalLabels      map[string]string `json:"additionalLabels,omitempty"`
+	// (Optional) Labels added to the Service
+	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
+	// (Optional) Annotations added to the Service, e.g. settings of cloud
+	// load balancer or service mesh specific to this Service
 	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
 
 	IPFamilies     []corev1.IPFamily          `json:"ipFamilies,omitempty"`
@@ -17,6 +20,14 @@ type TLSConfiguration struct {
 	Key                  corev1.SecretKeySelector `json:"key,omitempty"` // fixme validate: all three or none
 }
 
+// secretNames lists Secrets with certificates used by enabled TLS.
+func (t *TLSConfiguration) secretNames() []string {
+	if t == nil || !t.Enabled {
+		return nil
+	}
+	return []string{t.CertificateAuthority.Name, t.Certificate.Name, t.Key.Name}
+}
+
 type GRPCService struct {
 	Service `json:""`
 
@@ -43,6 +54,34 @@ type DatastreamsService struct {
 	TLSConfiguration *TLSConfiguration `json:"tls,omitempty"`
 }
 
+// ServicePort is an additional port of YDB process, exposed as container
+// port and by a separate Service
+type ServicePort struct {
+	Service `json:""`
+
+	// Name of the port, used as name of the container port and as suffix
+	// of the Service name
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength:=15
+	Name string `json:"name"`
+
+	// Port number listened by YDB process
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	Port int32 `json:"port"`
+
+	// (Optional) Protocol of the port
+	// Default: TCP
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +kubebuilder:default:=TCP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+}
+
+type PostgresService struct {
+	Service `json:""`
+}
+
 type IPDiscovery struct {
 	Enabled            bool            `json:"enabled"`
 	TargetNameOverride string          `json:"targetNameOverride,omitempty"`
@@ -1,8 +1,10 @@
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants"
 )
@@ -27,6 +29,79 @@ type StorageSpec struct {
 	// Default: (not specified)
 	// +optional
 	NodeSets []StorageNodeSetSpecInline `json:"nodeSets,omitempty"`
+
+	// (Optional) Rolling update settings of the Storage StatefulSet
+	// Default: (not specified)
+	// +optional
+	RollingUpdate *StorageRollingUpdate `json:"rollingUpdate,omitempty"`
+
+	// (Optional) Settings of the Storage healthcheck performed by operator
+	// Default: (not specified)
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// (Optional) Keepalive settings of gRPC and interconnect connections of
+	// Storage and Database nodes, useful on high-latency networks. gRPC
+	// keepalive is also applied to connections of operator to Storage.
+	// Default: (not specified), defaults of YDB are used
+	// +optional
+	Keepalive *KeepaliveSpec `json:"keepalive,omitempty"`
+
+	// (Optional) Target number of storage groups of the running cluster.
+	// When increased, groups are added to the first storage pool of the
+	// cluster, decreasing is not supported.
+	// Default: (not specified)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Groups *int32 `json:"groups,omitempty"`
+
+	// (Optional) Additional objects applied by operator alongside Storage
+	// resources and owned by Storage, e.g. NetworkPolicy. Only ConfigMap,
+	// Service and NetworkPolicy kinds are supported, objects are created in
+	// the namespace of Storage. Names of objects created by operator itself
+	// can not be used. Objects removed from the list are deleted.
+	// Default: (not specified)
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	// +optional
+	AdditionalResources []runtime.RawExtension `json:"additionalResources,omitempty"`
+
+	// (Optional) Go template of the YDB configuration rendered with the Storage
+	// object as context (e.g. `{{ .Spec.Nodes }}`). When set, the rendered
+	// result is used as is instead of the configuration generated by operator.
+	// Default: (not specified)
+	// +optional
+	ConfigurationTemplate *ConfigurationTemplate `json:"configurationTemplate,omitempty"`
+
+	// (Optional) References to ConfigMap keys with YDB configuration overlays
+	// merged over the base configuration in list order, e.g. settings of
+	// the environment. Nested mappings are merged, any other value of the
+	// overlay (including lists) replaces the base one. ConfigMaps are read
+	// when Storage is created or updated.
+	// Default: (not specified)
+	// +optional
+	ConfigOverlays []corev1.ConfigMapKeySelector `json:"configOverlays,omitempty"`
+
+	// (Optional) Additional `bs config invoke` requests applied once, in list
+	// order, after blobstorage is initialized. Request referenced from
+	// ConfigMap is applied again when content of the key changes. Escape
+	// hatch for advanced blobstorage topologies, requests are not validated
+	// by operator.
+	// Default: (not specified)
+	// +optional
+	AdditionalBSConfig []BSConfigCommand `json:"additionalBSConfig,omitempty"`
+}
+
+// BSConfigCommand is a text proto of `bs config invoke` request, specified
+// either inline or by reference to ConfigMap key
+type BSConfigCommand struct {
+	// (Optional) Text proto of the request, e.g. `Command { DefineStoragePool { ... } }`
+	// +optional
+	Proto string `json:"proto,omitempty"`
+
+	// (Optional) Reference to ConfigMap key with text proto of the request
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
 }
 
 type StorageClusterSpec struct {
@@ -41,15 +116,26 @@ type StorageClusterSpec struct {
 	// Data storage topology mode
 	// For details, see https://ydb.tech/docs/en/cluster/topology
 	// FIXME mirror-3-dc is only supported with external configuration
-	// +kubebuilder:validation:Enum=mirror-3-dc;block-4-2;none
+	// +kubebuilder:validation:Enum=mirror-3-dc;mirror-3of4;block-4-2;none
 	// +kubebuilder:default:=block-4-2
 	Erasure ErasureType `json:"erasure"`
 
+	// (Optional) Declarative topology of storage nodes. When set, static
+	// group of blobstorage is generated into `blob_storage_config` from
+	// `nodes`, `erasure` and `dataStore`, unless `hosts` or
+	// `blob_storage_config` are set in configuration. Only supported with
+	// plain configuration of version v1, not with dynconfig, unified
+	// configuration or `configurationTemplate`.
+	// Default: (not specified, static group is defined in configuration)
+	// +optional
+	Topology *StorageTopology `json:"topology,omitempty"`
+
 	// (Optional) Container image information
 	// +optional
 	Image *PodImage `json:"image,omitempty"`
 
-	// (Optional) YDBVersion sets the explicit version of the YDB image
+	// (Optional) YDBVersion sets the explicit version of the YDB image,
+	// ignored for images pinned by digest
 	// Default: ""
 	// +optional
 	YDBVersion string `json:"version,omitempty"`
@@ -60,22 +146,139 @@ type StorageClusterSpec struct {
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
 
+	// (Optional) List of additional containers run alongside YDB container
+	// in the pod, e.g. log shippers or proxies. Names must not clash with
+	// containers managed by the operator.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
 	// YDB configuration in YAML format. Will be applied on top of generated one in internal/configuration
 	// +optional
 	Configuration string `json:"configuration"`
 
+	// (Optional) Format of the YDB configuration: `v1` is the legacy static
+	// configuration with explicit DefineBox, `v2` is the unified configuration
+	// where the static group is managed by the cluster itself
+	// Default: v1
+	// +kubebuilder:validation:Enum=v1;v2
+	// +kubebuilder:default:=v1
+	// +optional
+	ConfigurationVersion ConfigurationVersion `json:"configurationVersion,omitempty"`
+
+	// (Optional) Directory inside the container where the configuration is mounted (read-only)
+	// Default: /opt/ydb/cfg
+	// +optional
+	ConfigDir string `json:"configDir,omitempty"`
+
+	// (Optional) How changes of configuration are applied to Storage Pods,
+	// including Pods of node sets.
+	// `Immediate` means the Pods are restarted with rolling restart.
+	// `Manual` means the Pods are not restarted by the operator and
+	// `ConfigPending` condition is set until all the Pods are deleted.
+	// `OnPodRestart` means the Pods pick up the configuration whenever they
+	// are restarted for any other reason.
+	// Default: Immediate
+	// +kubebuilder:validation:Enum=Immediate;Manual;OnPodRestart
+	// +kubebuilder:default:=Immediate
+	// +optional
+	ConfigChangePolicy ConfigChangePolicy `json:"configChangePolicy,omitempty"`
+
+	// (Optional) Separate disk for logs of storage nodes, mounted into
+	// every storage pod at `/opt/ydb/logs` where storage nodes write
+	// log file `ydbd.log`.
+	// Can not be changed after the Storage is created.
+	// Default: (not specified, logs are written to stderr)
+	// +optional
+	LogVolume *LogVolumeSpec `json:"logVolume,omitempty"`
+
+	// (Optional) Keep cluster data in `emptyDir` volumes instead of
+	// PersistentVolumeClaims, which is useful for testing and development
+	// clusters. Data is NOT durable and is lost when storage pods are
+	// restarted or rescheduled. Only `Filesystem` volume mode of `dataStore`
+	// is supported, `storagePools` can not be used.
+	// Can not be changed after the Storage is created.
+	// Default: false
+	// +optional
+	EphemeralDataStore bool `json:"ephemeralDataStore,omitempty"`
+
+	// (Optional) Number of pdisks of every storage node backed by `dataStore`.
+	// The only `dataStore` volume claim template with `Block` volume mode
+	// is used for each of the pdisks, drives of the pdisks are added to
+	// generated `host_configs`.
+	// Can not be changed after the Storage is created.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PDisksPerNode int32 `json:"pdisksPerNode,omitempty"`
+
+	// (Optional) Pod management policy of the Storage StatefulSet.
+	// `Parallel` starts all the storage pods at once, which is faster for
+	// large clusters, `OrderedReady` starts them one by one.
+	// Can not be changed after the Storage is created.
+	// Default: OrderedReady
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
+	// (Optional) Number of old revisions of the Storage StatefulSet kept
+	// to allow rollback
+	// Default: 3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// (Optional) Whether the service account token is mounted into storage pods.
+	// Storage nodes do not use Kubernetes API, the token may be disabled for
+	// security hardening.
+	// Default: (not specified, cluster default is used)
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// (Optional) Set `GOMAXPROCS` environment variable of the storage container
+	// and sidecars to their CPU limit rounded up to a whole CPU, e.g. `2`
+	// for `1500m`, so that processes respect cgroup CPU quota. Containers
+	// without CPU limit and explicitly set `GOMAXPROCS` are not changed.
+	// Default: false
+	// +optional
+	InjectGOMAXPROCS bool `json:"injectGOMAXPROCS,omitempty"`
+
+	// (Optional) Startup probe of the storage container. Storage nodes may
+	// take minutes to start, liveness probe is not performed until startup
+	// probe succeeds. Not used when liveness probe is disabled with annotation.
+	// Probe handler defaults to TCP check of GRPC port.
+	// Default: TCP check of GRPC port every 10s, up to 10 minutes
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
 	// (Optional) Storage services parameter overrides
 	// Default: (not specified)
 	// +optional
 	Service *StorageServices `json:"service,omitempty"`
 
+	// (Optional) Additional ports of YDB process, e.g. PostgreSQL or Kafka
+	// compatibility endpoints. Every port is added to the storage container
+	// and exposed by its own Service named `<storage>-<port name>`.
+	// +optional
+	AdditionalPorts []ServicePort `json:"additionalPorts,omitempty"`
+
 	// The state of the Storage processes.
-	// `true` means all the Storage Pods are being killed, but the Storage resource is persisted.
+	// `true` means all the Storage Pods are being killed, but the Storage resource is persisted,
+	// see `pauseMode`.
 	// `false` means the default state of the system, all Pods running.
 	// +kubebuilder:default:=false
 	// +optional
 	Pause bool `json:"pause"`
 
+	// (Optional) How the Storage is paused with `pause: true`.
+	// `ScaleToZero` means all the Storage Pods are being killed.
+	// `Freeze` means the Storage Pods keep running, but the operator does
+	// not modify resources of the Storage until it is resumed, e.g. for
+	// maintenance.
+	// Default: ScaleToZero
+	// +kubebuilder:validation:Enum=ScaleToZero;Freeze
+	// +optional
+	PauseMode PauseMode `json:"pauseMode,omitempty"`
+
 	// Enables or disables operator's reconcile loop.
 	// `false` means all the Pods are running, but the reconcile is effectively turned off.
 	// `true` means the default state of the system, all Pods running, operator reacts
@@ -106,6 +309,22 @@ type StorageClusterSpec struct {
 	Secrets []*corev1.LocalObjectReference `json:"secrets,omitempty"`
 }
 
+type StorageTopology struct {
+	// (Optional) Data centers storage nodes are placed to in round-robin
+	// order, written to `walle_location` of generated hosts.
+	// mirror-3-dc erasure requires exactly 3 data centers.
+	// Default: `az-0`, `az-1`, `az-2` for mirror-3-dc erasure, `az-1` otherwise
+	// +optional
+	DataCenters []string `json:"dataCenters,omitempty"`
+}
+
+type LogVolumeSpec struct {
+	// Template of the PersistentVolumeClaim for logs disk, its own storage
+	// class may be set with `storageClassName`
+	// +required
+	VolumeClaimTemplate corev1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate"`
+}
+
 type StorageNodeSpec struct {
 	// Number of nodes (pods)
 	// +required
@@ -115,6 +334,20 @@ type StorageNodeSpec struct {
 	// +optional
 	DataStore []corev1.PersistentVolumeClaimSpec `json:"dataStore,omitempty"`
 
+	// (Optional) Policy of the PersistentVolumeClaims created from StatefulSet
+	// VolumeClaimTemplates on StatefulSet deletion and scale-down. Retained
+	// PVCs are reused when the StatefulSet is scaled back up.
+	// Default: Retain for both
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// (Optional) Additional storage pools (e.g. `ssd` and `rot`) backed by
+	// separate block devices. If `domains_config.domain[].storage_pool_types`
+	// are declared in the configuration, every pool kind must be referenced
+	// there, otherwise the pools are defined by operator.
+	// +optional
+	StoragePools []StoragePool `json:"storagePools,omitempty"`
+
 	// (Optional) Container resource limits. Any container limits
 	// can be specified.
 	// Default: (not specified)
@@ -154,6 +387,12 @@ type StorageNodeSpec struct {
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// (Optional) If specified, the pod is dispatched by the specified scheduler,
+	// e.g. Volcano or YuniKorn for gang scheduling.
+	// Default: (not specified), default scheduler is used
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
 	// (Optional) If specified, the pod's terminationGracePeriodSeconds.
 	// +optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
@@ -167,6 +406,32 @@ type StorageNodeSpec struct {
 	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
 }
 
+// ExpandDataStore returns volume claim templates of every pdisk of the node
+// backed by data store: with `pdisksPerNode` the only template is repeated
+// for each of the pdisks.
+func ExpandDataStore(dataStore []corev1.PersistentVolumeClaimSpec, pdisksPerNode int32) []corev1.PersistentVolumeClaimSpec {
+	if pdisksPerNode <= 1 || len(dataStore) != 1 {
+		return dataStore
+	}
+
+	expanded := make([]corev1.PersistentVolumeClaimSpec, pdisksPerNode)
+	for i := range expanded {
+		dataStore[0].DeepCopyInto(&expanded[i])
+	}
+	return expanded
+}
+
+type StoragePool struct {
+	// Kind of the storage pool, e.g. `ssd` or `rot`
+	// +required
+	Kind string `json:"kind"`
+
+	// Template of the PersistentVolumeClaim that backs every pdisk of the pool.
+	// Only `Block` volume mode is supported.
+	// +required
+	VolumeClaimTemplate corev1.PersistentVolumeClaimSpec `json:"volumeClaimTemplate"`
+}
+
 type StorageInitJobSpec struct {
 	// (Optional) Container resource limits. Any container limits
 	// can be specified.
@@ -195,17 +460,244 @@ type StorageInitJobSpec struct {
 	// (Optional) Additional custom resource annotations that are added to all resources
 	// +optional
 	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
+
+	// (Optional) Number of retries before considering init blobstorage Job as failed.
+	// Retries are also capped by the 300 seconds active deadline of the Job,
+	// so large values take effect only if attempts fail fast.
+	// Default: 6
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// (Optional) Delay between checks of init blobstorage Job status, must be positive
+	// Default: 30s
+	// +optional
+	RequeueDelay *metav1.Duration `json:"requeueDelay,omitempty"`
+}
+
+type ConfigurationTemplate struct {
+	// (Optional) Inline template body
+	// +optional
+	Inline string `json:"inline,omitempty"`
+
+	// (Optional) Reference to ConfigMap key with template body.
+	// Changes of ConfigMap are rendered to configuration by operator.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+type HealthCheckSpec struct {
+	// (Optional) How the operator checks Storage health.
+	// `GRPC` calls SelfCheck of the Monitoring service via grpc endpoint.
+	// `HTTP` requests the status service, useful when grpc endpoint is not
+	// reachable by operator (e.g. TLS-locked).
+	// Default: GRPC
+	// +kubebuilder:validation:Enum=GRPC;HTTP
+	// +kubebuilder:default:=GRPC
+	// +optional
+	Mechanism HealthCheckMechanism `json:"mechanism,omitempty"`
+
+	// (Optional) Endpoint with scheme to check health at instead of the one
+	// derived from Storage services, e.g. for external or custom-port clusters.
+	// Scheme is grpc:// or grpcs:// for `GRPC` mechanism and http:// or https://
+	// for `HTTP` mechanism.
+	// Default: (not specified)
+	// +kubebuilder:validation:Pattern:=^(grpcs?|https?)://.+$
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// (Optional) Path of the status service healthcheck endpoint used by `HTTP` mechanism.
+	// Must be a JSON viewer handler, i.e. start with /viewer/json/
+	// Default: /viewer/json/healthcheck
+	// +kubebuilder:validation:Pattern:=^/viewer/json/.*$
+	// +optional
+	HTTPPath string `json:"httpPath,omitempty"`
+
+	// (Optional) Port of the status service used by `HTTP` mechanism when
+	// endpoint is not specified, e.g. when YDB monitoring listens on a custom
+	// port exposed with `additionalPorts`.
+	// Default: 8765
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HTTPPort int32 `json:"httpPort,omitempty"`
+}
+
+type KeepaliveSpec struct {
+	// (Optional) Keepalive of gRPC connections, `grpc_config.keep_alive_*`
+	// +optional
+	GRPC *GRPCKeepalive `json:"grpc,omitempty"`
+
+	// (Optional) Keepalive of interconnect connections between nodes
+	// +optional
+	Interconnect *InterconnectKeepalive `json:"interconnect,omitempty"`
+}
+
+type GRPCKeepalive struct {
+	// (Optional) Idle time of connection before the first keepalive probe
+	// Default: 90
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IdleTimeoutSeconds *int32 `json:"idleTimeoutSeconds,omitempty"`
+
+	// (Optional) Interval between keepalive probes
+	// Default: 10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProbeIntervalSeconds *int32 `json:"probeIntervalSeconds,omitempty"`
+
+	// (Optional) Number of unanswered probes before connection is closed
+	// Default: 3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxProbeCount *int32 `json:"maxProbeCount,omitempty"`
+}
+
+type InterconnectKeepalive struct {
+	// (Optional) Interval between heartbeats of interconnect session
+	// Default: (not specified), default of YDB is used
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HeartbeatIntervalSeconds *int32 `json:"heartbeatIntervalSeconds,omitempty"`
+
+	// (Optional) Time without heartbeats after which peer is considered dead
+	// Default: (not specified), default of YDB is used
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DeadPeerTimeoutSeconds *int32 `json:"deadPeerTimeoutSeconds,omitempty"`
+}
+
+type StorageRollingUpdate struct {
+	// (Optional) How the update partition is managed.
+	// `Auto` means the operator lowers the partition to 0 once all the Pods
+	// with ordinal >= partition are updated and ready.
+	// `Manual` means the partition is kept where the user set it.
+	// `Maintenance` means the operator restarts the Pods one by one, each
+	// after YDB CMS grants maintenance task for the node, down to the partition.
+	// Default: Auto
+	// +kubebuilder:validation:Enum=Auto;Manual;Maintenance
+	// +kubebuilder:default:=Auto
+	// +optional
+	Mode RollingUpdateMode `json:"mode,omitempty"`
+
+	// (Optional) Only Pods with ordinal greater than or equal to the partition
+	// are updated when the Storage Pod template changes.
+	// Default: 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
 }
 
 // StorageStatus defines the observed state of Storage
 type StorageStatus struct {
 	State      constants.ClusterState `json:"state"`
 	Conditions []metav1.Condition     `json:"conditions,omitempty"`
+
+	// Generation of the Storage spec which was fully reconciled, Ready
+	// condition is not True until the latest generation is reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Checksum of the rendered configuration that was applied to the
+	// cluster resources (stored in ConfigMap under `config.yaml` key)
+	// +optional
+	ObservedConfigHash string `json:"observedConfigHash,omitempty"`
+
+	// Checksum of the box definition (`hosts` and `host_configs` of the
+	// configuration) the blobstorage was initialized with. Later changes of
+	// the box definition are not applied and reported as drift
+	// +optional
+	InitializedBoxHash string `json:"initializedBoxHash,omitempty"`
+
+	// Desired and actual number of storage groups, tracked when
+	// `spec.groups` is specified
+	// +optional
+	Groups *StorageGroupsStatus `json:"groups,omitempty"`
+
+	// Checksums of `spec.additionalBSConfig` requests which were applied
+	// to the current blobstorage and are not applied again
+	// +optional
+	AppliedBSConfig []string `json:"appliedBSConfig,omitempty"`
+
+	// Current update partition of the Storage StatefulSet
+	// +optional
+	UpdatePartition *int32 `json:"updatePartition,omitempty"`
+
+	// Mapping of Storage Pods to YDB node IDs
+	// +optional
+	Nodes []StorageNodeStatus `json:"nodes,omitempty"`
+
+	// Image of the Storage nodes after the last completed rollout, which
+	// the nodes are reverted to when SelfCheck of the cluster fails during
+	// upgrade to a new image
+	// +optional
+	PreviousImage *PodImage `json:"previousImage,omitempty"`
+
+	// UID of the CMS maintenance task held for the Storage node being
+	// restarted by rolling update in `Maintenance` mode
+	// +optional
+	MaintenanceTask string `json:"maintenanceTask,omitempty"`
+
+	// YDB version running on Storage nodes. During rollout, or when version
+	// of some nodes is unknown, lists every version with its share of nodes,
+	// e.g. `25.1 (3/8), 25.2 (5/8)`
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Objects created by the operator for the Storage, e.g. Services,
+	// ConfigMap and StatefulSet
+	// +optional
+	ManagedResources []ManagedResource `json:"managedResources,omitempty"`
+
+	// Storage groups of the cluster and how many of them are allocated
+	// to tenants, reported by viewer
+	// +optional
+	TenantCapacity *StorageTenantCapacity `json:"tenantCapacity,omitempty"`
+}
+
+type StorageGroupsStatus struct {
+	// Number of storage groups requested by `spec.groups`
+	Desired int32 `json:"desired"`
+
+	// Number of storage groups reported by healthcheck
+	Current int32 `json:"current"`
+}
+
+type StorageTenantCapacity struct {
+	// Number of tenants hosted by the cluster
+	Tenants int32 `json:"tenants"`
+
+	// Number of storage groups of the cluster
+	StorageGroups int32 `json:"storageGroups"`
+
+	// Number of storage groups allocated to tenants
+	AllocatedStorageGroups int32 `json:"allocatedStorageGroups"`
+
+	// Number of storage units which can still be allocated to new tenants,
+	// i.e. how many tenants of one storage unit the cluster can host
+	Available int32 `json:"available"`
+}
+
+type StorageNodeStatus struct {
+	// Name of the Storage Pod
+	PodName string `json:"podName"`
+
+	// ID of the YDB node running in the Pod
+	NodeID uint32 `json:"nodeId"`
+
+	// State of the YDB node reported by viewer (e.g. Green, Yellow, Red)
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// YDB version running on the node reported by viewer
+	// +optional
+	RunningVersion string `json:"runningVersion,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="The status of this DB"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="YDB version running on Storage nodes"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Storage is the Schema for the Storages API
@@ -245,3 +737,41 @@ func (r *Storage) AnyCertificatesAdded() bool {
 		r.Spec.Service.Interconnect.TLSConfiguration.Enabled ||
 		r.Spec.Service.Status.TLSConfiguration.Enabled
 }
+
+// GetReferencedSecrets lists names of Secrets referenced from Storage spec:
+// additional secrets, TLS certificates, image pull secret and credentials
+// of operator connection.
+func (r *Storage) GetReferencedSecrets() []string {
+	var names []string
+	for _, secret := range r.Spec.Secrets {
+		names = append(names, secret.Name)
+	}
+	if r.Spec.Service != nil {
+		names = append(names, r.Spec.Service.GRPC.TLSConfiguration.secretNames()...)
+		names = append(names, r.Spec.Service.Interconnect.TLSConfiguration.secretNames()...)
+		names = append(names, r.Spec.Service.Status.TLSConfiguration.secretNames()...)
+	}
+	if r.Spec.Image != nil && r.Spec.Image.PullSecret != nil {
+		names = append(names, *r.Spec.Image.PullSecret)
+	}
+	names = append(names, r.Spec.OperatorConnection.secretNames()...)
+	return uniqueSecretNames(names)
+}
+
+// GetReferencedConfigMaps returns names of ConfigMaps which are read by
+// operator to build configuration of Storage or to configure blobstorage
+func (r *Storage) GetReferencedConfigMaps() []string {
+	var names []string
+	if r.Spec.ConfigurationTemplate != nil && r.Spec.ConfigurationTemplate.ConfigMapKeyRef != nil {
+		names = append(names, r.Spec.ConfigurationTemplate.ConfigMapKeyRef.Name)
+		for _, overlayRef := range r.Spec.ConfigOverlays {
+			names = append(names, overlayRef.Name)
+		}
+	}
+	for _, command := range r.Spec.AdditionalBSConfig {
+		if command.ConfigMapKeyRef != nil {
+			names = append(names, command.ConfigMapKeyRef.Name)
+		}
+	}
+	return uniqueSecretNames(names)
+}
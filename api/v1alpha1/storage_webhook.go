@@ -2,15 +2,21 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/strings/slices"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,6 +37,13 @@ func (r *Storage) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Complete()
 }
 
+func (r *StorageClusterSpec) GetConfigDir() string {
+	if r.ConfigDir != "" {
+		return r.ConfigDir
+	}
+	return ConfigDir
+}
+
 func (r *Storage) GetStorageEndpointWithProto() string {
 	return fmt.Sprintf("%s%s", r.GetStorageProto(), r.GetStorageEndpoint())
 }
@@ -62,6 +75,43 @@ func (r *Storage) GetGRPCServiceEndpoint() string {
 	return fmt.Sprintf("%s:%d", host, GRPCPort)
 }
 
+func (r *Storage) GetStatusServiceEndpointWithProto() string {
+	return r.getStatusServiceEndpointWithProto(StatusPort)
+}
+
+func (r *Storage) getStatusServiceEndpointWithProto(port int32) string {
+	proto := "http://"
+	if r.IsStatusEndpointSecure() {
+		proto = "https://"
+	}
+
+	return fmt.Sprintf("%s%s:%d", proto, fmt.Sprintf(StatusServiceFQDNFormat, r.Name, r.Namespace), port)
+}
+
+// GetHealthCheckEndpoint returns the endpoint with scheme used by the
+// operator to check Storage health: override from spec if set, otherwise
+// the grpc or status service endpoint depending on mechanism.
+func (r *Storage) GetHealthCheckEndpoint() string {
+	if r.Spec.HealthCheck != nil && r.Spec.HealthCheck.Endpoint != "" {
+		return r.Spec.HealthCheck.Endpoint
+	}
+	if r.Spec.HealthCheck != nil && r.Spec.HealthCheck.Mechanism == HealthCheckHTTP {
+		port := int32(StatusPort)
+		if r.Spec.HealthCheck.HTTPPort != 0 {
+			port = r.Spec.HealthCheck.HTTPPort
+		}
+		return r.getStatusServiceEndpointWithProto(port)
+	}
+	return r.GetStorageEndpointWithProto()
+}
+
+func (r *Storage) GetHealthCheckHTTPPath() string {
+	if r.Spec.HealthCheck != nil && r.Spec.HealthCheck.HTTPPath != "" {
+		return r.Spec.HealthCheck.HTTPPath
+	}
+	return DefaultHealthCheckHTTPPath
+}
+
 func (r *Storage) GetHostFromConfigEndpoint() string {
 	var rawYamlConfiguration string
 	// skip handle error because we already checked in webhook
@@ -78,6 +128,40 @@ func (r *Storage) GetHostFromConfigEndpoint() string {
 	return fmt.Sprintf("%s:%d", configuration.Hosts[randNum].Host, GRPCPort)
 }
 
+// GetExpectedStorageGroups returns the number of static storage groups
+// that initialization of Storage is expected to bring up. Configurations
+// without an explicit service_set still get a single static group.
+func (r *Storage) GetExpectedStorageGroups() int {
+	var rawYamlConfiguration string
+	success, dynConfig, _ := ParseDynConfig(r.Spec.Configuration)
+	if success {
+		config, _ := yaml.Marshal(dynConfig.Config)
+		rawYamlConfiguration = string(config)
+	} else {
+		rawYamlConfiguration = r.Spec.Configuration
+	}
+
+	configuration, _ := ParseConfiguration(rawYamlConfiguration)
+	if configuration.BlobStorageConfig == nil ||
+		configuration.BlobStorageConfig.ServiceSet == nil ||
+		len(configuration.BlobStorageConfig.ServiceSet.Groups) == 0 {
+		return 1
+	}
+	return len(configuration.BlobStorageConfig.ServiceSet.Groups)
+}
+
+// GetDataCenters returns data centers storage nodes are placed to in
+// round-robin order
+func (r *Storage) GetDataCenters() []string {
+	if r.Spec.Topology != nil && len(r.Spec.Topology.DataCenters) > 0 {
+		return r.Spec.Topology.DataCenters
+	}
+	if r.Spec.Erasure == ErasureMirror3DC {
+		return []string{"az-0", "az-1", "az-2"}
+	}
+	return []string{"az-1"}
+}
+
 func (r *Storage) IsStorageEndpointSecure() bool {
 	if r.Spec.Service.GRPC.TLSConfiguration != nil {
 		return r.Spec.Service.GRPC.TLSConfiguration.Enabled
@@ -85,6 +169,13 @@ func (r *Storage) IsStorageEndpointSecure() bool {
 	return false
 }
 
+func (r *Storage) IsStatusEndpointSecure() bool {
+	if r.Spec.Service.Status.TLSConfiguration != nil {
+		return r.Spec.Service.Status.TLSConfiguration.Enabled
+	}
+	return false
+}
+
 func (r *Storage) IsRemoteNodeSetsOnly() bool {
 	if len(r.Spec.NodeSets) == 0 {
 		return false
@@ -137,7 +228,7 @@ func (r *StorageDefaulter) Default(ctx context.Context, obj runtime.Object) erro
 	}
 
 	if storage.Spec.Image.PullPolicyName == nil {
-		policy := corev1.PullIfNotPresent
+		policy := storage.Spec.Image.defaultPullPolicy()
 		storage.Spec.Image.PullPolicyName = &policy
 	}
 
@@ -175,15 +266,115 @@ func (r *StorageDefaulter) Default(ctx context.Context, obj runtime.Object) erro
 		storage.Spec.Domain = DefaultDatabaseDomain
 	}
 
+	if storage.Spec.ConfigurationTemplate != nil {
+		configuration, err := RenderStorageConfiguration(ctx, r.Client, storage)
+		if err != nil {
+			return err
+		}
+		storage.Spec.Configuration = string(configuration)
+		return nil
+	}
+
 	configuration, err := BuildConfiguration(storage, nil)
 	if err != nil {
 		return err
 	}
-	storage.Spec.Configuration = string(configuration)
 
+	configuration, err = mergeStorageConfigOverlays(ctx, r.Client, storage, configuration)
+	if err != nil {
+		return err
+	}
+
+	storage.Spec.Configuration = string(configuration)
 	return nil
 }
 
+// RenderStorageConfiguration renders configuration template of Storage and
+// merges configuration overlays, if any, over the result. Operator renders
+// it again on changes of the template ConfigMap which is read with c.
+func RenderStorageConfiguration(ctx context.Context, c client.Client, storage *Storage) ([]byte, error) {
+	configurationTemplate, err := getConfigurationTemplate(ctx, c, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration, err := RenderConfigurationTemplate(storage, configurationTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeStorageConfigOverlays(ctx, c, storage, configuration)
+}
+
+// mergeStorageConfigOverlays merges configuration overlays of Storage, if
+// any, over the base configuration
+func mergeStorageConfigOverlays(ctx context.Context, c client.Client, storage *Storage, configuration []byte) ([]byte, error) {
+	if len(storage.Spec.ConfigOverlays) == 0 {
+		return configuration, nil
+	}
+
+	overlays := make([]string, 0, len(storage.Spec.ConfigOverlays))
+	for _, overlayRef := range storage.Spec.ConfigOverlays {
+		overlay, err := getConfigOverlay(ctx, c, storage, overlayRef)
+		if err != nil {
+			return nil, err
+		}
+		overlays = append(overlays, overlay)
+	}
+
+	return MergeConfigurationOverlays(configuration, overlays)
+}
+
+func getConfigOverlay(
+	ctx context.Context,
+	c client.Client,
+	storage *Storage,
+	overlayRef corev1.ConfigMapKeySelector,
+) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{
+		Name:      overlayRef.Name,
+		Namespace: storage.Namespace,
+	}, configMap); err != nil {
+		if apierrors.IsNotFound(err) && overlayRef.Optional != nil && *overlayRef.Optional {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get configuration overlay ConfigMap %s, error: %w", overlayRef.Name, err)
+	}
+
+	body, ok := configMap.Data[overlayRef.Key]
+	if !ok {
+		if overlayRef.Optional != nil && *overlayRef.Optional {
+			return "", nil
+		}
+		return "", fmt.Errorf("key %s not found in configuration overlay ConfigMap %s", overlayRef.Key, overlayRef.Name)
+	}
+
+	return body, nil
+}
+
+func getConfigurationTemplate(ctx context.Context, c client.Client, storage *Storage) (string, error) {
+	configurationTemplate := storage.Spec.ConfigurationTemplate
+	if configurationTemplate.ConfigMapKeyRef == nil {
+		return configurationTemplate.Inline, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{
+		Name:      configurationTemplate.ConfigMapKeyRef.Name,
+		Namespace: storage.Namespace,
+	}, configMap); err != nil {
+		return "", fmt.Errorf("failed to get configuration template ConfigMap %s, error: %w", configurationTemplate.ConfigMapKeyRef.Name, err)
+	}
+
+	body, ok := configMap.Data[configurationTemplate.ConfigMapKeyRef.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in configuration template ConfigMap %s", configurationTemplate.ConfigMapKeyRef.Key, configurationTemplate.ConfigMapKeyRef.Name)
+	}
+
+	return body, nil
+}
+
 //+kubebuilder:webhook:path=/validate-ydb-tech-v1alpha1-storage,mutating=true,failurePolicy=fail,sideEffects=None,groups=ydb.tech,resources=storages,verbs=create;update,versions=v1alpha1,name=validate-storage.ydb.tech,admissionReviewVersions=v1
 
 var _ webhook.Validator = &Storage{}
@@ -203,6 +394,26 @@ func isSignAlgorithmSupported(alg string) bool {
 func (r *Storage) ValidateCreate() error {
 	storagelog.Info("validate create", "name", r.Name)
 
+	if err := validateDurationAnnotations(r.Annotations); err != nil {
+		return err
+	}
+
+	if err := r.ValidateSpec(); err != nil {
+		return err
+	}
+
+	crdCheckError := checkMonitoringCRD(manager, storagelog, r.Spec.Monitoring != nil)
+	if crdCheckError != nil {
+		return crdCheckError
+	}
+
+	return nil
+}
+
+// ValidateSpec checks invariants of Storage spec which do not require
+// access to the cluster. It is also used by the reconciler, so that
+// invalid spec is reported even when webhooks are not installed.
+func (r *Storage) ValidateSpec() error {
 	var rawYamlConfiguration string
 	success, dynConfig, err := ParseDynConfig(r.Spec.Configuration)
 	if success {
@@ -231,18 +442,16 @@ func (r *Storage) ValidateCreate() error {
 		nodesNumber = int32(len(configuration.Hosts))
 	}
 
-	minNodesPerErasure := map[ErasureType]int32{
-		ErasureMirror3DC: 3,
-		ErasureBlock42:   8,
-		None:             1,
+	if err := validateErasureNodes(r.Spec.Erasure, nodesNumber); err != nil {
+		return err
 	}
 
-	if nodesNumber < minNodesPerErasure[r.Spec.Erasure] {
-		return fmt.Errorf("erasure type %v requires at least %v storage nodes", r.Spec.Erasure, minNodesPerErasure[r.Spec.Erasure])
+	if err := r.validateTopology(success); err != nil {
+		return err
 	}
 
 	var authEnabled bool
-	if configuration.DomainsConfig.SecurityConfig != nil {
+	if configuration.DomainsConfig != nil && configuration.DomainsConfig.SecurityConfig != nil {
 		if configuration.DomainsConfig.SecurityConfig.EnforceUserTokenRequirement != nil {
 			authEnabled = *configuration.DomainsConfig.SecurityConfig.EnforceUserTokenRequirement
 		}
@@ -252,6 +461,58 @@ func (r *Storage) ValidateCreate() error {
 		return fmt.Errorf("field 'spec.operatorConnection' does not satisfy with config option `enforce_user_token_requirement: %t`", authEnabled)
 	}
 
+	if err := r.validateDomain(configuration); err != nil {
+		return err
+	}
+
+	if err := r.validateStoragePools(configuration); err != nil {
+		return err
+	}
+
+	if err := r.validateEphemeralDataStore(); err != nil {
+		return err
+	}
+
+	if err := r.validatePDisksPerNode(configuration); err != nil {
+		return err
+	}
+
+	if err := r.validateRollingUpdate(); err != nil {
+		return err
+	}
+
+	if err := r.validateHealthCheck(); err != nil {
+		return err
+	}
+
+	if err := validateAdditionalResources(r.Name, r.Namespace, r.Spec.AdditionalResources); err != nil {
+		return err
+	}
+
+	if err := validateAdditionalPorts(r.Spec.AdditionalPorts); err != nil {
+		return err
+	}
+
+	if err := validateSidecars(r.Spec.Sidecars, r.Spec.InitContainers, StorageContainerName); err != nil {
+		return err
+	}
+
+	if err := validateImageDigest(r.Spec.Image); err != nil {
+		return err
+	}
+
+	if err := r.validateInitJob(); err != nil {
+		return err
+	}
+
+	if err := r.validateConfigurationTemplate(); err != nil {
+		return err
+	}
+
+	if err := r.validateAdditionalBSConfig(); err != nil {
+		return err
+	}
+
 	if r.Spec.OperatorConnection != nil && r.Spec.OperatorConnection.Oauth2TokenExchange != nil {
 		auth := r.Spec.OperatorConnection.Oauth2TokenExchange
 		if auth.KeyID == nil {
@@ -290,14 +551,398 @@ func (r *Storage) ValidateCreate() error {
 		}
 	}
 
-	crdCheckError := checkMonitoringCRD(manager, storagelog, r.Spec.Monitoring != nil)
-	if crdCheckError != nil {
-		return crdCheckError
+	return nil
+}
+
+// validateStoragePools checks storage pools of Storage and its NodeSets.
+// Pool kinds are defined by operator only if configuration declares no
+// `storage_pool_types`, otherwise every pool kind must be referenced there.
+func (r *Storage) validateStoragePools(configuration schema.Configuration) error {
+	var declaredKinds map[string]bool
+	if configuration.DomainsConfig != nil {
+		for _, domain := range configuration.DomainsConfig.Domain {
+			for _, poolType := range domain.StoragePoolTypes {
+				if declaredKinds == nil {
+					declaredKinds = make(map[string]bool)
+				}
+				declaredKinds[poolType.Kind] = true
+			}
+		}
+	}
+
+	if err := validateStoragePools(r.Spec.StoragePools, declaredKinds); err != nil {
+		return err
+	}
+
+	for _, nodeSetSpec := range r.Spec.NodeSets {
+		if err := validateStoragePools(nodeSetSpec.StoragePools, declaredKinds); err != nil {
+			return fmt.Errorf("nodeSet %s: %w", nodeSetSpec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Storage) validateEphemeralDataStore() error {
+	if !r.Spec.EphemeralDataStore {
+		return nil
+	}
+
+	if err := validateEphemeralDataStore(r.Spec.StorageNodeSpec); err != nil {
+		return err
+	}
+
+	for _, nodeSetSpec := range r.Spec.NodeSets {
+		if err := validateEphemeralDataStore(nodeSetSpec.StorageNodeSpec); err != nil {
+			return fmt.Errorf("nodeSet %s: %w", nodeSetSpec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateEphemeralDataStore(spec StorageNodeSpec) error {
+	if len(spec.StoragePools) > 0 {
+		return errors.New("storage pools can not be used with ephemeral data store")
+	}
+	for _, pvcSpec := range spec.DataStore {
+		if pvcSpec.VolumeMode != nil && *pvcSpec.VolumeMode == corev1.PersistentVolumeBlock {
+			return errors.New("ephemeral data store supports only Filesystem volume mode")
+		}
+	}
+	return nil
+}
+
+// validatePDisksPerNode checks that several pdisks per node are backed by
+// the only block data store, and that host configs declared in configuration
+// have a drive for every pdisk of the node
+func (r *Storage) validatePDisksPerNode(configuration schema.Configuration) error {
+	if r.Spec.PDisksPerNode <= 1 {
+		return nil
+	}
+
+	if r.Spec.EphemeralDataStore {
+		return errors.New("field 'spec.pdisksPerNode' can not be used with ephemeral data store")
+	}
+
+	if err := validatePDisksDataStore(r.Spec.DataStore); err != nil {
+		return err
+	}
+	for _, nodeSetSpec := range r.Spec.NodeSets {
+		if nodeSetSpec.DataStore == nil {
+			continue
+		}
+		if err := validatePDisksDataStore(nodeSetSpec.DataStore); err != nil {
+			return fmt.Errorf("nodeSet %s: %w", nodeSetSpec.Name, err)
+		}
+	}
+
+	// Host configs can not be matched with NodeSets which may differ in
+	// storage pools, so drives are only checked for a single StatefulSet
+	if r.Spec.NodeSets != nil {
+		return nil
+	}
+	drives := int(r.Spec.PDisksPerNode) + len(r.Spec.StoragePools)
+	for _, hostConfig := range configuration.HostConfigs {
+		if len(hostConfig.Drive) != drives {
+			return fmt.Errorf("host config %d declares %d drives, but nodes have %d pdisks",
+				hostConfig.HostConfigID, len(hostConfig.Drive), drives)
+		}
+	}
+
+	return nil
+}
+
+func validatePDisksDataStore(dataStore []corev1.PersistentVolumeClaimSpec) error {
+	if len(dataStore) != 1 {
+		return fmt.Errorf("field 'spec.pdisksPerNode' requires exactly one 'dataStore' volume claim template, found %d", len(dataStore))
+	}
+	if dataStore[0].VolumeMode == nil || *dataStore[0].VolumeMode != corev1.PersistentVolumeBlock {
+		return errors.New("field 'spec.pdisksPerNode' requires `Block` volume mode of 'dataStore'")
+	}
+	return nil
+}
+
+func validateStoragePools(storagePools []StoragePool, declaredKinds map[string]bool) error {
+	seenKinds := make(map[string]bool)
+	for _, pool := range storagePools {
+		if seenKinds[pool.Kind] {
+			return fmt.Errorf("storage pool kind %s is specified more than once", pool.Kind)
+		}
+		seenKinds[pool.Kind] = true
+
+		if declaredKinds != nil && !declaredKinds[pool.Kind] {
+			return fmt.Errorf("storage pool kind %s is not declared in `domains_config.domain[].storage_pool_types`", pool.Kind)
+		}
+
+		volumeMode := pool.VolumeClaimTemplate.VolumeMode
+		if volumeMode == nil || *volumeMode != corev1.PersistentVolumeBlock {
+			return fmt.Errorf("storage pool %s must use `Block` volume mode", pool.Kind)
+		}
+	}
+
+	return nil
+}
+
+func (r *Storage) validateRollingUpdate() error {
+	if r.Spec.RollingUpdate == nil {
+		return nil
+	}
+
+	if r.Spec.RollingUpdate.Mode == RollingUpdateModeMaintenance && r.Spec.NodeSets != nil {
+		return fmt.Errorf("rolling update mode %s is not supported with 'spec.nodeSets'", RollingUpdateModeMaintenance)
+	}
+
+	if r.Spec.RollingUpdate.Partition == nil {
+		return nil
+	}
+
+	if r.Spec.NodeSets != nil {
+		return fmt.Errorf("field 'spec.rollingUpdate.partition' is not supported with 'spec.nodeSets'")
+	}
+
+	if *r.Spec.RollingUpdate.Partition > r.Spec.Nodes {
+		return fmt.Errorf("rolling update partition %d exceeds number of nodes %d", *r.Spec.RollingUpdate.Partition, r.Spec.Nodes)
+	}
+
+	return nil
+}
+
+func (r *Storage) validateHealthCheck() error {
+	if r.Spec.HealthCheck == nil {
+		return nil
+	}
+
+	// Only JSON viewer handlers can be parsed into SelfCheckResult
+	if r.Spec.HealthCheck.HTTPPath != "" &&
+		!strings.HasPrefix(r.Spec.HealthCheck.HTTPPath, HealthCheckHTTPPathPrefix) {
+		return fmt.Errorf("field 'spec.healthCheck.httpPath' must start with %s", HealthCheckHTTPPathPrefix)
+	}
+
+	if r.Spec.HealthCheck.Endpoint != "" {
+		schemes := []string{GRPCProto, GRPCSProto}
+		if r.Spec.HealthCheck.Mechanism == HealthCheckHTTP {
+			schemes = []string{"http://", "https://"}
+		}
+		if !strings.HasPrefix(r.Spec.HealthCheck.Endpoint, schemes[0]) &&
+			!strings.HasPrefix(r.Spec.HealthCheck.Endpoint, schemes[1]) {
+			return fmt.Errorf("field 'spec.healthCheck.endpoint' must start with %s or %s", schemes[0], schemes[1])
+		}
+	}
+
+	return nil
+}
+
+// validateAdditionalPorts checks that additional ports do not clash with
+// each other and with ports managed by the operator
+func validateAdditionalPorts(ports []ServicePort) error {
+	names := map[string]bool{
+		GRPCServicePortName:         true,
+		InterconnectServicePortName: true,
+		StatusServicePortName:       true,
+	}
+	numbers := map[int32]bool{
+		GRPCPort:         true,
+		InterconnectPort: true,
+		StatusPort:       true,
+	}
+
+	for _, port := range ports {
+		if names[port.Name] {
+			return fmt.Errorf("additional port name %s is already in use", port.Name)
+		}
+		names[port.Name] = true
+
+		if numbers[port.Port] {
+			return fmt.Errorf("additional port %s: port %d is already in use", port.Name, port.Port)
+		}
+		numbers[port.Port] = true
+	}
+
+	return nil
+}
+
+// validateSidecars checks that names of sidecar containers are unique
+// within the pod, so that the YDB container is still resolved by name
+func validateSidecars(sidecars, initContainers []corev1.Container, containerName string) error {
+	names := map[string]bool{
+		containerName:                true,
+		CaStorePatchingContainerName: true,
+	}
+	for _, container := range initContainers {
+		names[container.Name] = true
+	}
+
+	for _, sidecar := range sidecars {
+		if names[sidecar.Name] {
+			return fmt.Errorf("sidecar container name %s is already in use", sidecar.Name)
+		}
+		names[sidecar.Name] = true
+	}
+
+	return nil
+}
+
+var imageDigestRegexp = regexp.MustCompile(`^[a-z0-9]+([+._-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+
+// validateImageDigest checks digest of the image pinned by digest, so that
+// mistyped digest is not rolled out as a mutable tag
+func validateImageDigest(image *PodImage) error {
+	if !image.HasDigest() {
+		return nil
+	}
+	digest := image.Name[strings.LastIndex(image.Name, "@")+1:]
+	if !imageDigestRegexp.MatchString(digest) {
+		return fmt.Errorf("incorrect digest %q of image %s, must be in form `algorithm:hex`, e.g. `sha256:...`", digest, image.Name)
+	}
+	return nil
+}
+
+var domainNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?$`)
+
+func validateDomainName(domain string) error {
+	if len(domain) > 63 || !domainNameRegexp.MatchString(domain) {
+		return fmt.Errorf("incorrect domain name %q, must consist of alphanumeric characters, '-' or '_' and be at most 63 characters long", domain)
+	}
+	return nil
+}
+
+func (r *Storage) validateDomain(configuration schema.Configuration) error {
+	if r.Spec.Domain == "" {
+		return nil
+	}
+
+	if err := validateDomainName(r.Spec.Domain); err != nil {
+		return err
+	}
+
+	if configuration.DomainsConfig == nil || len(configuration.DomainsConfig.Domain) == 0 {
+		return nil
+	}
+
+	for _, domain := range configuration.DomainsConfig.Domain {
+		if domain.Name == r.Spec.Domain {
+			return nil
+		}
+	}
+	return fmt.Errorf("domain %s is not declared in `domains_config.domain[]`", r.Spec.Domain)
+}
+
+func (r *Storage) validateInitJob() error {
+	if r.Spec.InitJob == nil || r.Spec.InitJob.RequeueDelay == nil {
+		return nil
+	}
+
+	if r.Spec.InitJob.RequeueDelay.Duration <= 0 {
+		return fmt.Errorf("field 'spec.initJob.requeueDelay' must be positive, got %s", r.Spec.InitJob.RequeueDelay.Duration)
+	}
+
+	return nil
+}
+
+func (r *Storage) validateConfigurationTemplate() error {
+	if r.Spec.ConfigurationTemplate == nil {
+		return nil
+	}
+
+	hasInline := r.Spec.ConfigurationTemplate.Inline != ""
+	hasConfigMapKeyRef := r.Spec.ConfigurationTemplate.ConfigMapKeyRef != nil
+	if hasInline == hasConfigMapKeyRef {
+		return fmt.Errorf("exactly one of 'spec.configurationTemplate.inline' or 'spec.configurationTemplate.configMapKeyRef' must be specified")
+	}
+
+	return nil
+}
+
+// validateImmutableFields rejects changes of fields which can not be applied
+// to the existing cluster: blobstorage is initialized with them and
+// StatefulSet volumeClaimTemplates are immutable
+func (r *Storage) validateImmutableFields(old *Storage) error {
+	if old.Spec.Domain != "" && old.Spec.Domain != r.Spec.Domain {
+		return errors.New("storage domain cannot be changed")
+	}
+
+	if old.Spec.Erasure != "" && old.Spec.Erasure != r.Spec.Erasure {
+		return fmt.Errorf("field 'spec.erasure' cannot be changed from %s to %s", old.Spec.Erasure, r.Spec.Erasure)
+	}
+
+	if old.Spec.ConfigurationVersion != "" && old.Spec.ConfigurationVersion != r.Spec.ConfigurationVersion {
+		return fmt.Errorf("field 'spec.configurationVersion' is immutable, migration from %s to %s is not supported", old.Spec.ConfigurationVersion, r.Spec.ConfigurationVersion)
+	}
+
+	if old.Spec.Groups != nil && (r.Spec.Groups == nil || *r.Spec.Groups < *old.Spec.Groups) {
+		return fmt.Errorf("field 'spec.groups' cannot be decreased, removal of storage groups is not supported")
+	}
+
+	if !equality.Semantic.DeepEqual(old.Spec.LogVolume, r.Spec.LogVolume) {
+		return errors.New("field 'spec.logVolume' cannot be changed")
+	}
+	if old.Spec.EphemeralDataStore != r.Spec.EphemeralDataStore {
+		return errors.New("field 'spec.ephemeralDataStore' cannot be changed")
+	}
+	if old.Spec.PDisksPerNode != r.Spec.PDisksPerNode {
+		return errors.New("field 'spec.pdisksPerNode' cannot be changed")
+	}
+	if old.Spec.PodManagementPolicy != r.Spec.PodManagementPolicy {
+		return errors.New("field 'spec.podManagementPolicy' cannot be changed")
+	}
+
+	for i := range old.Spec.DataStore {
+		if i >= len(r.Spec.DataStore) {
+			break
+		}
+		if err := validateVolumeSizeNotDecreased(
+			fmt.Sprintf("spec.dataStore[%d]", i),
+			old.Spec.DataStore[i],
+			r.Spec.DataStore[i],
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, oldPool := range old.Spec.StoragePools {
+		for _, pool := range r.Spec.StoragePools {
+			if pool.Kind != oldPool.Kind {
+				continue
+			}
+			if err := validateVolumeSizeNotDecreased(
+				fmt.Sprintf("spec.storagePools[%s].volumeClaimTemplate", pool.Kind),
+				oldPool.VolumeClaimTemplate,
+				pool.VolumeClaimTemplate,
+			); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+func validateVolumeSizeNotDecreased(field string, oldSpec, newSpec corev1.PersistentVolumeClaimSpec) error {
+	oldSize, hasOldSize := oldSpec.Resources.Requests[corev1.ResourceStorage]
+	newSize, hasNewSize := newSpec.Resources.Requests[corev1.ResourceStorage]
+	if hasOldSize && hasNewSize && newSize.Cmp(oldSize) < 0 {
+		return fmt.Errorf(
+			"field '%s.resources.requests.storage' cannot be decreased from %s to %s",
+			field,
+			oldSize.String(),
+			newSize.String(),
+		)
+	}
+	return nil
+}
+
+func (r *Storage) validateAdditionalBSConfig() error {
+	for i, command := range r.Spec.AdditionalBSConfig {
+		hasProto := command.Proto != ""
+		hasConfigMapKeyRef := command.ConfigMapKeyRef != nil
+		if hasProto == hasConfigMapKeyRef {
+			return fmt.Errorf("exactly one of 'proto' or 'configMapKeyRef' must be specified in 'spec.additionalBSConfig[%d]'", i)
+		}
+	}
+	return nil
+}
+
 func hasUpdatesBesidesFrozen(oldStorage, newStorage *Storage) (bool, string) {
 	oldStorageCopy := oldStorage.DeepCopy()
 	newStorageCopy := newStorage.DeepCopy()
@@ -313,67 +958,55 @@ func hasUpdatesBesidesFrozen(oldStorage, newStorage *Storage) (bool, string) {
 	return diff != "", diff
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *Storage) ValidateUpdate(old runtime.Object) error {
-	storagelog.Info("validate update", "name", r.Name)
-
-	var rawYamlConfiguration string
-	success, dynConfig, err := ParseDynConfig(r.Spec.Configuration)
-	if success {
-		if err != nil {
-			return fmt.Errorf("failed to parse dynconfig, error: %w", err)
-		}
-		config, err := yaml.Marshal(dynConfig.Config)
-		if err != nil {
-			return fmt.Errorf("failed to serialize YAML config, error: %w", err)
-		}
-		rawYamlConfiguration = string(config)
-	} else {
-		rawYamlConfiguration = r.Spec.Configuration
+// validateTopology refuses topology for configurations which static group
+// is not generated for: dynconfig requires `blob_storage_config`, static
+// group of unified configuration is managed by self-management and
+// configuration rendered from template is used as is
+func (r *Storage) validateTopology(dynConfig bool) error {
+	if r.Spec.Topology == nil {
+		return nil
 	}
-
-	var configuration schema.Configuration
-	configuration, err = ParseConfiguration(rawYamlConfiguration)
-	if err != nil {
-		return fmt.Errorf("failed to parse configuration, error: %w", err)
+	if dynConfig || r.Spec.ConfigurationVersion == ConfigurationV2 || r.Spec.ConfigurationTemplate != nil {
+		return errors.New("spec.topology is only supported with plain configuration of version v1, static group of this configuration is not generated")
 	}
-
-	var nodesNumber int32
-	if len(configuration.Hosts) == 0 {
-		nodesNumber = r.Spec.Nodes
-	} else {
-		nodesNumber = int32(len(configuration.Hosts))
+	if r.Spec.Erasure == ErasureMirror3DC && len(r.Spec.Topology.DataCenters) != 0 && len(r.Spec.Topology.DataCenters) != 3 {
+		return fmt.Errorf("erasure type %v requires exactly 3 data centers in spec.topology.dataCenters", r.Spec.Erasure)
 	}
-
-	minNodesPerErasure := map[ErasureType]int32{
-		ErasureMirror3DC: 3,
-		ErasureBlock42:   8,
-		None:             1,
+	seen := make(map[string]bool, len(r.Spec.Topology.DataCenters))
+	for _, dataCenter := range r.Spec.Topology.DataCenters {
+		if seen[dataCenter] {
+			return fmt.Errorf("data center %s is duplicated in spec.topology.dataCenters", dataCenter)
+		}
+		seen[dataCenter] = true
 	}
+	return nil
+}
 
-	if nodesNumber < minNodesPerErasure[r.Spec.Erasure] {
-		return fmt.Errorf("erasure type %v requires at least %v storage nodes", r.Spec.Erasure, minNodesPerErasure[r.Spec.Erasure])
+func validateErasureNodes(erasure ErasureType, nodesNumber int32) error {
+	minNodes, ok := MinNodesPerErasure[erasure]
+	if !ok {
+		return fmt.Errorf("unsupported erasure type %v", erasure)
 	}
+	if nodesNumber < minNodes {
+		return fmt.Errorf("erasure type %v requires at least %v storage nodes", erasure, minNodes)
+	}
+	return nil
+}
 
-	var authEnabled bool
-	if configuration.DomainsConfig.SecurityConfig != nil {
-		if configuration.DomainsConfig.SecurityConfig.EnforceUserTokenRequirement != nil {
-			authEnabled = *configuration.DomainsConfig.SecurityConfig.EnforceUserTokenRequirement
-		}
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Storage) ValidateUpdate(old runtime.Object) error {
+	storagelog.Info("validate update", "name", r.Name)
+
+	if err := validateDurationAnnotations(r.Annotations); err != nil {
+		return err
 	}
 
-	if authEnabled && r.Spec.OperatorConnection == nil {
-		return fmt.Errorf("field 'spec.operatorConnection' does not align with config option `enforce_user_token_requirement: %t`", authEnabled)
+	if err := r.ValidateSpec(); err != nil {
+		return err
 	}
 
-	if r.Spec.OperatorConnection != nil && r.Spec.OperatorConnection.Oauth2TokenExchange != nil {
-		auth := r.Spec.OperatorConnection.Oauth2TokenExchange
-		if auth.KeyID == nil {
-			return fmt.Errorf("field keyID is required for OAuth2TokenExchange type")
-		}
-		if !isSignAlgorithmSupported(auth.SignAlg) {
-			return fmt.Errorf("signAlg %s does not supported for OAuth2TokenExchange type", auth.SignAlg)
-		}
+	if err := r.validateImmutableFields(old.(*Storage)); err != nil {
+		return err
 	}
 
 	if !r.Spec.OperatorSync {
@@ -394,16 +1027,6 @@ func (r *Storage) ValidateUpdate(old runtime.Object) error {
 		}
 	}
 
-	if r.Spec.NodeSets != nil {
-		var nodesInSetsCount int32
-		for _, nodeSetInline := range r.Spec.NodeSets {
-			nodesInSetsCount += nodeSetInline.Nodes
-		}
-		if nodesInSetsCount != r.Spec.Nodes {
-			return fmt.Errorf("incorrect value nodes: %d, does not satisfy with nodeSets: %d ", r.Spec.Nodes, nodesInSetsCount)
-		}
-	}
-
 	crdCheckError := checkMonitoringCRD(manager, storagelog, r.Spec.Monitoring != nil)
 	if crdCheckError != nil {
 		return crdCheckError
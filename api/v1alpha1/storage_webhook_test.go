@@ -0,0 +1,603 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+func TestWebhooks(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhooks suite")
+}
+
+//nolint:all
+var storageConfigurationExample = `
+domains_config:
+  domain:
+  - name: Root
+    storage_pool_types:
+    - kind: ssd
+`
+
+func newTestStorage() *v1alpha1.Storage {
+	storage := &v1alpha1.Storage{}
+	storage.Name = "storage"
+	storage.Namespace = "ydb"
+	storage.Spec.Nodes = 8
+	storage.Spec.Domain = "Root"
+	storage.Spec.Erasure = v1alpha1.ErasureBlock42
+	storage.Spec.Configuration = storageConfigurationExample
+	// Skip lookup of monitoring CRDs which requires a manager
+	storage.Spec.Monitoring = &v1alpha1.MonitoringOptions{}
+	return storage
+}
+
+func newTestStoragePool(kind string, volumeMode corev1.PersistentVolumeMode) v1alpha1.StoragePool {
+	return v1alpha1.StoragePool{
+		Kind:                kind,
+		VolumeClaimTemplate: corev1.PersistentVolumeClaimSpec{VolumeMode: &volumeMode},
+	}
+}
+
+var _ = Describe("Storage webhook", func() {
+	It("rejects storage pools which kinds are not declared in configuration", func() {
+		storage := newTestStorage()
+		storage.Spec.StoragePools = []v1alpha1.StoragePool{
+			newTestStoragePool("ssd", corev1.PersistentVolumeBlock),
+			newTestStoragePool("rot", corev1.PersistentVolumeBlock),
+		}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("storage pool kind rot is not declared")))
+	})
+
+	It("accepts storage pools if configuration declares no storage pool types", func() {
+		storage := newTestStorage()
+		storage.Spec.Configuration = "domains_config:\n  domain:\n  - name: Root\n"
+		storage.Spec.StoragePools = []v1alpha1.StoragePool{
+			newTestStoragePool("ssd", corev1.PersistentVolumeBlock),
+			newTestStoragePool("rot", corev1.PersistentVolumeBlock),
+		}
+		Expect(storage.ValidateCreate()).To(Succeed())
+	})
+
+	It("rejects undeclared storage pool kinds of NodeSets", func() {
+		storage := newTestStorage()
+		storage.Spec.NodeSets = []v1alpha1.StorageNodeSetSpecInline{{
+			Name: "rot",
+			StorageNodeSpec: v1alpha1.StorageNodeSpec{
+				Nodes: 8,
+				StoragePools: []v1alpha1.StoragePool{
+					newTestStoragePool("rot", corev1.PersistentVolumeBlock),
+				},
+			},
+		}}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("nodeSet rot: storage pool kind rot is not declared")))
+	})
+
+	It("rejects duplicated storage pool kinds", func() {
+		storage := newTestStorage()
+		storage.Spec.StoragePools = []v1alpha1.StoragePool{
+			newTestStoragePool("ssd", corev1.PersistentVolumeBlock),
+			newTestStoragePool("ssd", corev1.PersistentVolumeBlock),
+		}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("more than once")))
+	})
+
+	It("rejects storage pool with Filesystem volume mode", func() {
+		storage := newTestStorage()
+		storage.Spec.StoragePools = []v1alpha1.StoragePool{
+			newTestStoragePool("ssd", corev1.PersistentVolumeFilesystem),
+		}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("`Block` volume mode")))
+	})
+
+	It("validates storage pools of NodeSets", func() {
+		storage := newTestStorage()
+		storage.Spec.NodeSets = []v1alpha1.StorageNodeSetSpecInline{{
+			Name: "rot",
+			StorageNodeSpec: v1alpha1.StorageNodeSpec{
+				Nodes: 8,
+				StoragePools: []v1alpha1.StoragePool{
+					newTestStoragePool("ssd", corev1.PersistentVolumeFilesystem),
+				},
+			},
+		}}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("nodeSet rot: storage pool ssd must use `Block` volume mode")))
+	})
+
+	It("rejects unsupported erasure type", func() {
+		storage := newTestStorage()
+		storage.Spec.Erasure = "mirror-2"
+		Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("unsupported erasure type")))
+	})
+
+	It("requires minimal number of nodes for erasure type", func() {
+		storage := newTestStorage()
+		storage.Spec.Erasure = v1alpha1.ErasureMirror3of4
+		storage.Spec.Nodes = 4
+		Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("requires at least 8 storage nodes")))
+
+		storage.Spec.Nodes = 8
+		Expect(storage.ValidateSpec()).To(Succeed())
+	})
+
+	It("accepts topology with plain configuration only", func() {
+		storage := newTestStorage()
+		storage.Spec.Topology = &v1alpha1.StorageTopology{}
+		Expect(storage.ValidateSpec()).To(Succeed())
+
+		unified := storage.DeepCopy()
+		unified.Spec.ConfigurationVersion = v1alpha1.ConfigurationV2
+		Expect(unified.ValidateSpec()).To(MatchError(ContainSubstring("spec.topology is only supported with plain configuration")))
+
+		dynConfig := storage.DeepCopy()
+		dynConfig.Spec.Configuration = `metadata:
+  kind: MainConfig
+  version: 0
+  cluster: ""
+config:
+  yaml_config_enabled: true
+  static_erasure: block-4-2
+  host_configs: []
+  blob_storage_config: {}
+`
+		Expect(dynConfig.ValidateSpec()).To(MatchError(ContainSubstring("spec.topology is only supported with plain configuration")))
+
+		templated := storage.DeepCopy()
+		templated.Spec.ConfigurationTemplate = &v1alpha1.ConfigurationTemplate{Inline: storage.Spec.Configuration}
+		Expect(templated.ValidateSpec()).To(MatchError(ContainSubstring("spec.topology is only supported with plain configuration")))
+	})
+
+	Context("additional resources", func() {
+		It("accepts allowed namespaced kinds", func() {
+			storage := newTestStorage()
+			storage.Spec.AdditionalResources = []runtime.RawExtension{{
+				Raw: []byte(`{"apiVersion":"networking.k8s.io/v1","kind":"NetworkPolicy","metadata":{"name":"storage-policy"}}`),
+			}}
+			Expect(storage.ValidateSpec()).To(Succeed())
+		})
+
+		It("rejects kinds which are not allowed", func() {
+			storage := newTestStorage()
+			storage.Spec.AdditionalResources = []runtime.RawExtension{{
+				Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"storage"}}`),
+			}}
+			Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("is not allowed")))
+		})
+
+		It("rejects objects from another namespace", func() {
+			storage := newTestStorage()
+			storage.Spec.AdditionalResources = []runtime.RawExtension{{
+				Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"storage-extra","namespace":"other"}}`),
+			}}
+			Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("namespace must be")))
+		})
+
+		It("rejects names of objects managed by operator", func() {
+			storage := newTestStorage()
+			storage.Spec.AdditionalResources = []runtime.RawExtension{{
+				Raw: []byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"storage-grpc"}}`),
+			}}
+			Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("is reserved")))
+
+			storage.Spec.AdditionalResources = []runtime.RawExtension{{
+				Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"storage"}}`),
+			}}
+			Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("is reserved")))
+		})
+	})
+
+	It("lists referenced secrets once", func() {
+		storage := newTestStorage()
+		pullSecret := "registry"
+		storage.Spec.Image = &v1alpha1.PodImage{PullSecret: &pullSecret}
+		storage.Spec.Secrets = []*corev1.LocalObjectReference{{Name: "extra"}}
+		tls := &v1alpha1.TLSConfiguration{
+			Enabled:              true,
+			CertificateAuthority: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "certs"}},
+			Certificate:          corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "certs"}},
+			Key:                  corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "certs"}},
+		}
+		storage.Spec.Service = &v1alpha1.StorageServices{
+			GRPC:         v1alpha1.GRPCService{TLSConfiguration: tls},
+			Interconnect: v1alpha1.InterconnectService{TLSConfiguration: tls},
+		}
+		Expect(storage.GetReferencedSecrets()).To(ConsistOf("extra", "certs", "registry"))
+	})
+
+	It("lists ConfigMaps of bs config requests as referenced", func() {
+		storage := newTestStorage()
+		storage.Spec.AdditionalBSConfig = []v1alpha1.BSConfigCommand{
+			{Proto: "Command { }"},
+			{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "bsconfig"},
+				Key:                  "pool.proto",
+			}},
+		}
+		Expect(storage.GetReferencedConfigMaps()).To(ConsistOf("bsconfig"))
+	})
+
+	It("rejects non-positive init Job requeue delay", func() {
+		storage := newTestStorage()
+		storage.Spec.InitJob = &v1alpha1.StorageInitJobSpec{
+			RequeueDelay: &metav1.Duration{},
+		}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("must be positive")))
+
+		storage.Spec.InitJob.RequeueDelay = &metav1.Duration{Duration: time.Minute}
+		Expect(storage.ValidateCreate()).To(Succeed())
+	})
+
+	It("rejects additional ports clashing with operator managed ports", func() {
+		storage := newTestStorage()
+		storage.Spec.AdditionalPorts = []v1alpha1.ServicePort{{Name: "pgwire", Port: 5432}}
+		Expect(storage.ValidateCreate()).To(Succeed())
+
+		storage.Spec.AdditionalPorts = append(storage.Spec.AdditionalPorts, v1alpha1.ServicePort{Name: "grpc", Port: 2136})
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("name grpc is already in use")))
+
+		storage.Spec.AdditionalPorts[1] = v1alpha1.ServicePort{Name: "kafka", Port: v1alpha1.StatusPort}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("port 8765 is already in use")))
+	})
+
+	Context("image pull policy", func() {
+		It("defaults to Always for mutable image tag", func() {
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			storage.Spec.Image = &v1alpha1.PodImage{Name: "cr.yandex/ydb/ydb:latest"}
+			Expect((&v1alpha1.StorageDefaulter{}).Default(context.Background(), storage)).To(Succeed())
+			Expect(*storage.Spec.Image.PullPolicyName).To(Equal(corev1.PullAlways))
+		})
+
+		It("defaults to IfNotPresent for pinned image tag", func() {
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			storage.Spec.Image = &v1alpha1.PodImage{Name: "localhost:5000/ydb:23.3.17"}
+			Expect((&v1alpha1.StorageDefaulter{}).Default(context.Background(), storage)).To(Succeed())
+			Expect(*storage.Spec.Image.PullPolicyName).To(Equal(corev1.PullIfNotPresent))
+		})
+
+		It("defaults to IfNotPresent for image pinned by digest", func() {
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			storage.Spec.Image = &v1alpha1.PodImage{Name: "cr.yandex/ydb/ydb:latest@sha256:" + strings.Repeat("a", 64)}
+			Expect((&v1alpha1.StorageDefaulter{}).Default(context.Background(), storage)).To(Succeed())
+			Expect(*storage.Spec.Image.PullPolicyName).To(Equal(corev1.PullIfNotPresent))
+		})
+
+		It("rejects image with incorrect digest", func() {
+			storage := newTestStorage()
+			storage.Spec.Image = &v1alpha1.PodImage{Name: "cr.yandex/ydb/ydb@sha256:" + strings.Repeat("a", 64)}
+			Expect(storage.ValidateSpec()).To(Succeed())
+
+			storage.Spec.Image.Name = "cr.yandex/ydb/ydb@24.2.7"
+			Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("incorrect digest")))
+		})
+
+		It("keeps pull policy set by user", func() {
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			policy := corev1.PullNever
+			storage.Spec.Image = &v1alpha1.PodImage{Name: "cr.yandex/ydb/ydb:latest", PullPolicyName: &policy}
+			Expect((&v1alpha1.StorageDefaulter{}).Default(context.Background(), storage)).To(Succeed())
+			Expect(*storage.Spec.Image.PullPolicyName).To(Equal(corev1.PullNever))
+		})
+	})
+
+	It("rejects changing log volume of existing Storage", func() {
+		oldStorage := newTestStorage()
+		storage := newTestStorage()
+		storage.Spec.LogVolume = &v1alpha1.LogVolumeSpec{}
+		Expect(storage.ValidateUpdate(oldStorage)).To(MatchError(ContainSubstring("spec.logVolume")))
+	})
+
+	Context("immutable fields", func() {
+		withVolumeSize := func(size string) corev1.PersistentVolumeClaimSpec {
+			volumeMode := corev1.PersistentVolumeBlock
+			return corev1.PersistentVolumeClaimSpec{
+				VolumeMode: &volumeMode,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			}
+		}
+
+		DescribeTable("rejects changes of existing Storage",
+			func(update func(oldStorage, storage *v1alpha1.Storage), message string) {
+				oldStorage := newTestStorage()
+				storage := newTestStorage()
+				update(oldStorage, storage)
+				Expect(storage.ValidateUpdate(oldStorage)).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("domain", func(_, storage *v1alpha1.Storage) {
+				storage.Spec.Domain = "Other"
+				storage.Spec.Configuration = strings.ReplaceAll(storage.Spec.Configuration, "Root", "Other")
+			}, "domain cannot be changed"),
+			Entry("erasure", func(_, storage *v1alpha1.Storage) {
+				storage.Spec.Erasure = v1alpha1.None
+			}, "field 'spec.erasure' cannot be changed from block-4-2 to none"),
+			Entry("configuration version", func(oldStorage, storage *v1alpha1.Storage) {
+				oldStorage.Spec.ConfigurationVersion = v1alpha1.ConfigurationV1
+				storage.Spec.ConfigurationVersion = v1alpha1.ConfigurationV2
+			}, "spec.configurationVersion"),
+			Entry("log volume", func(_, storage *v1alpha1.Storage) {
+				storage.Spec.LogVolume = &v1alpha1.LogVolumeSpec{}
+			}, "spec.logVolume"),
+			Entry("ephemeral data store", func(_, storage *v1alpha1.Storage) {
+				storage.Spec.EphemeralDataStore = true
+			}, "spec.ephemeralDataStore"),
+			Entry("decreased number of storage groups", func(oldStorage, storage *v1alpha1.Storage) {
+				oldStorage.Spec.Groups = ptr.Int32(4)
+				storage.Spec.Groups = ptr.Int32(2)
+			}, "spec.groups"),
+			Entry("decreased data store volume size", func(oldStorage, storage *v1alpha1.Storage) {
+				oldStorage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{withVolumeSize("80Gi")}
+				storage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{withVolumeSize("40Gi")}
+			}, "field 'spec.dataStore[0].resources.requests.storage' cannot be decreased from 80Gi to 40Gi"),
+			Entry("decreased storage pool volume size", func(oldStorage, storage *v1alpha1.Storage) {
+				oldStorage.Spec.StoragePools = []v1alpha1.StoragePool{{Kind: "ssd", VolumeClaimTemplate: withVolumeSize("1Ti")}}
+				storage.Spec.StoragePools = []v1alpha1.StoragePool{{Kind: "ssd", VolumeClaimTemplate: withVolumeSize("500Gi")}}
+			}, "field 'spec.storagePools[ssd].volumeClaimTemplate.resources.requests.storage' cannot be decreased"),
+		)
+
+		It("accepts increased volume size", func() {
+			oldStorage := newTestStorage()
+			oldStorage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{withVolumeSize("80Gi")}
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			storage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{withVolumeSize("120Gi")}
+			Expect(storage.ValidateUpdate(oldStorage)).To(Succeed())
+		})
+	})
+
+	It("rejects decreasing number of storage groups", func() {
+		oldStorage := newTestStorage()
+		oldStorage.Spec.Groups = ptr.Int32(4)
+		storage := newTestStorage()
+		storage.Spec.OperatorSync = true
+		storage.Spec.Groups = ptr.Int32(6)
+		Expect(storage.ValidateUpdate(oldStorage)).To(Succeed())
+
+		storage.Spec.Groups = ptr.Int32(2)
+		Expect(storage.ValidateUpdate(oldStorage)).To(MatchError(ContainSubstring("spec.groups")))
+	})
+
+	It("rejects invalid requeue delay annotation", func() {
+		storage := newTestStorage()
+		storage.Annotations = map[string]string{v1alpha1.AnnotationRequeueDelay: "often"}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring(v1alpha1.AnnotationRequeueDelay)))
+
+		storage.Annotations[v1alpha1.AnnotationRequeueDelay] = "1m"
+		Expect(storage.ValidateCreate()).To(Succeed())
+	})
+
+	It("rejects invalid rollout stuck timeout annotation", func() {
+		storage := newTestStorage()
+		storage.Annotations = map[string]string{v1alpha1.AnnotationRolloutStuckTimeout: "-10m"}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring(v1alpha1.AnnotationRolloutStuckTimeout)))
+
+		storage.Annotations[v1alpha1.AnnotationRolloutStuckTimeout] = "30m"
+		Expect(storage.ValidateCreate()).To(Succeed())
+	})
+
+	It("validates spec of updated Storage like a new one", func() {
+		oldStorage := newTestStorage()
+		storage := newTestStorage()
+		storage.Spec.OperatorSync = true
+		storage.Spec.Secrets = []*corev1.LocalObjectReference{{Name: "datastreams"}}
+		Expect(storage.ValidateUpdate(oldStorage)).To(MatchError(ContainSubstring("is reserved")))
+
+		storage.Spec.Secrets = nil
+		storage.Spec.Volumes = []*corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}}
+		Expect(storage.ValidateUpdate(oldStorage)).To(MatchError(ContainSubstring("Only hostPath is supported")))
+	})
+
+	It("requires either inline proto or ConfigMap reference of additional bs config", func() {
+		storage := newTestStorage()
+		storage.Spec.AdditionalBSConfig = []v1alpha1.BSConfigCommand{{}}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("spec.additionalBSConfig[0]")))
+
+		storage.Spec.AdditionalBSConfig[0].Proto = "Command { ReadBox { BoxId: 1 } }"
+		Expect(storage.ValidateCreate()).To(Succeed())
+	})
+
+	It("rejects storage pools with ephemeral data store", func() {
+		storage := newTestStorage()
+		storage.Spec.EphemeralDataStore = true
+		storage.Spec.StoragePools = []v1alpha1.StoragePool{newTestStoragePool("ssd", corev1.PersistentVolumeBlock)}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("ephemeral data store")))
+	})
+
+	It("checks health at endpoint override matching mechanism", func() {
+		storage := newTestStorage()
+		storage.Spec.HealthCheck = &v1alpha1.HealthCheckSpec{
+			Mechanism: v1alpha1.HealthCheckHTTP,
+			Endpoint:  "grpcs://storage.example.com:2136",
+		}
+		Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("spec.healthCheck.endpoint")))
+
+		storage.Spec.HealthCheck.Endpoint = "https://storage.example.com:8766"
+		Expect(storage.ValidateCreate()).To(Succeed())
+		Expect(storage.GetHealthCheckEndpoint()).To(Equal("https://storage.example.com:8766"))
+	})
+
+	It("checks health at custom port of status service", func() {
+		storage := newTestStorage()
+		storage.Spec.Service = &v1alpha1.StorageServices{}
+		storage.Spec.HealthCheck = &v1alpha1.HealthCheckSpec{Mechanism: v1alpha1.HealthCheckHTTP}
+		Expect(storage.GetHealthCheckEndpoint()).To(HaveSuffix(":8765"))
+
+		storage.Spec.HealthCheck.HTTPPort = 8766
+		Expect(storage.GetHealthCheckEndpoint()).To(HavePrefix("http://"))
+		Expect(storage.GetHealthCheckEndpoint()).To(HaveSuffix(":8766"))
+	})
+
+	Context("domain", func() {
+		It("rejects incorrect domain name", func() {
+			storage := newTestStorage()
+			storage.Spec.Domain = "Root/nested"
+			Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("incorrect domain name")))
+		})
+
+		It("rejects domain which is not declared in configuration", func() {
+			storage := newTestStorage()
+			storage.Spec.Domain = "Other"
+			Expect(storage.ValidateCreate()).To(MatchError(ContainSubstring("is not declared")))
+		})
+
+		It("rejects changing domain of existing Storage", func() {
+			oldStorage := newTestStorage()
+			storage := newTestStorage()
+			storage.Spec.Domain = "Other"
+			storage.Spec.Configuration = strings.ReplaceAll(storage.Spec.Configuration, "Root", "Other")
+			Expect(storage.ValidateUpdate(oldStorage)).To(MatchError(ContainSubstring("domain cannot be changed")))
+		})
+	})
+
+	Context("expected storage groups", func() {
+		It("expects a single group without static groups in configuration", func() {
+			Expect(newTestStorage().GetExpectedStorageGroups()).To(Equal(1))
+		})
+
+		It("expects every static group declared in configuration", func() {
+			storage := newTestStorage()
+			storage.Spec.Configuration += `
+blob_storage_config:
+  service_set:
+    groups:
+    - group_id: 0
+      erasure_species: block-4-2
+    - group_id: 1
+      erasure_species: block-4-2
+`
+			Expect(storage.GetExpectedStorageGroups()).To(Equal(2))
+		})
+	})
+
+	Context("configuration overlays", func() {
+		newOverlay := func(name, body string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ydb"},
+				Data:       map[string]string{"config.yaml": body},
+			}
+		}
+
+		It("merges overlays over base configuration in list order", func() {
+			configuration, err := v1alpha1.MergeConfigurationOverlays(
+				[]byte("actor_system_config:\n  use_auto_config: true\n  cpu_count: 4\nlog_config:\n  default_level: 5\n"),
+				[]string{
+					"actor_system_config:\n  cpu_count: 8\nlog_config:\n  entry: [{component: BS_CONTROLLER}]\n",
+					"actor_system_config:\n  cpu_count: 16\n",
+				},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(configuration)).To(Equal(`actor_system_config:
+    cpu_count: 16
+    use_auto_config: true
+log_config:
+    default_level: 5
+    entry:
+        - component: BS_CONTROLLER
+`))
+		})
+
+		It("applies overlays from ConfigMaps to Storage configuration", func() {
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			storage.Spec.ConfigOverlays = []corev1.ConfigMapKeySelector{
+				{LocalObjectReference: corev1.LocalObjectReference{Name: "base"}, Key: "config.yaml"},
+				{LocalObjectReference: corev1.LocalObjectReference{Name: "production"}, Key: "config.yaml"},
+			}
+			defaulter := &v1alpha1.StorageDefaulter{Client: fake.NewClientBuilder().WithObjects(
+				newOverlay("base", "feature_flags:\n  enable_views: false\n"),
+				newOverlay("production", "feature_flags:\n  enable_views: true\n"),
+			).Build()}
+
+			Expect(defaulter.Default(context.Background(), storage)).To(Succeed())
+			Expect(storage.Spec.Configuration).To(ContainSubstring("enable_views: true"))
+			Expect(storage.Spec.Configuration).To(ContainSubstring("name: Root"))
+			Expect(storage.ValidateSpec()).To(Succeed())
+		})
+
+		It("fails on missing overlay unless it is optional", func() {
+			storage := newTestStorage()
+			storage.Spec.OperatorSync = true
+			storage.Spec.ConfigOverlays = []corev1.ConfigMapKeySelector{
+				{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "config.yaml"},
+			}
+			defaulter := &v1alpha1.StorageDefaulter{Client: fake.NewClientBuilder().Build()}
+			Expect(defaulter.Default(context.Background(), storage)).
+				To(MatchError(ContainSubstring("failed to get configuration overlay ConfigMap missing")))
+
+			optional := true
+			storage.Spec.ConfigOverlays[0].Optional = &optional
+			Expect(defaulter.Default(context.Background(), storage)).To(Succeed())
+		})
+	})
+
+	It("sets keepalive of gRPC and interconnect in configuration", func() {
+		storage := newTestStorage()
+		storage.Spec.Configuration += "grpc_config:\n  keep_alive_idle_timeout_trigger_sec: 90\n  port: 2135\n"
+		storage.Spec.Keepalive = &v1alpha1.KeepaliveSpec{
+			GRPC: &v1alpha1.GRPCKeepalive{
+				IdleTimeoutSeconds: ptr.Int32(30),
+				MaxProbeCount:      ptr.Int32(5),
+			},
+			Interconnect: &v1alpha1.InterconnectKeepalive{
+				DeadPeerTimeoutSeconds: ptr.Int32(20),
+			},
+		}
+
+		configuration, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(configuration)).To(ContainSubstring("keep_alive_enable: true"))
+		Expect(string(configuration)).To(ContainSubstring("keep_alive_idle_timeout_trigger_sec: 30"))
+		Expect(string(configuration)).To(ContainSubstring("keep_alive_max_probe_count: 5"))
+		Expect(string(configuration)).To(ContainSubstring("port: 2135"))
+		Expect(string(configuration)).NotTo(ContainSubstring("keep_alive_probe_interval_sec"))
+		Expect(string(configuration)).To(ContainSubstring("dead_peer_timeout_duration:\n        seconds: 20"))
+	})
+
+	It("rejects sidecars clashing with containers of the pod", func() {
+		storage := newTestStorage()
+		storage.Spec.Sidecars = []corev1.Container{{Name: "vector"}}
+		Expect(storage.ValidateSpec()).To(Succeed())
+
+		storage.Spec.Sidecars = append(storage.Spec.Sidecars, corev1.Container{Name: v1alpha1.StorageContainerName})
+		Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("name ydb-storage is already in use")))
+
+		storage.Spec.Sidecars = []corev1.Container{{Name: "vector"}, {Name: "vector"}}
+		Expect(storage.ValidateSpec()).To(MatchError(ContainSubstring("name vector is already in use")))
+	})
+
+	It("checks YDB version of image against minimum supported one", func() {
+		image := &v1alpha1.PodImage{Name: "cr.yandex/ydb/ydb:24.2.7"}
+		Expect(v1alpha1.CheckYDBVersion(image, "")).To(Succeed())
+
+		image.Name = "cr.yandex/ydb/ydb:21.4.30"
+		Expect(v1alpha1.CheckYDBVersion(image, "")).To(MatchError(ContainSubstring("YDB version 21.4.30 is not supported")))
+
+		image.Name = "cr.yandex/ydb/ydb:latest"
+		Expect(v1alpha1.CheckYDBVersion(image, "")).To(Succeed())
+		Expect(v1alpha1.CheckYDBVersion(image, "22.1.5")).To(HaveOccurred())
+		Expect(v1alpha1.CheckYDBVersion(image, "22.2")).To(Succeed())
+
+		image.Name = "cr.yandex/ydb/ydb@sha256:" + strings.Repeat("a", 64)
+		Expect(v1alpha1.CheckYDBVersion(image, "22.1.5")).To(Succeed())
+	})
+})
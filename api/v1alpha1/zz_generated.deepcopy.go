@@ -7,6 +7,7 @@ package v1alpha1
 
 import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,6 +33,61 @@ func (in *AccessTokenAuth) DeepCopy() *AccessTokenAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BSConfigCommand) DeepCopyInto(out *BSConfigCommand) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BSConfigCommand.
+func (in *BSConfigCommand) DeepCopy() *BSConfigCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(BSConfigCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputationalUnit) DeepCopyInto(out *ComputationalUnit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputationalUnit.
+func (in *ComputationalUnit) DeepCopy() *ComputationalUnit {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputationalUnit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationTemplate) DeepCopyInto(out *ConfigurationTemplate) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationTemplate.
+func (in *ConfigurationTemplate) DeepCopy() *ConfigurationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionOptions) DeepCopyInto(out *ConnectionOptions) {
 	*out = *in
@@ -50,6 +106,11 @@ func (in *ConnectionOptions) DeepCopyInto(out *ConnectionOptions) {
 		*out = new(Oauth2TokenExchange)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ServiceAccountKey != nil {
+		in, out := &in.ServiceAccountKey, &out.ServiceAccountKey
+		*out = new(ServiceAccountKeyAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionOptions.
@@ -62,6 +123,21 @@ func (in *ConnectionOptions) DeepCopy() *ConnectionOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionSecretSpec) DeepCopyInto(out *ConnectionSecretSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionSecretSpec.
+func (in *ConnectionSecretSpec) DeepCopy() *ConnectionSecretSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CredentialSource) DeepCopyInto(out *CredentialSource) {
 	*out = *in
@@ -109,10 +185,30 @@ func (in *Database) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseAutoscaling) DeepCopyInto(out *DatabaseAutoscaling) {
+	*out = *in
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseAutoscaling.
+func (in *DatabaseAutoscaling) DeepCopy() *DatabaseAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseClusterSpec) DeepCopyInto(out *DatabaseClusterSpec) {
 	*out = *in
-	out.StorageClusterRef = in.StorageClusterRef
+	in.StorageClusterRef.DeepCopyInto(&out.StorageClusterRef)
 	if in.ServerlessResources != nil {
 		in, out := &in.ServerlessResources, &out.ServerlessResources
 		*out = new(ServerlessDatabaseResources)
@@ -135,6 +231,13 @@ func (in *DatabaseClusterSpec) DeepCopyInto(out *DatabaseClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
 		*out = new(DatabaseServices)
@@ -145,6 +248,18 @@ func (in *DatabaseClusterSpec) DeepCopyInto(out *DatabaseClusterSpec) {
 		*out = new(DatastreamsConfig)
 		**out = **in
 	}
+	if in.Postgres != nil {
+		in, out := &in.Postgres, &out.Postgres
+		*out = new(PostgresConfig)
+		**out = **in
+	}
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringOptions)
@@ -172,6 +287,21 @@ func (in *DatabaseClusterSpec) DeepCopyInto(out *DatabaseClusterSpec) {
 			}
 		}
 	}
+	if in.ScratchSpace != nil {
+		in, out := &in.ScratchSpace, &out.ScratchSpace
+		*out = new(ScratchSpaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterSpec.
@@ -479,6 +609,11 @@ func (in *DatabaseNodeSpec) DeepCopyInto(out *DatabaseNodeSpec) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodePool != nil {
+		in, out := &in.NodePool, &out.NodePool
+		*out = new(NodePool)
+		**out = **in
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -533,6 +668,16 @@ func (in *DatabaseResources) DeepCopyInto(out *DatabaseResources) {
 		*out = make([]StorageUnit, len(*in))
 		copy(*out, *in)
 	}
+	if in.ComputationalUnits != nil {
+		in, out := &in.ComputationalUnits, &out.ComputationalUnits
+		*out = make([]ComputationalUnit, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchemaOperationQuotas != nil {
+		in, out := &in.SchemaOperationQuotas, &out.SchemaOperationQuotas
+		*out = make([]SchemaOperationQuota, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseResources.
@@ -552,6 +697,7 @@ func (in *DatabaseServices) DeepCopyInto(out *DatabaseServices) {
 	in.Interconnect.DeepCopyInto(&out.Interconnect)
 	in.Status.DeepCopyInto(&out.Status)
 	in.Datastreams.DeepCopyInto(&out.Datastreams)
+	in.Postgres.DeepCopyInto(&out.Postgres)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseServices.
@@ -576,6 +722,40 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConnectionSecret != nil {
+		in, out := &in.ConnectionSecret, &out.ConnectionSecret
+		*out = new(ConnectionSecretSpec)
+		**out = **in
+	}
+	if in.AdditionalResources != nil {
+		in, out := &in.AdditionalResources, &out.AdditionalResources
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(DatabaseAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageAffinity != nil {
+		in, out := &in.StorageAffinity, &out.StorageAffinity
+		*out = new(StorageAffinity)
+		**out = **in
+	}
+	if in.SourceDatabaseRef != nil {
+		in, out := &in.SourceDatabaseRef, &out.SourceDatabaseRef
+		*out = new(SourceDatabaseRef)
+		**out = **in
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make([]InitScript, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -598,6 +778,16 @@ func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = make([]ManagedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedInitScripts != nil {
+		in, out := &in.AppliedInitScripts, &out.AppliedInitScripts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
@@ -671,6 +861,36 @@ func (in *EncryptionConfig) DeepCopy() *EncryptionConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCKeepalive) DeepCopyInto(out *GRPCKeepalive) {
+	*out = *in
+	if in.IdleTimeoutSeconds != nil {
+		in, out := &in.IdleTimeoutSeconds, &out.IdleTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProbeIntervalSeconds != nil {
+		in, out := &in.ProbeIntervalSeconds, &out.ProbeIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxProbeCount != nil {
+		in, out := &in.MaxProbeCount, &out.MaxProbeCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCKeepalive.
+func (in *GRPCKeepalive) DeepCopy() *GRPCKeepalive {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCKeepalive)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GRPCService) DeepCopyInto(out *GRPCService) {
 	*out = *in
@@ -697,6 +917,21 @@ func (in *GRPCService) DeepCopy() *GRPCService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
+func (in *HealthCheckSpec) DeepCopy() *HealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPDiscovery) DeepCopyInto(out *IPDiscovery) {
 	*out = *in
@@ -712,6 +947,51 @@ func (in *IPDiscovery) DeepCopy() *IPDiscovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitScript) DeepCopyInto(out *InitScript) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitScript.
+func (in *InitScript) DeepCopy() *InitScript {
+	if in == nil {
+		return nil
+	}
+	out := new(InitScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectKeepalive) DeepCopyInto(out *InterconnectKeepalive) {
+	*out = *in
+	if in.HeartbeatIntervalSeconds != nil {
+		in, out := &in.HeartbeatIntervalSeconds, &out.HeartbeatIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeadPeerTimeoutSeconds != nil {
+		in, out := &in.DeadPeerTimeoutSeconds, &out.DeadPeerTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectKeepalive.
+func (in *InterconnectKeepalive) DeepCopy() *InterconnectKeepalive {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectKeepalive)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterconnectService) DeepCopyInto(out *InterconnectService) {
 	*out = *in
@@ -768,6 +1048,62 @@ func (in *JWTHeader) DeepCopy() *JWTHeader {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeepaliveSpec) DeepCopyInto(out *KeepaliveSpec) {
+	*out = *in
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPCKeepalive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Interconnect != nil {
+		in, out := &in.Interconnect, &out.Interconnect
+		*out = new(InterconnectKeepalive)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeepaliveSpec.
+func (in *KeepaliveSpec) DeepCopy() *KeepaliveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeepaliveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogVolumeSpec) DeepCopyInto(out *LogVolumeSpec) {
+	*out = *in
+	in.VolumeClaimTemplate.DeepCopyInto(&out.VolumeClaimTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogVolumeSpec.
+func (in *LogVolumeSpec) DeepCopy() *LogVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedResource) DeepCopyInto(out *ManagedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedResource.
+func (in *ManagedResource) DeepCopy() *ManagedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitoringOptions) DeepCopyInto(out *MonitoringOptions) {
 	*out = *in
@@ -809,6 +1145,21 @@ func (in *NamespacedRef) DeepCopy() *NamespacedRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Oauth2TokenExchange) DeepCopyInto(out *Oauth2TokenExchange) {
 	*out = *in
@@ -856,6 +1207,37 @@ func (in *PodImage) DeepCopy() *PodImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfig) DeepCopyInto(out *PostgresConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfig.
+func (in *PostgresConfig) DeepCopy() *PostgresConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresService) DeepCopyInto(out *PostgresService) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresService.
+func (in *PostgresService) DeepCopy() *PostgresService {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteDatabaseNodeSet) DeepCopyInto(out *RemoteDatabaseNodeSet) {
 	*out = *in
@@ -1069,6 +1451,46 @@ func (in *RemoteStorageNodeSetStatus) DeepCopy() *RemoteStorageNodeSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaOperationQuota) DeepCopyInto(out *SchemaOperationQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaOperationQuota.
+func (in *SchemaOperationQuota) DeepCopy() *SchemaOperationQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaOperationQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScratchSpaceSpec) DeepCopyInto(out *ScratchSpaceSpec) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScratchSpaceSpec.
+func (in *ScratchSpaceSpec) DeepCopy() *ScratchSpaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScratchSpaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerlessDatabaseResources) DeepCopyInto(out *ServerlessDatabaseResources) {
 	*out = *in
@@ -1124,6 +1546,58 @@ func (in *Service) DeepCopy() *Service {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountKeyAuth) DeepCopyInto(out *ServiceAccountKeyAuth) {
+	*out = *in
+	if in.CredentialSource != nil {
+		in, out := &in.CredentialSource, &out.CredentialSource
+		*out = new(CredentialSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountKeyAuth.
+func (in *ServiceAccountKeyAuth) DeepCopy() *ServiceAccountKeyAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountKeyAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePort) DeepCopyInto(out *ServicePort) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePort.
+func (in *ServicePort) DeepCopy() *ServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(ServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceDatabaseRef) DeepCopyInto(out *SourceDatabaseRef) {
+	*out = *in
+	out.NamespacedRef = in.NamespacedRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceDatabaseRef.
+func (in *SourceDatabaseRef) DeepCopy() *SourceDatabaseRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceDatabaseRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StaticCredentialsAuth) DeepCopyInto(out *StaticCredentialsAuth) {
 	*out = *in
@@ -1192,9 +1666,50 @@ func (in *Storage) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAffinity) DeepCopyInto(out *StorageAffinity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAffinity.
+func (in *StorageAffinity) DeepCopy() *StorageAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClusterRef) DeepCopyInto(out *StorageClusterRef) {
+	*out = *in
+	out.NamespacedRef = in.NamespacedRef
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClusterRef.
+func (in *StorageClusterRef) DeepCopy() *StorageClusterRef {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClusterRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageClusterSpec) DeepCopyInto(out *StorageClusterSpec) {
 	*out = *in
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(StorageTopology)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(PodImage)
@@ -1207,11 +1722,35 @@ func (in *StorageClusterSpec) DeepCopyInto(out *StorageClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogVolume != nil {
+		in, out := &in.LogVolume, &out.LogVolume
+		*out = new(LogVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
 		*out = new(StorageServices)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalPorts != nil {
+		in, out := &in.AdditionalPorts, &out.AdditionalPorts
+		*out = make([]ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringOptions)
@@ -1239,6 +1778,16 @@ func (in *StorageClusterSpec) DeepCopyInto(out *StorageClusterSpec) {
 			}
 		}
 	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClusterSpec.
@@ -1251,6 +1800,21 @@ func (in *StorageClusterSpec) DeepCopy() *StorageClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageGroupsStatus) DeepCopyInto(out *StorageGroupsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageGroupsStatus.
+func (in *StorageGroupsStatus) DeepCopy() *StorageGroupsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageGroupsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageInitJobSpec) DeepCopyInto(out *StorageInitJobSpec) {
 	*out = *in
@@ -1292,6 +1856,16 @@ func (in *StorageInitJobSpec) DeepCopyInto(out *StorageInitJobSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequeueDelay != nil {
+		in, out := &in.RequeueDelay, &out.RequeueDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageInitJobSpec.
@@ -1584,6 +2158,18 @@ func (in *StorageNodeSpec) DeepCopyInto(out *StorageNodeSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
+	if in.StoragePools != nil {
+		in, out := &in.StoragePools, &out.StoragePools
+		*out = make([]StoragePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(v1.ResourceRequirements)
@@ -1646,6 +2232,57 @@ func (in *StorageNodeSpec) DeepCopy() *StorageNodeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeStatus) DeepCopyInto(out *StorageNodeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeStatus.
+func (in *StorageNodeStatus) DeepCopy() *StorageNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePool) DeepCopyInto(out *StoragePool) {
+	*out = *in
+	in.VolumeClaimTemplate.DeepCopyInto(&out.VolumeClaimTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePool.
+func (in *StoragePool) DeepCopy() *StoragePool {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageRollingUpdate) DeepCopyInto(out *StorageRollingUpdate) {
+	*out = *in
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageRollingUpdate.
+func (in *StorageRollingUpdate) DeepCopy() *StorageRollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageServices) DeepCopyInto(out *StorageServices) {
 	*out = *in
@@ -1686,6 +2323,52 @@ func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(StorageRollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckSpec)
+		**out = **in
+	}
+	if in.Keepalive != nil {
+		in, out := &in.Keepalive, &out.Keepalive
+		*out = new(KeepaliveSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AdditionalResources != nil {
+		in, out := &in.AdditionalResources, &out.AdditionalResources
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigurationTemplate != nil {
+		in, out := &in.ConfigurationTemplate, &out.ConfigurationTemplate
+		*out = new(ConfigurationTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigOverlays != nil {
+		in, out := &in.ConfigOverlays, &out.ConfigOverlays
+		*out = make([]v1.ConfigMapKeySelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalBSConfig != nil {
+		in, out := &in.AdditionalBSConfig, &out.AdditionalBSConfig
+		*out = make([]BSConfigCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
@@ -1708,6 +2391,41 @@ func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = new(StorageGroupsStatus)
+		**out = **in
+	}
+	if in.AppliedBSConfig != nil {
+		in, out := &in.AppliedBSConfig, &out.AppliedBSConfig
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdatePartition != nil {
+		in, out := &in.UpdatePartition, &out.UpdatePartition
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]StorageNodeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreviousImage != nil {
+		in, out := &in.PreviousImage, &out.PreviousImage
+		*out = new(PodImage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = make([]ManagedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.TenantCapacity != nil {
+		in, out := &in.TenantCapacity, &out.TenantCapacity
+		*out = new(StorageTenantCapacity)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
@@ -1720,6 +2438,41 @@ func (in *StorageStatus) DeepCopy() *StorageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageTenantCapacity) DeepCopyInto(out *StorageTenantCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageTenantCapacity.
+func (in *StorageTenantCapacity) DeepCopy() *StorageTenantCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageTenantCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageTopology) DeepCopyInto(out *StorageTopology) {
+	*out = *in
+	if in.DataCenters != nil {
+		in, out := &in.DataCenters, &out.DataCenters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageTopology.
+func (in *StorageTopology) DeepCopy() *StorageTopology {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageTopology)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageUnit) DeepCopyInto(out *StorageUnit) {
 	*out = *in
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -17,6 +20,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ydbv1alpha1 "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/database"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/databasenodeset"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/monitoring"
@@ -24,6 +28,10 @@ import (
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/remotestoragenodeset"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/storage"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/storagenodeset"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/probes"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/shutdown"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/tracing"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ydbctl"
 )
 
 var (
@@ -39,6 +47,10 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == ydbctl.Command {
+		os.Exit(ydbctl.Run(ctrl.SetupSignalHandler(), os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var disableWebhooks bool
@@ -46,6 +58,15 @@ func main() {
 	var probeAddr string
 	var mgmtClusterKubeconfig string
 	var mgmtClusterName string
+	var maxConcurrentReconciles int
+	var mutableImageTags string
+	var minYDBVersion string
+	var skipStorageInit bool
+	var cmsOperationTimeout time.Duration
+	var cmsPollInterval time.Duration
+	var readyzRequireLeader bool
+	var shutdownDrainTimeout time.Duration
+	var otlpEndpoint string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -55,6 +76,19 @@ func main() {
 	flag.BoolVar(&enableServiceMonitors, "with-service-monitors", false, "Enables service monitoring")
 	flag.StringVar(&mgmtClusterKubeconfig, "mgmt-cluster-kubeconfig", "/mgmt-cluster/kubeconfig", "Path to kubeconfig for mgmt remote k8s cluster. Only required if using Remote objects")
 	flag.StringVar(&mgmtClusterName, "mgmt-cluster-name", "", "The name of mgmt remote cluster to sync k8s resources. Only required if using Remote objects")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "The maximum number of concurrent reconciles for Storage and Database controllers.")
+	flag.StringVar(&mutableImageTags, "mutable-image-tags", "latest", "Comma-separated list of image tags which are pulled with policy Always by default.")
+	flag.StringVar(&minYDBVersion, "min-ydb-version", ydbv1alpha1.MinYDBVersion, "Minimum YDB version which is rolled out to Storages and Databases, empty value disables the check.")
+	flag.BoolVar(&skipStorageInit, "skip-storage-init", false, "Skip initialization of all Storages, e.g. when bootstrap is performed by managed control plane.")
+	flag.DurationVar(&cmsOperationTimeout, "cms-operation-timeout", cms.DefaultOperationTimeout, "How long CMS operation, e.g. tenant creation, may run before it is reported as a retryable error.")
+	flag.DurationVar(&cmsPollInterval, "cms-poll-interval", cms.DefaultOperationPollInterval, "Interval of requeueing reconcile to check running CMS operation.")
+	flag.BoolVar(&readyzRequireLeader, "readyz-require-leader", false,
+		"Report replica ready only after it won leader election, so that standby replicas are not ready. "+
+			"Requires --leader-elect and a rollout strategy which does not wait for the new replica to become ready.")
+	flag.DurationVar(&shutdownDrainTimeout, "shutdown-drain-timeout", shutdown.DefaultDrainTimeout,
+		"How long in-flight critical operations, e.g. exec into Storage pods or CMS requests, are let to finish on operator shutdown.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"OTLP gRPC endpoint to export traces of reconciles to, e.g. otel-collector:4317. Tracing is disabled if empty.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -63,10 +97,28 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	ydbv1alpha1.MutableImageTags = splitList(mutableImageTags)
+	ydbv1alpha1.MinYDBVersion = minYDBVersion
+	shutdown.DrainTimeout = shutdownDrainTimeout
+
+	ctx := ctrl.SetupSignalHandler()
+	shutdownTracing, err := tracing.Setup(ctx, otlpEndpoint)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	if skipStorageInit {
+		setupLog.Info("Storage initialization is disabled for all Storages with --skip-storage-init")
+	}
+
 	if enableServiceMonitors {
 		utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	}
 
+	// Reconciles are waited on shutdown for a bit longer than critical
+	// operations in flight are drained
+	gracefulShutdownTimeout := shutdownDrainTimeout + 5*time.Second
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
@@ -74,6 +126,8 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "a14e577a.ydb.tech",
+
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -85,6 +139,12 @@ func main() {
 		Scheme:   mgr.GetScheme(),
 		Config:   mgr.GetConfig(),
 		Recorder: mgr.GetEventRecorderFor("ydb-operator"),
+
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		CMSPollOptions: cms.PollOptions{
+			Timeout:  cmsOperationTimeout,
+			Interval: cmsPollInterval,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Database")
 		os.Exit(1)
@@ -95,7 +155,9 @@ func main() {
 		Config:   mgr.GetConfig(),
 		Recorder: mgr.GetEventRecorderFor("ydb-operator"),
 
-		WithServiceMonitors: enableServiceMonitors,
+		WithServiceMonitors:     enableServiceMonitors,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		SkipStorageInit:         skipStorageInit,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Storage")
 		os.Exit(1)
@@ -188,16 +250,31 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	var elected <-chan struct{}
+	if enableLeaderElection && readyzRequireLeader {
+		elected = mgr.Elected()
+	}
+	readiness := probes.NewReadiness(mgr.GetCache(), elected)
+	if err := mgr.Add(readiness); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", readiness.Check); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
 }
 
 func createRemoteCluster(mgmtClusterName, mgmtClusterKubeconfig string) (cluster.Cluster, error) {
@@ -229,3 +306,15 @@ func createRemoteCluster(mgmtClusterName, mgmtClusterKubeconfig string) (cluster
 		})
 	})
 }
+
+// splitList splits comma-separated flag value, surrounding spaces and
+// empty entries are dropped
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -114,9 +114,11 @@ func DefaultDatabase() *v1alpha1.Database {
 			DatabaseClusterSpec: v1alpha1.DatabaseClusterSpec{
 				Domain:       DefaultDomain,
 				OperatorSync: true,
-				StorageClusterRef: v1alpha1.NamespacedRef{
-					Name:      StorageName,
-					Namespace: YdbNamespace,
+				StorageClusterRef: v1alpha1.StorageClusterRef{
+					NamespacedRef: v1alpha1.NamespacedRef{
+						Name:      StorageName,
+						Namespace: YdbNamespace,
+					},
 				},
 				Image: &v1alpha1.PodImage{
 					Name:           YdbImage,
@@ -6,8 +6,11 @@ const (
 	RemoteResourceVersionAnnotation   = "ydb.tech/remote-resource-version"
 	ConfigurationChecksum             = "ydb.tech/configuration-checksum"
 	StorageFinalizerKey               = "ydb.tech/storage-finalizer"
+	DatabaseFinalizerKey              = "ydb.tech/database-finalizer"
 	RemoteFinalizerKey                = "ydb.tech/remote-finalizer"
 	LastAppliedAnnotation             = "ydb.tech/last-applied"
+	EncryptionKeyVersion              = "ydb.tech/encryption-key-version"
+	OperatorToken                     = "ydb.tech/operator-token"
 )
 
 func CompareLastAppliedAnnotation(map1, map2 map[string]string) bool {
@@ -0,0 +1,77 @@
+package cms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/Ydb_Operation_V1"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/connection"
+)
+
+const ListOperationsTimeoutSeconds = 10
+
+// ExportOperationKinds are kinds of operations which export tables of the
+// database to external storage, i.e. backups of the database
+var ExportOperationKinds = []string{"export/s3", "export/yt"}
+
+// ListRunningExports returns ids of export operations of the database at
+// endpoint which are not finished yet
+func ListRunningExports(
+	ctx context.Context,
+	endpoint string,
+	opts ...ydb.Option,
+) ([]string, error) {
+	logger := log.FromContext(ctx)
+
+	conn, err := connection.Open(ctx, endpoint, ydb.MergeOptions(opts...))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to YDB: %w", err)
+	}
+	defer func() {
+		connection.Close(ctx, conn)
+	}()
+
+	client := Ydb_Operation_V1.NewOperationServiceClient(ydb.GRPCConn(conn))
+
+	var running []string
+	for _, kind := range ExportOperationKinds {
+		request := &Ydb_Operations.ListOperationsRequest{Kind: kind}
+		for {
+			logger.Info("ListOperations request", "endpoint", endpoint, "request", request)
+			listCtx, listCtxCancel := context.WithTimeout(ctx, ListOperationsTimeoutSeconds*time.Second)
+			response, err := client.ListOperations(listCtx, request)
+			listCtxCancel()
+			if err != nil {
+				return nil, err
+			}
+			if response.GetStatus() != Ydb.StatusIds_SUCCESS {
+				return nil, fmt.Errorf("YDB response error: %v %v", response.GetStatus(), response.GetIssues())
+			}
+
+			running = append(running, RunningOperationIDs(response.GetOperations())...)
+			if response.GetNextPageToken() == "" {
+				break
+			}
+			request.PageToken = response.GetNextPageToken()
+		}
+	}
+
+	return running, nil
+}
+
+// RunningOperationIDs returns ids of operations which are not ready yet
+func RunningOperationIDs(operations []*Ydb_Operations.Operation) []string {
+	var running []string
+	for _, operation := range operations {
+		if GetOperationState(operation) == OperationStateRunning {
+			running = append(running, operation.GetId())
+		}
+	}
+	return running
+}
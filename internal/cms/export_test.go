@@ -0,0 +1,25 @@
+package cms_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+)
+
+var _ = Describe("Export", func() {
+	It("returns ids of running operations only", func() {
+		operations := []*Ydb_Operations.Operation{
+			{Id: "running"},
+			{Id: "succeeded", Ready: true, Status: Ydb.StatusIds_SUCCESS},
+			{Id: "cancelled", Ready: true, Status: Ydb.StatusIds_CANCELLED},
+		}
+		Expect(cms.RunningOperationIDs(operations)).To(Equal([]string{"running"}))
+	})
+
+	It("returns nothing without operations", func() {
+		Expect(cms.RunningOperationIDs(nil)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,205 @@
+package cms
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultIAMTokenEndpoint = "https://iam.api.cloud.yandex.net/iam/v1/tokens"
+
+	IAMTokenRequestTimeoutSeconds = 10
+
+	// JWT is exchanged right away, short lifetime is enough
+	iamJWTLifetime = time.Hour
+	// IAM token is refreshed in advance to never send an expired one
+	iamTokenRefreshMargin = 5 * time.Minute
+)
+
+// ServiceAccountKey is an authorized key of a service account
+type ServiceAccountKey struct {
+	ID               string `json:"id"`
+	ServiceAccountID string `json:"service_account_id"`
+	PrivateKey       string `json:"private_key"`
+}
+
+// ParseServiceAccountKey reads authorized key in the JSON format it is
+// issued by IAM
+func ParseServiceAccountKey(data []byte) (*ServiceAccountKey, error) {
+	key := &ServiceAccountKey{}
+	if err := json.Unmarshal(data, key); err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if key.ID == "" || key.ServiceAccountID == "" || key.PrivateKey == "" {
+		return nil, errors.New("service account key must contain id, service_account_id and private_key")
+	}
+	return key, nil
+}
+
+// IAMTokenCredentials implement YDB credentials exchanging a JWT signed
+// by service account key for IAM token. Token is cached until it is about
+// to expire, so it is safe to share credentials between connections.
+type IAMTokenCredentials struct {
+	key        *ServiceAccountKey
+	privateKey *rsa.PrivateKey
+	endpoint   string
+	audience   string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewIAMTokenCredentials(
+	key *ServiceAccountKey,
+	endpoint string,
+	audience string,
+	httpClient *http.Client,
+) (*IAMTokenCredentials, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key of service account key %s: %w", key.ID, err)
+	}
+	if endpoint == "" {
+		endpoint = DefaultIAMTokenEndpoint
+	}
+	if audience == "" {
+		audience = endpoint
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &IAMTokenCredentials{
+		key:        key,
+		privateKey: privateKey,
+		endpoint:   endpoint,
+		audience:   audience,
+		httpClient: httpClient,
+	}, nil
+}
+
+type iamTokenRequest struct {
+	JWT string `json:"jwt"`
+}
+
+type iamTokenResponse struct {
+	IAMToken  string    `json:"iamToken"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Token returns cached IAM token or requests a new one
+func (c *IAMTokenCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Add(iamTokenRefreshMargin).Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	response, err := c.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = response.IAMToken
+	c.expiresAt = response.ExpiresAt
+	return c.token, nil
+}
+
+func (c *IAMTokenCredentials) signJWT() (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.RegisteredClaims{
+		Issuer:    c.key.ServiceAccountID,
+		Audience:  jwt.ClaimStrings{c.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(iamJWTLifetime)),
+	})
+	token.Header["kid"] = c.key.ID
+	return token.SignedString(c.privateKey)
+}
+
+func (c *IAMTokenCredentials) requestToken(ctx context.Context) (*iamTokenResponse, error) {
+	logger := log.FromContext(ctx)
+
+	signed, err := c.signJWT()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign JWT with service account key %s: %w", c.key.ID, err)
+	}
+	body, err := json.Marshal(iamTokenRequest{JWT: signed})
+	if err != nil {
+		return nil, err
+	}
+
+	iamCtx, iamCtxCancel := context.WithTimeout(ctx, IAMTokenRequestTimeoutSeconds*time.Second)
+	defer iamCtxCancel()
+	request, err := http.NewRequestWithContext(iamCtx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	logger.Info("IAM token request", "endpoint", c.endpoint, "keyID", c.key.ID)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request IAM token: %w", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IAM token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("IAM token request failed with status %s: %s", response.Status, string(data))
+	}
+
+	result := &iamTokenResponse{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("failed to parse IAM token response: %w", err)
+	}
+	if result.IAMToken == "" {
+		return nil, errors.New("IAM token response does not contain token")
+	}
+	return result, nil
+}
+
+var (
+	iamCredentialsMu sync.Mutex
+	iamCredentials   = map[string]*IAMTokenCredentials{}
+)
+
+// SharedIAMTokenCredentials returns credentials cached per service account
+// key, endpoint and audience, so that IAM token survives between reconciles
+func SharedIAMTokenCredentials(
+	key *ServiceAccountKey,
+	endpoint string,
+	audience string,
+) (*IAMTokenCredentials, error) {
+	cacheKey := fmt.Sprintf("%s/%s/%s", key.ID, endpoint, audience)
+
+	iamCredentialsMu.Lock()
+	defer iamCredentialsMu.Unlock()
+
+	if creds, ok := iamCredentials[cacheKey]; ok && creds.key.PrivateKey == key.PrivateKey {
+		return creds, nil
+	}
+
+	creds, err := NewIAMTokenCredentials(key, endpoint, audience, nil)
+	if err != nil {
+		return nil, err
+	}
+	iamCredentials[cacheKey] = creds
+	return creds, nil
+}
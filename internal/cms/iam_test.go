@@ -0,0 +1,112 @@
+package cms_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+)
+
+var _ = Describe("IAM token credentials", func() {
+	var (
+		privateKey *rsa.PrivateKey
+		key        *cms.ServiceAccountKey
+		server     *httptest.Server
+		requests   atomic.Int32
+		tokenTTL   time.Duration
+	)
+
+	BeforeEach(func() {
+		var err error
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		Expect(err).ToNot(HaveOccurred())
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+		data, err := json.Marshal(map[string]string{
+			"id":                 "key-id",
+			"service_account_id": "sa-id",
+			"private_key":        string(keyPEM),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		key, err = cms.ParseServiceAccountKey(data)
+		Expect(err).ToNot(HaveOccurred())
+
+		requests.Store(0)
+		tokenTTL = time.Hour
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			n := requests.Add(1)
+
+			request := struct {
+				JWT string `json:"jwt"`
+			}{}
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+
+			claims := &jwt.RegisteredClaims{}
+			token, err := jwt.ParseWithClaims(request.JWT, claims, func(*jwt.Token) (interface{}, error) {
+				return &privateKey.PublicKey, nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token.Method).To(Equal(jwt.SigningMethodPS256))
+			Expect(token.Header["kid"]).To(Equal("key-id"))
+			Expect(claims.Issuer).To(Equal("sa-id"))
+			Expect(claims.VerifyAudience("audience", true)).To(BeTrue())
+
+			Expect(json.NewEncoder(w).Encode(map[string]interface{}{
+				"iamToken":  fmt.Sprintf("token-%d", n),
+				"expiresAt": time.Now().Add(tokenTTL).Format(time.RFC3339),
+			})).To(Succeed())
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("rejects incomplete service account key", func() {
+		_, err := cms.ParseServiceAccountKey([]byte(`{"id": "key-id"}`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("caches IAM token until it is about to expire", func() {
+		creds, err := cms.NewIAMTokenCredentials(key, server.URL, "audience", server.Client())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(creds.Token(context.Background())).To(Equal("token-1"))
+		Expect(creds.Token(context.Background())).To(Equal("token-1"))
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("refreshes IAM token which is about to expire", func() {
+		tokenTTL = time.Minute
+		creds, err := cms.NewIAMTokenCredentials(key, server.URL, "audience", server.Client())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(creds.Token(context.Background())).To(Equal("token-1"))
+		Expect(creds.Token(context.Background())).To(Equal("token-2"))
+		Expect(requests.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("shares credentials of the same service account key", func() {
+		first, err := cms.SharedIAMTokenCredentials(key, server.URL, "audience")
+		Expect(err).ToNot(HaveOccurred())
+		second, err := cms.SharedIAMTokenCredentials(key, server.URL, "audience")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+	})
+})
@@ -0,0 +1,186 @@
+package cms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/draft/Ydb_Maintenance_V1"
+	"github.com/ydb-platform/ydb-go-genproto/draft/protos/Ydb_Maintenance"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/connection"
+)
+
+const (
+	MaintenanceTimeoutSeconds = 10
+	DefaultLockDuration       = 10 * time.Minute
+)
+
+type Maintenance struct {
+	StorageEndpoint string
+	Domain          string
+	TaskUID         string
+	Description     string
+	LockDuration    time.Duration
+}
+
+// RequestMaintenance creates the maintenance task locking given nodes, or
+// refreshes it if the task already exists, and reports whether CMS permitted
+// to take all the nodes down without breaking availability
+func (m *Maintenance) RequestMaintenance(
+	ctx context.Context,
+	nodeIDs []uint32,
+	opts ...ydb.Option,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	endpoint := fmt.Sprintf("%s/%s", m.StorageEndpoint, m.Domain)
+	conn, err := connection.Open(ctx, endpoint, ydb.MergeOptions(opts...))
+	if err != nil {
+		return false, fmt.Errorf("error connecting to YDB: %w", err)
+	}
+	defer func() {
+		connection.Close(ctx, conn)
+	}()
+
+	cmsCtx, cmsCtxCancel := context.WithTimeout(ctx, MaintenanceTimeoutSeconds*time.Second)
+	defer cmsCtxCancel()
+	client := Ydb_Maintenance_V1.NewMaintenanceServiceClient(ydb.GRPCConn(conn))
+	request := m.makeCreateMaintenanceTaskRequest(nodeIDs)
+
+	logger.Info("CMS CreateMaintenanceTask request", "endpoint", endpoint, "request", request)
+	response, err := client.CreateMaintenanceTask(cmsCtx, request)
+	if err != nil {
+		return false, err
+	}
+
+	if response.GetOperation().GetStatus() == Ydb.StatusIds_ALREADY_EXISTS {
+		refreshRequest := &Ydb_Maintenance.RefreshMaintenanceTaskRequest{
+			OperationParams: makeMaintenanceOperationParams(),
+			TaskUid:         m.TaskUID,
+		}
+		logger.Info("CMS RefreshMaintenanceTask request", "endpoint", endpoint, "request", refreshRequest)
+		response, err = client.RefreshMaintenanceTask(cmsCtx, refreshRequest)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return m.CheckMaintenanceTaskResponse(ctx, response)
+}
+
+// CheckMaintenanceTaskResponse reports whether all the actions of the
+// maintenance task are performed, i.e. the nodes are locked for maintenance
+func (m *Maintenance) CheckMaintenanceTaskResponse(
+	ctx context.Context,
+	response *Ydb_Maintenance.MaintenanceTaskResponse,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("CMS MaintenanceTask response", "response", response)
+
+	operation := response.GetOperation()
+	if operation == nil {
+		return false, ErrEmptyReplyFromStorage
+	}
+	if operation.GetStatus() != Ydb.StatusIds_SUCCESS {
+		return false, fmt.Errorf("YDB response error: %v %v", operation.GetStatus(), operation.GetIssues())
+	}
+
+	result := &Ydb_Maintenance.MaintenanceTaskResult{}
+	if err := operation.GetResult().UnmarshalTo(result); err != nil {
+		return false, err
+	}
+
+	if len(result.GetActionGroupStates()) == 0 {
+		return false, nil
+	}
+	for _, group := range result.GetActionGroupStates() {
+		for _, state := range group.GetActionStates() {
+			if state.GetStatus() != Ydb_Maintenance.ActionState_ACTION_STATUS_PERFORMED {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// DropMaintenance removes the maintenance task releasing its locks
+func (m *Maintenance) DropMaintenance(
+	ctx context.Context,
+	opts ...ydb.Option,
+) error {
+	logger := log.FromContext(ctx)
+
+	endpoint := fmt.Sprintf("%s/%s", m.StorageEndpoint, m.Domain)
+	conn, err := connection.Open(ctx, endpoint, ydb.MergeOptions(opts...))
+	if err != nil {
+		return fmt.Errorf("error connecting to YDB: %w", err)
+	}
+	defer func() {
+		connection.Close(ctx, conn)
+	}()
+
+	cmsCtx, cmsCtxCancel := context.WithTimeout(ctx, MaintenanceTimeoutSeconds*time.Second)
+	defer cmsCtxCancel()
+	client := Ydb_Maintenance_V1.NewMaintenanceServiceClient(ydb.GRPCConn(conn))
+	request := &Ydb_Maintenance.DropMaintenanceTaskRequest{
+		OperationParams: makeMaintenanceOperationParams(),
+		TaskUid:         m.TaskUID,
+	}
+
+	logger.Info("CMS DropMaintenanceTask request", "endpoint", endpoint, "request", request)
+	response, err := client.DropMaintenanceTask(cmsCtx, request)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("CMS DropMaintenanceTask response", "response", response)
+	status := response.GetOperation().GetStatus()
+	if status != Ydb.StatusIds_SUCCESS && status != Ydb.StatusIds_NOT_FOUND {
+		return fmt.Errorf("YDB response error: %v %v", status, response.GetOperation().GetIssues())
+	}
+	return nil
+}
+
+func (m *Maintenance) makeCreateMaintenanceTaskRequest(nodeIDs []uint32) *Ydb_Maintenance.CreateMaintenanceTaskRequest {
+	lockDuration := m.LockDuration
+	if lockDuration == 0 {
+		lockDuration = DefaultLockDuration
+	}
+
+	actions := make([]*Ydb_Maintenance.Action, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		actions = append(actions, &Ydb_Maintenance.Action{
+			Action: &Ydb_Maintenance.Action_LockAction{
+				LockAction: &Ydb_Maintenance.LockAction{
+					Scope: &Ydb_Maintenance.ActionScope{
+						Scope: &Ydb_Maintenance.ActionScope_NodeId{NodeId: nodeID},
+					},
+					Duration: durationpb.New(lockDuration),
+				},
+			},
+		})
+	}
+
+	return &Ydb_Maintenance.CreateMaintenanceTaskRequest{
+		OperationParams: makeMaintenanceOperationParams(),
+		TaskOptions: &Ydb_Maintenance.MaintenanceTaskOptions{
+			TaskUid:          m.TaskUID,
+			Description:      m.Description,
+			AvailabilityMode: Ydb_Maintenance.AvailabilityMode_AVAILABILITY_MODE_STRONG,
+		},
+		// Single group, so that CMS grants locks of all the nodes at once
+		ActionGroups: []*Ydb_Maintenance.ActionGroup{{Actions: actions}},
+	}
+}
+
+func makeMaintenanceOperationParams() *Ydb_Operations.OperationParams {
+	return &Ydb_Operations.OperationParams{
+		OperationTimeout: &durationpb.Duration{Seconds: MaintenanceTimeoutSeconds},
+	}
+}
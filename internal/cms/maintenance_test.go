@@ -0,0 +1,60 @@
+package cms_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ydb-platform/ydb-go-genproto/draft/protos/Ydb_Maintenance"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+)
+
+func newMaintenanceTaskResponse(statuses ...Ydb_Maintenance.ActionState_ActionStatus) *Ydb_Maintenance.MaintenanceTaskResponse {
+	var states []*Ydb_Maintenance.ActionState
+	for _, status := range statuses {
+		states = append(states, &Ydb_Maintenance.ActionState{Status: status})
+	}
+	result, err := anypb.New(&Ydb_Maintenance.MaintenanceTaskResult{
+		TaskUid:           "task",
+		ActionGroupStates: []*Ydb_Maintenance.ActionGroupStates{{ActionStates: states}},
+	})
+	Expect(err).ToNot(HaveOccurred())
+	return &Ydb_Maintenance.MaintenanceTaskResponse{
+		Operation: &Ydb_Operations.Operation{
+			Ready:  true,
+			Status: Ydb.StatusIds_SUCCESS,
+			Result: result,
+		},
+	}
+}
+
+var _ = Describe("Maintenance", func() {
+	maintenance := &cms.Maintenance{TaskUID: "task"}
+
+	It("is granted when all the actions are performed", func() {
+		granted, err := maintenance.CheckMaintenanceTaskResponse(context.Background(), newMaintenanceTaskResponse(
+			Ydb_Maintenance.ActionState_ACTION_STATUS_PERFORMED,
+			Ydb_Maintenance.ActionState_ACTION_STATUS_PERFORMED,
+		))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(granted).To(BeTrue())
+	})
+
+	It("is not granted while any action is pending", func() {
+		granted, err := maintenance.CheckMaintenanceTaskResponse(context.Background(), newMaintenanceTaskResponse(
+			Ydb_Maintenance.ActionState_ACTION_STATUS_PERFORMED,
+			Ydb_Maintenance.ActionState_ACTION_STATUS_PENDING,
+		))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(granted).To(BeFalse())
+	})
+
+	It("returns error on empty reply", func() {
+		_, err := maintenance.CheckMaintenanceTaskResponse(context.Background(), &Ydb_Maintenance.MaintenanceTaskResponse{})
+		Expect(err).To(MatchError(cms.ErrEmptyReplyFromStorage))
+	})
+})
@@ -2,6 +2,7 @@ package cms
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,8 +17,41 @@ import (
 
 const (
 	GetOperationTimeoutSeconds = 10
+
+	DefaultOperationTimeout      = 10 * time.Minute
+	DefaultOperationPollInterval = 5 * time.Second
 )
 
+// ErrOperationTimeout is returned when operation is not ready after
+// PollOptions.Timeout, operation keeps running and is checked again later
+var ErrOperationTimeout = errors.New("operation is not ready in time")
+
+// PollOptions bound waiting for an asynchronous CMS operation, operation
+// is checked once per reconcile requeued after Interval
+type PollOptions struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// GetTimeout returns Timeout or DefaultOperationTimeout if it is not set
+func (p PollOptions) GetTimeout() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultOperationTimeout
+	}
+	return p.Timeout
+}
+
+// GetInterval returns Interval or DefaultOperationPollInterval if it is not set
+func (p PollOptions) GetInterval() time.Duration {
+	if p.Interval <= 0 {
+		return DefaultOperationPollInterval
+	}
+	return p.Interval
+}
+
+// GetOperationFunc requests current state of operation by its id
+type GetOperationFunc func(ctx context.Context, id string) (*Ydb_Operations.Operation, error)
+
 type Operation struct {
 	StorageEndpoint string
 	Domain          string
@@ -48,6 +82,23 @@ func (op *Operation) GetOperation(
 	return client.GetOperation(cmsCtx, request)
 }
 
+// OperationGetter returns GetOperationFunc requesting operations of the
+// storage domain
+func OperationGetter(storageEndpoint, domain string, opts ...ydb.Option) GetOperationFunc {
+	return func(ctx context.Context, id string) (*Ydb_Operations.Operation, error) {
+		op := &Operation{
+			StorageEndpoint: storageEndpoint,
+			Domain:          domain,
+			ID:              id,
+		}
+		response, err := op.GetOperation(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return response.GetOperation(), nil
+	}
+}
+
 func (op *Operation) CheckGetOperationResponse(ctx context.Context, response *Ydb_Operations.GetOperationResponse) (bool, string, error) {
 	logger := log.FromContext(ctx)
 
@@ -55,18 +106,65 @@ func (op *Operation) CheckGetOperationResponse(ctx context.Context, response *Yd
 	return CheckOperationStatus(response.GetOperation())
 }
 
+// OperationState is the state of an asynchronous CMS operation
+type OperationState string
+
+const (
+	OperationStateRunning   OperationState = "Running"
+	OperationStateSucceeded OperationState = "Succeeded"
+	OperationStateFailed    OperationState = "Failed"
+)
+
+// GetOperationState returns state of the operation as reported by CMS,
+// operation which already exists is reported as succeeded
+func GetOperationState(operation *Ydb_Operations.Operation) OperationState {
+	switch {
+	case !operation.GetReady():
+		return OperationStateRunning
+	case operation.GetStatus() == Ydb.StatusIds_ALREADY_EXISTS || operation.GetStatus() == Ydb.StatusIds_SUCCESS:
+		return OperationStateSucceeded
+	default:
+		return OperationStateFailed
+	}
+}
+
 func CheckOperationStatus(operation *Ydb_Operations.Operation) (bool, string, error) {
 	if operation == nil {
 		return false, "", ErrEmptyReplyFromStorage
 	}
 
-	if !operation.GetReady() {
+	switch GetOperationState(operation) {
+	case OperationStateRunning:
 		return false, operation.Id, nil
-	}
-
-	if operation.Status == Ydb.StatusIds_ALREADY_EXISTS || operation.Status == Ydb.StatusIds_SUCCESS {
+	case OperationStateSucceeded:
 		return true, operation.Id, nil
 	}
 
 	return true, operation.Id, fmt.Errorf("YDB response error: %v %v", operation.Status, operation.Issues)
 }
+
+// PollOperation requests current state of operation with getOperation
+// once, without waiting for it. Operation which is still not ready after
+// poll.Timeout since it was submitted is returned together with
+// ErrOperationTimeout, so that the caller requeues with a retryable error.
+func PollOperation(
+	ctx context.Context,
+	id string,
+	submitted time.Time,
+	getOperation GetOperationFunc,
+	poll PollOptions,
+) (*Ydb_Operations.Operation, error) {
+	operation, err := getOperation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if operation == nil {
+		return nil, ErrEmptyReplyFromStorage
+	}
+
+	if !operation.GetReady() && time.Since(submitted) >= poll.GetTimeout() {
+		return operation, fmt.Errorf("%w: operationID %s", ErrOperationTimeout, id)
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,81 @@
+package cms_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+)
+
+// slowOperations is a fake CMS which reports operation ready after the
+// given number of polls
+func slowOperations(polls int) (cms.GetOperationFunc, *int) {
+	calls := 0
+	return func(ctx context.Context, id string) (*Ydb_Operations.Operation, error) {
+		calls++
+		return &Ydb_Operations.Operation{
+			Id:     id,
+			Ready:  calls >= polls,
+			Status: Ydb.StatusIds_SUCCESS,
+		}, nil
+	}, &calls
+}
+
+var _ = Describe("Operation", func() {
+	poll := cms.PollOptions{Timeout: time.Minute, Interval: time.Second}
+
+	It("polls operation once without waiting for it", func() {
+		getOperation, calls := slowOperations(3)
+		operation, err := cms.PollOperation(context.Background(), "operation", time.Now(), getOperation, poll)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(operation.GetId()).To(Equal("operation"))
+		Expect(operation.GetReady()).To(BeFalse())
+		Expect(*calls).To(Equal(1))
+	})
+
+	It("returns ready operation", func() {
+		getOperation, _ := slowOperations(1)
+		operation, err := cms.PollOperation(context.Background(), "operation", time.Now().Add(-time.Hour), getOperation, poll)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(operation.GetReady()).To(BeTrue())
+	})
+
+	It("returns retryable error when operation is not ready in time", func() {
+		getOperation, _ := slowOperations(1000)
+		operation, err := cms.PollOperation(context.Background(), "operation", time.Now().Add(-2*time.Minute), getOperation, poll)
+		Expect(err).To(MatchError(cms.ErrOperationTimeout))
+		Expect(operation.GetId()).To(Equal("operation"))
+		Expect(operation.GetReady()).To(BeFalse())
+	})
+
+	It("returns error of polling", func() {
+		failure := errors.New("unavailable")
+		_, err := cms.PollOperation(context.Background(), "operation", time.Now(),
+			func(context.Context, string) (*Ydb_Operations.Operation, error) {
+				return nil, failure
+			}, poll)
+		Expect(err).To(MatchError(failure))
+	})
+
+	It("uses defaults of not set poll options", func() {
+		Expect(cms.PollOptions{}.GetTimeout()).To(Equal(cms.DefaultOperationTimeout))
+		Expect(cms.PollOptions{}.GetInterval()).To(Equal(cms.DefaultOperationPollInterval))
+		Expect(poll.GetInterval()).To(Equal(time.Second))
+	})
+})
+
+var _ = DescribeTable("Operation state",
+	func(operation *Ydb_Operations.Operation, expected cms.OperationState) {
+		Expect(cms.GetOperationState(operation)).To(Equal(expected))
+	},
+	Entry("not ready", &Ydb_Operations.Operation{Id: "operation"}, cms.OperationStateRunning),
+	Entry("succeeded", &Ydb_Operations.Operation{Ready: true, Status: Ydb.StatusIds_SUCCESS}, cms.OperationStateSucceeded),
+	Entry("already exists", &Ydb_Operations.Operation{Ready: true, Status: Ydb.StatusIds_ALREADY_EXISTS}, cms.OperationStateSucceeded),
+	Entry("failed", &Ydb_Operations.Operation{Ready: true, Status: Ydb.StatusIds_BAD_REQUEST}, cms.OperationStateFailed),
+)
@@ -17,9 +17,15 @@ import (
 
 const (
 	CreateDatabaseTimeoutSeconds = 10
+	AlterDatabaseTimeoutSeconds  = 10
+	ListDatabasesTimeoutSeconds  = 10
 )
 
-var ErrEmptyReplyFromStorage = errors.New("empty reply from storage")
+var (
+	ErrEmptyReplyFromStorage    = errors.New("empty reply from storage")
+	ErrParentCheckNotFinished   = errors.New("listing databases to check parent is not finished")
+	ErrListDatabasesNotFinished = errors.New("listing databases is not finished")
+)
 
 type Tenant struct {
 	StorageEndpoint    string
@@ -28,6 +34,10 @@ type Tenant struct {
 	StorageUnits       []ydbv1alpha1.StorageUnit
 	Shared             bool
 	SharedDatabasePath string
+	ParentPath         string
+
+	ComputationalUnits    []ydbv1alpha1.ComputationalUnit
+	SchemaOperationQuotas []ydbv1alpha1.SchemaOperationQuota
 }
 
 func (t *Tenant) CreateDatabase(
@@ -60,6 +70,110 @@ func (t *Tenant) CheckCreateDatabaseResponse(ctx context.Context, response *Ydb_
 	return CheckOperationStatus(response.GetOperation())
 }
 
+// AlterSchemaOperationQuotas replaces quotas of schema operations of the
+// tenant with SchemaOperationQuotas, empty quotas remove the limits
+func (t *Tenant) AlterSchemaOperationQuotas(
+	ctx context.Context,
+	opts ...ydb.Option,
+) (*Ydb_Cms.AlterDatabaseResponse, error) {
+	logger := log.FromContext(ctx)
+
+	endpoint := fmt.Sprintf("%s/%s", t.StorageEndpoint, t.Domain)
+	conn, err := connection.Open(ctx, endpoint, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to YDB: %w", err)
+	}
+	defer func() {
+		connection.Close(ctx, conn)
+	}()
+
+	cmsCtx, cmsCtxCancel := context.WithTimeout(ctx, AlterDatabaseTimeoutSeconds*time.Second)
+	defer cmsCtxCancel()
+	client := Ydb_Cms_V1.NewCmsServiceClient(ydb.GRPCConn(conn))
+	request := &Ydb_Cms.AlterDatabaseRequest{
+		Path:                  t.Path,
+		SchemaOperationQuotas: t.makeSchemaOperationQuotas(),
+	}
+	if request.SchemaOperationQuotas == nil {
+		request.SchemaOperationQuotas = &Ydb_Cms.SchemaOperationQuotas{}
+	}
+	logger.Info("CMS AlterDatabase request", "endpoint", endpoint, "request", request)
+	return client.AlterDatabase(cmsCtx, request)
+}
+
+func (t *Tenant) CheckAlterDatabaseResponse(ctx context.Context, response *Ydb_Cms.AlterDatabaseResponse) (bool, string, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("CMS AlterDatabase response", "response", response)
+	return CheckOperationStatus(response.GetOperation())
+}
+
+func (t *Tenant) ListDatabases(
+	ctx context.Context,
+	opts ...ydb.Option,
+) (*Ydb_Cms.ListDatabasesResponse, error) {
+	logger := log.FromContext(ctx)
+
+	endpoint := fmt.Sprintf("%s/%s", t.StorageEndpoint, t.Domain)
+	conn, err := connection.Open(ctx, endpoint, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to YDB: %w", err)
+	}
+	defer func() {
+		connection.Close(ctx, conn)
+	}()
+
+	cmsCtx, cmsCtxCancel := context.WithTimeout(ctx, ListDatabasesTimeoutSeconds*time.Second)
+	defer cmsCtxCancel()
+	client := Ydb_Cms_V1.NewCmsServiceClient(ydb.GRPCConn(conn))
+	request := &Ydb_Cms.ListDatabasesRequest{}
+	logger.Info("CMS ListDatabases request", "endpoint", endpoint, "request", request)
+	return client.ListDatabases(cmsCtx, request)
+}
+
+// CheckParentExists reports whether ParentPath of the tenant is known to CMS.
+// The domain root always exists and is not checked.
+func (t *Tenant) CheckParentExists(ctx context.Context, response *Ydb_Cms.ListDatabasesResponse) (bool, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("CMS ListDatabases response", "response", response)
+
+	if t.ParentPath == "" || t.ParentPath == fmt.Sprintf("/%s", t.Domain) {
+		return true, nil
+	}
+
+	paths, err := GetDatabasePaths(response)
+	if errors.Is(err, ErrListDatabasesNotFinished) {
+		return false, ErrParentCheckNotFinished
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, path := range paths {
+		if path == t.ParentPath {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetDatabasePaths returns paths of the databases listed in CMS response
+func GetDatabasePaths(response *Ydb_Cms.ListDatabasesResponse) ([]string, error) {
+	finished, _, err := CheckOperationStatus(response.GetOperation())
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, ErrListDatabasesNotFinished
+	}
+
+	listResult := &Ydb_Cms.ListDatabasesResult{}
+	if err := response.GetOperation().GetResult().UnmarshalTo(listResult); err != nil {
+		return nil, err
+	}
+	return listResult.GetPaths(), nil
+}
+
 func (t *Tenant) makeCreateDatabaseRequest() *Ydb_Cms.CreateDatabaseRequest {
 	request := &Ydb_Cms.CreateDatabaseRequest{Path: t.Path}
 	if t.SharedDatabasePath != "" {
@@ -83,12 +197,35 @@ func (t *Tenant) makeCreateDatabaseRequest() *Ydb_Cms.CreateDatabaseRequest {
 				},
 			}
 		} else {
+			computationalUnitsPb := []*Ydb_Cms.ComputationalUnits{}
+			for _, i := range t.ComputationalUnits {
+				computationalUnitsPb = append(
+					computationalUnitsPb,
+					&Ydb_Cms.ComputationalUnits{UnitKind: i.UnitKind, AvailabilityZone: i.AvailabilityZone, Count: i.Count},
+				)
+			}
 			request.ResourcesKind = &Ydb_Cms.CreateDatabaseRequest_Resources{
 				Resources: &Ydb_Cms.Resources{
-					StorageUnits: storageUnitsPb,
+					StorageUnits:       storageUnitsPb,
+					ComputationalUnits: computationalUnitsPb,
 				},
 			}
+			request.SchemaOperationQuotas = t.makeSchemaOperationQuotas()
 		}
 	}
 	return request
 }
+
+func (t *Tenant) makeSchemaOperationQuotas() *Ydb_Cms.SchemaOperationQuotas {
+	if len(t.SchemaOperationQuotas) == 0 {
+		return nil
+	}
+	quotas := &Ydb_Cms.SchemaOperationQuotas{}
+	for _, i := range t.SchemaOperationQuotas {
+		quotas.LeakyBucketQuotas = append(
+			quotas.LeakyBucketQuotas,
+			&Ydb_Cms.SchemaOperationQuotas_LeakyBucket{BucketSize: float64(i.BucketSize), BucketSeconds: i.BucketSeconds},
+		)
+	}
+	return quotas
+}
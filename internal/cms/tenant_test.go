@@ -0,0 +1,70 @@
+package cms_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Cms"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+)
+
+func TestCMS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CMS suite")
+}
+
+func newListDatabasesResponse(ready bool, paths ...string) *Ydb_Cms.ListDatabasesResponse {
+	result, err := anypb.New(&Ydb_Cms.ListDatabasesResult{Paths: paths})
+	Expect(err).ToNot(HaveOccurred())
+	return &Ydb_Cms.ListDatabasesResponse{
+		Operation: &Ydb_Operations.Operation{
+			Ready:  ready,
+			Status: Ydb.StatusIds_SUCCESS,
+			Result: result,
+		},
+	}
+}
+
+var _ = Describe("Tenant", func() {
+	var tenant *cms.Tenant
+
+	BeforeEach(func() {
+		tenant = &cms.Tenant{
+			Domain:     "Root",
+			Path:       "/Root/parent/database",
+			ParentPath: "/Root/parent",
+		}
+	})
+
+	It("does not check domain root as parent", func() {
+		tenant.ParentPath = "/Root"
+		Expect(tenant.CheckParentExists(context.Background(), nil)).To(BeTrue())
+	})
+
+	It("finds existing parent database", func() {
+		response := newListDatabasesResponse(true, "/Root/other", "/Root/parent")
+		Expect(tenant.CheckParentExists(context.Background(), response)).To(BeTrue())
+	})
+
+	It("reports missing parent database", func() {
+		response := newListDatabasesResponse(true, "/Root/other", "/Root/parent/database")
+		Expect(tenant.CheckParentExists(context.Background(), response)).To(BeFalse())
+	})
+
+	It("returns distinct error when listing is not finished", func() {
+		response := newListDatabasesResponse(false)
+		_, err := tenant.CheckParentExists(context.Background(), response)
+		Expect(err).To(MatchError(cms.ErrParentCheckNotFinished))
+	})
+
+	It("returns error on empty reply", func() {
+		_, err := tenant.CheckParentExists(context.Background(), &Ydb_Cms.ListDatabasesResponse{})
+		Expect(err).To(MatchError(cms.ErrEmptyReplyFromStorage))
+	})
+})
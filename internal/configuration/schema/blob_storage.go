@@ -0,0 +1,29 @@
+package schema
+
+type BlobStorageConfig struct {
+	ServiceSet *ServiceSet `yaml:"service_set,omitempty"`
+}
+
+type ServiceSet struct {
+	Groups []StaticGroup `yaml:"groups,omitempty"`
+}
+
+type StaticGroup struct {
+	GroupID        int    `yaml:"group_id"`
+	ErasureSpecies string `yaml:"erasure_species,omitempty"`
+	Rings          []Ring `yaml:"rings,omitempty"`
+}
+
+type Ring struct {
+	FailDomains []FailDomain `yaml:"fail_domains"`
+}
+
+type FailDomain struct {
+	VDiskLocations []VDiskLocation `yaml:"vdisk_locations"`
+}
+
+type VDiskLocation struct {
+	NodeID        int    `yaml:"node_id"`
+	PDiskCategory string `yaml:"pdisk_category"`
+	Path          string `yaml:"path"`
+}
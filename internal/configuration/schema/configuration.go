@@ -7,9 +7,11 @@ type DynConfig struct {
 	SelectorConfig []SelectorConfig       `yaml:"selector_config"`
 }
 type Configuration struct {
-	DomainsConfig *DomainsConfig `yaml:"domains_config"`
-	Hosts         []Host         `yaml:"hosts,omitempty"`
-	KeyConfig     *KeyConfig     `yaml:"key_config,omitempty"`
+	BlobStorageConfig *BlobStorageConfig `yaml:"blob_storage_config,omitempty"`
+	DomainsConfig     *DomainsConfig     `yaml:"domains_config"`
+	HostConfigs       []HostConfig       `yaml:"host_configs,omitempty"`
+	Hosts             []Host             `yaml:"hosts,omitempty"`
+	KeyConfig         *KeyConfig         `yaml:"key_config,omitempty"`
 }
 
 type Metadata struct {
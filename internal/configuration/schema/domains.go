@@ -1,9 +1,19 @@
 package schema
 
 type DomainsConfig struct {
+	Domain         []Domain        `yaml:"domain,omitempty"`
 	SecurityConfig *SecurityConfig `yaml:"security_config,omitempty"`
 }
 
+type Domain struct {
+	Name             string            `yaml:"name"`
+	StoragePoolTypes []StoragePoolType `yaml:"storage_pool_types,omitempty"`
+}
+
+type StoragePoolType struct {
+	Kind string `yaml:"kind"`
+}
+
 type SecurityConfig struct {
 	EnforceUserTokenRequirement *bool `yaml:"enforce_user_token_requirement,omitempty"`
 }
@@ -14,3 +14,13 @@ type WalleLocation struct {
 	DataCenter string `yaml:"data_center"`
 	Rack       string `yaml:"rack"`
 }
+
+type HostConfig struct {
+	HostConfigID int     `yaml:"host_config_id"`
+	Drive        []Drive `yaml:"drive"`
+}
+
+type Drive struct {
+	Path string `yaml:"path"`
+	Type string `yaml:"type"`
+}
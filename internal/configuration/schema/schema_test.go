@@ -6,6 +6,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration/schema"
@@ -203,6 +205,250 @@ allowed_labels:
     type: string
 `
 
+//nolint:all
+var unifiedConfigExample = `
+---
+host_configs:
+  - drive:
+      - path: SectorMap:1:1
+        type: SSD
+    host_config_id: 1
+grpc_config:
+  port: 2135
+`
+
+//nolint:all
+var goldenConfigurationV1 = `
+grpc_config:
+  port: 2135
+hosts:
+- host: storage-0
+  host_config_id: 1
+  node_id: 1
+  port: 19001
+  walle_location: {body: 12340, data_center: az-0, rack: "0"}
+- host: storage-1
+  host_config_id: 1
+  node_id: 2
+  port: 19001
+  walle_location: {body: 12341, data_center: az-1, rack: "1"}
+- host: storage-2
+  host_config_id: 1
+  node_id: 3
+  port: 19001
+  walle_location: {body: 12342, data_center: az-2, rack: "2"}
+`
+
+//nolint:all
+var goldenConfigurationV2 = `
+metadata:
+  kind: MainConfig
+  cluster: ""
+  version: 0
+config:
+  erasure: mirror-3-dc
+  grpc_config:
+    port: 2135
+  host_configs:
+  - drive:
+    - path: SectorMap:1:1
+      type: SSD
+    host_config_id: 1
+  hosts:
+  - host: storage-0
+    host_config_id: 1
+    node_id: 1
+    port: 19001
+    walle_location: {body: 12340, data_center: az-0, rack: "0"}
+  - host: storage-1
+    host_config_id: 1
+    node_id: 2
+    port: 19001
+    walle_location: {body: 12341, data_center: az-1, rack: "1"}
+  - host: storage-2
+    host_config_id: 1
+    node_id: 3
+    port: 19001
+    walle_location: {body: 12342, data_center: az-2, rack: "2"}
+  self_management_config:
+    enabled: true
+  yaml_config_enabled: true
+allowed_labels: {}
+selector_config: []
+`
+
+//nolint:all
+var storagePoolsConfigExample = `
+---
+domains_config:
+  domain:
+  - name: Root
+    storage_pool_types:
+    - kind: ssd
+      pool_config:
+        box_id: 1
+        erasure_species: block-4-2
+        kind: ssd
+        pdisk_filter:
+        - property:
+          - type: SSD
+        vdisk_kind: Default
+`
+
+type storagePoolsConfig struct {
+	DomainsConfig struct {
+		Domain []struct {
+			Name             string `yaml:"name"`
+			StoragePoolTypes []struct {
+				Kind       string `yaml:"kind"`
+				PoolConfig struct {
+					ErasureSpecies string `yaml:"erasure_species"`
+					PDiskFilter    []struct {
+						Property []map[string]string `yaml:"property"`
+					} `yaml:"pdisk_filter"`
+				} `yaml:"pool_config"`
+			} `yaml:"storage_pool_types"`
+		} `yaml:"domain"`
+	} `yaml:"domains_config"`
+	HostConfigs []schema.HostConfig `yaml:"host_configs"`
+	Hosts       []schema.Host       `yaml:"hosts"`
+}
+
+func newStoragePoolsStorage() *v1alpha1.Storage {
+	blockMode := corev1.PersistentVolumeBlock
+
+	storage := &v1alpha1.Storage{}
+	storage.Name = "storage"
+	storage.Spec.Nodes = 8
+	storage.Spec.Domain = "Root"
+	storage.Spec.Erasure = v1alpha1.ErasureBlock42
+	storage.Spec.Configuration = storagePoolsConfigExample
+	storage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{{VolumeMode: &blockMode}}
+	storage.Spec.StoragePools = []v1alpha1.StoragePool{{
+		Kind:                "rot",
+		VolumeClaimTemplate: corev1.PersistentVolumeClaimSpec{VolumeMode: &blockMode},
+	}}
+	return storage
+}
+
+//nolint:all
+var goldenStaticGroupNone = `
+service_set:
+  groups:
+  - group_id: 0
+    erasure_species: none
+    rings:
+    - fail_domains:
+      - vdisk_locations:
+        - {node_id: 1, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+`
+
+//nolint:all
+var goldenStaticGroupBlock42 = `
+service_set:
+  groups:
+  - group_id: 0
+    erasure_species: block-4-2
+    rings:
+    - fail_domains:
+      - vdisk_locations:
+        - {node_id: 1, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 2, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 3, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 4, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 5, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 6, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 7, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 8, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+`
+
+//nolint:all
+var goldenStaticGroupMirror3of4 = `
+service_set:
+  groups:
+  - group_id: 0
+    erasure_species: mirror-3of4
+    rings:
+    - fail_domains:
+      - vdisk_locations:
+        - {node_id: 1, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 2, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 3, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 4, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 5, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 6, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 7, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 8, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+`
+
+//nolint:all
+var goldenStaticGroupMirror3DC = `
+service_set:
+  groups:
+  - group_id: 0
+    erasure_species: mirror-3-dc
+    rings:
+    - fail_domains:
+      - vdisk_locations:
+        - {node_id: 1, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 1, pdisk_category: SSD, path: /dev/kikimr_ssd_01}
+      - vdisk_locations:
+        - {node_id: 1, pdisk_category: SSD, path: /dev/kikimr_ssd_02}
+    - fail_domains:
+      - vdisk_locations:
+        - {node_id: 2, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 2, pdisk_category: SSD, path: /dev/kikimr_ssd_01}
+      - vdisk_locations:
+        - {node_id: 2, pdisk_category: SSD, path: /dev/kikimr_ssd_02}
+    - fail_domains:
+      - vdisk_locations:
+        - {node_id: 3, pdisk_category: SSD, path: /dev/kikimr_ssd_00}
+      - vdisk_locations:
+        - {node_id: 3, pdisk_category: SSD, path: /dev/kikimr_ssd_01}
+      - vdisk_locations:
+        - {node_id: 3, pdisk_category: SSD, path: /dev/kikimr_ssd_02}
+`
+
+func newTopologyStorage(erasure v1alpha1.ErasureType, nodes int32) *v1alpha1.Storage {
+	blockMode := corev1.PersistentVolumeBlock
+
+	storage := &v1alpha1.Storage{}
+	storage.Name = "storage"
+	storage.Spec.Nodes = nodes
+	storage.Spec.Erasure = erasure
+	storage.Spec.Configuration = "grpc_config:\n  port: 2135\n"
+	storage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{{VolumeMode: &blockMode}}
+	storage.Spec.Topology = &v1alpha1.StorageTopology{}
+	return storage
+}
+
+func buildStaticGroup(storage *v1alpha1.Storage) string {
+	rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	config := map[string]interface{}{}
+	Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+	blobStorageConfig, err := yaml.Marshal(config["blob_storage_config"])
+	Expect(err).ShouldNot(HaveOccurred())
+	return string(blobStorageConfig)
+}
+
 func TestSchema(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Shema suite")
@@ -261,4 +507,354 @@ var _ = Describe("Testing schema", func() {
 		_, err := v1alpha1.ParseConfiguration(dynconfigExample)
 		Expect(err).ShouldNot(HaveOccurred())
 	})
+
+	It("Build unified configuration from plain config body", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+		storage.Spec.Nodes = 8
+		storage.Spec.Erasure = v1alpha1.ErasureBlock42
+		storage.Spec.ConfigurationVersion = v1alpha1.ConfigurationV2
+		storage.Spec.Configuration = unifiedConfigExample
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		success, dynconfig, err := v1alpha1.ParseDynConfig(string(rawConfig))
+		Expect(success).Should(BeTrue())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v1alpha1.IsUnifiedConfig(dynconfig)).Should(BeTrue())
+		Expect(dynconfig.Metadata.Kind).Should(Equal("MainConfig"))
+		Expect(dynconfig.Config["erasure"]).Should(Equal("block-4-2"))
+		Expect(dynconfig.Config["yaml_config_enabled"]).Should(BeTrue())
+		Expect(dynconfig.Config["self_management_config"]).Should(BeEquivalentTo(map[string]interface{}{
+			"enabled": true,
+		}))
+		Expect(dynconfig.Config["hosts"]).Should(HaveLen(8))
+		Expect(dynconfig.Config).ShouldNot(HaveKey("static_erasure"))
+		Expect(dynconfig.Config).ShouldNot(HaveKey("blob_storage_config"))
+	})
+
+	It("Build configuration matching golden output", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+		storage.Spec.Nodes = 3
+		storage.Spec.Erasure = v1alpha1.ErasureMirror3DC
+		storage.Spec.Configuration = "grpc_config:\n  port: 2135\n"
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(rawConfig)).Should(MatchYAML(goldenConfigurationV1))
+	})
+
+	It("Build configuration with interconnect encryption", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+		storage.Spec.Nodes = 3
+		storage.Spec.Erasure = v1alpha1.ErasureMirror3DC
+		storage.Spec.Configuration = "interconnect_config:\n  encryption_mode: OPTIONAL\n"
+		storage.Spec.Service = &v1alpha1.StorageServices{
+			Interconnect: v1alpha1.InterconnectService{
+				TLSConfiguration: &v1alpha1.TLSConfiguration{Enabled: true},
+			},
+		}
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(rawConfig)).Should(ContainSubstring("encryption_mode: OPTIONAL"))
+		Expect(string(rawConfig)).Should(ContainSubstring("path_to_certificate_file: /tls/interconnect/tls.crt"))
+		Expect(string(rawConfig)).Should(ContainSubstring("path_to_private_key_file: /tls/interconnect/tls.key"))
+		Expect(string(rawConfig)).Should(ContainSubstring("path_to_ca_file: /tls/interconnect/ca.crt"))
+	})
+
+	It("Build unified configuration matching golden output", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+		storage.Spec.Nodes = 3
+		storage.Spec.Erasure = v1alpha1.ErasureMirror3DC
+		storage.Spec.ConfigurationVersion = v1alpha1.ConfigurationV2
+		storage.Spec.Configuration = unifiedConfigExample
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(rawConfig)).Should(MatchYAML(goldenConfigurationV2))
+	})
+
+	It("Keep static part of unified configuration for CMS", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+		storage.Spec.Nodes = 8
+		storage.Spec.Erasure = v1alpha1.ErasureBlock42
+		storage.Spec.ConfigurationVersion = v1alpha1.ConfigurationV2
+		storage.Spec.Configuration = unifiedConfigExample
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, dynconfig, err := v1alpha1.ParseDynConfig(string(rawConfig))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		cmsConfig, err := v1alpha1.GetConfigForCMS(dynconfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(cmsConfig)).Should(ContainSubstring("host_configs"))
+		Expect(string(cmsConfig)).Should(ContainSubstring("hosts"))
+	})
+
+	It("Render configuration template with Storage as context", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+		storage.Spec.Nodes = 3
+		storage.Spec.Domain = "Root"
+
+		rawConfig, err := v1alpha1.RenderConfigurationTemplate(storage,
+			"domains_config:\n  domain:\n  - name: {{ .Spec.Domain }}\nactor_system_config:\n  nodes: {{ .Spec.Nodes }}\n")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		configuration, err := v1alpha1.ParseConfiguration(string(rawConfig))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(configuration.DomainsConfig.Domain[0].Name).Should(Equal("Root"))
+	})
+
+	It("Reject configuration template rendered into invalid YAML", func() {
+		storage := &v1alpha1.Storage{}
+		storage.Name = "storage"
+
+		_, err := v1alpha1.RenderConfigurationTemplate(storage, "hosts: [{{ .Name }}\n")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Generate host config with DataStore and storage pool drives", func() {
+		storage := newStoragePoolsStorage()
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := storagePoolsConfig{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+		Expect(config.HostConfigs).Should(Equal([]schema.HostConfig{{
+			HostConfigID: 1,
+			Drive: []schema.Drive{
+				{Path: "/dev/kikimr_ssd_00", Type: "SSD"},
+				{Path: "/dev/kikimr_ssd_01", Type: "ROT"},
+			},
+		}}))
+		Expect(config.Hosts).Should(HaveLen(8))
+		for _, host := range config.Hosts {
+			Expect(host.HostConfigID).Should(Equal(1))
+		}
+	})
+
+	It("Generate host config with drive for every pdisk of the node", func() {
+		storage := newStoragePoolsStorage()
+		storage.Spec.StoragePools = nil
+		storage.Spec.PDisksPerNode = 3
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := storagePoolsConfig{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+		Expect(config.HostConfigs).Should(Equal([]schema.HostConfig{{
+			HostConfigID: 1,
+			Drive: []schema.Drive{
+				{Path: "/dev/kikimr_ssd_00", Type: "SSD"},
+				{Path: "/dev/kikimr_ssd_01", Type: "SSD"},
+				{Path: "/dev/kikimr_ssd_02", Type: "SSD"},
+			},
+		}}))
+	})
+
+	It("Generate definitions of undeclared storage pools", func() {
+		storage := newStoragePoolsStorage()
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := storagePoolsConfig{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+		poolTypes := config.DomainsConfig.Domain[0].StoragePoolTypes
+		Expect(poolTypes).Should(HaveLen(2))
+		Expect(poolTypes[0].Kind).Should(Equal("ssd"))
+		Expect(poolTypes[1].Kind).Should(Equal("rot"))
+		Expect(poolTypes[1].PoolConfig.ErasureSpecies).Should(Equal("block-4-2"))
+		Expect(poolTypes[1].PoolConfig.PDiskFilter[0].Property).Should(Equal([]map[string]string{{"type": "ROT"}}))
+	})
+
+	It("Generate host config for every NodeSet with own storage pools", func() {
+		blockMode := corev1.PersistentVolumeBlock
+
+		storage := newStoragePoolsStorage()
+		storage.Spec.NodeSets = []v1alpha1.StorageNodeSetSpecInline{
+			{
+				Name:            "ssd",
+				StorageNodeSpec: v1alpha1.StorageNodeSpec{Nodes: 4},
+			},
+			{
+				Name: "nvme",
+				StorageNodeSpec: v1alpha1.StorageNodeSpec{
+					Nodes: 4,
+					StoragePools: []v1alpha1.StoragePool{{
+						Kind:                "nvme",
+						VolumeClaimTemplate: corev1.PersistentVolumeClaimSpec{VolumeMode: &blockMode},
+					}},
+				},
+			},
+		}
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := storagePoolsConfig{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+		Expect(config.HostConfigs).Should(HaveLen(2))
+		Expect(config.HostConfigs[0].Drive[1]).Should(Equal(schema.Drive{Path: "/dev/kikimr_ssd_01", Type: "ROT"}))
+		Expect(config.HostConfigs[1].Drive[1]).Should(Equal(schema.Drive{Path: "/dev/kikimr_ssd_01", Type: "NVME"}))
+		Expect(config.Hosts[0].Host).Should(Equal("storage-ssd-0"))
+		Expect(config.Hosts[0].HostConfigID).Should(Equal(1))
+		Expect(config.Hosts[4].Host).Should(Equal("storage-nvme-0"))
+		Expect(config.Hosts[4].HostConfigID).Should(Equal(2))
+
+		poolTypes := config.DomainsConfig.Domain[0].StoragePoolTypes
+		Expect(poolTypes).Should(HaveLen(3))
+		Expect(poolTypes[2].Kind).Should(Equal("nvme"))
+	})
+
+	It("Keep host configs set by user", func() {
+		storage := newStoragePoolsStorage()
+		storage.Spec.Configuration = storagePoolsConfigExample + `host_configs:
+- host_config_id: 1
+  drive:
+  - path: /dev/kikimr_ssd_00
+    type: SSD
+`
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := storagePoolsConfig{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+		Expect(config.HostConfigs).Should(HaveLen(1))
+		Expect(config.HostConfigs[0].Drive).Should(HaveLen(1))
+	})
+
+	It("Reject unsupported erasure in generated storage pools", func() {
+		storage := newStoragePoolsStorage()
+		storage.Spec.Erasure = "mirror-2"
+
+		_, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).Should(MatchError(ContainSubstring("unsupported erasure type mirror-2")))
+	})
+
+	It("Generate host configs and storage pools of dynconfig", func() {
+		storage := newStoragePoolsStorage()
+		storage.Spec.Configuration = `---
+metadata:
+  kind: MainConfig
+  version: 0
+  cluster: "unknown"
+config:
+  yaml_config_enabled: true
+  domains_config:
+    domain:
+    - name: Root
+allowed_labels: {}
+selector_config: []
+`
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		dynConfig := struct {
+			Config storagePoolsConfig `yaml:"config"`
+		}{}
+		Expect(yaml.Unmarshal(rawConfig, &dynConfig)).Should(Succeed())
+		config := dynConfig.Config
+		Expect(config.HostConfigs).Should(HaveLen(1))
+		Expect(config.HostConfigs[0].Drive[1]).Should(Equal(schema.Drive{Path: "/dev/kikimr_ssd_01", Type: "ROT"}))
+		Expect(config.Hosts).Should(HaveLen(8))
+		Expect(config.Hosts[0].HostConfigID).Should(Equal(1))
+		poolTypes := config.DomainsConfig.Domain[0].StoragePoolTypes
+		Expect(poolTypes).Should(HaveLen(1))
+		Expect(poolTypes[0].Kind).Should(Equal("rot"))
+	})
+
+	It("Set spilling root of Database with scratch space", func() {
+		storage := newStoragePoolsStorage()
+		storage.Spec.StoragePools = nil
+		database := &v1alpha1.Database{}
+		database.Name = "database"
+		database.Spec.ScratchSpace = &v1alpha1.ScratchSpaceSpec{}
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, database)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := map[string]interface{}{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+		Expect(config["table_service_config"]).Should(BeEquivalentTo(map[string]interface{}{
+			"spilling_service_config": map[string]interface{}{
+				"local_file_config": map[string]interface{}{
+					"enable": true,
+					"root":   v1alpha1.ScratchSpaceDir,
+				},
+			},
+		}))
+	})
+
+	It("Keep spilling service configured explicitly", func() {
+		storage := newStoragePoolsStorage()
+		storage.Spec.StoragePools = nil
+		storage.Spec.Configuration = storagePoolsConfigExample + `table_service_config:
+  spilling_service_config:
+    local_file_config:
+      enable: false
+`
+		database := &v1alpha1.Database{}
+		database.Name = "database"
+		database.Spec.ScratchSpace = &v1alpha1.ScratchSpaceSpec{}
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, database)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(rawConfig)).ShouldNot(ContainSubstring(v1alpha1.ScratchSpaceDir))
+	})
+
+	It("Build static group matching golden output for every erasure", func() {
+		Expect(buildStaticGroup(newTopologyStorage(v1alpha1.None, 1))).Should(MatchYAML(goldenStaticGroupNone))
+		Expect(buildStaticGroup(newTopologyStorage(v1alpha1.ErasureBlock42, 8))).Should(MatchYAML(goldenStaticGroupBlock42))
+		Expect(buildStaticGroup(newTopologyStorage(v1alpha1.ErasureMirror3of4, 8))).Should(MatchYAML(goldenStaticGroupMirror3of4))
+
+		storage := newTopologyStorage(v1alpha1.ErasureMirror3DC, 3)
+		storage.Spec.PDisksPerNode = 3
+		Expect(buildStaticGroup(storage)).Should(MatchYAML(goldenStaticGroupMirror3DC))
+	})
+
+	It("Place hosts to data centers of topology", func() {
+		storage := newTopologyStorage(v1alpha1.ErasureMirror3DC, 6)
+		storage.Spec.Topology.DataCenters = []string{"vla", "sas", "klg"}
+
+		rawConfig, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		config := schema.Configuration{}
+		Expect(yaml.Unmarshal(rawConfig, &config)).Should(Succeed())
+
+		Expect(config.Hosts).Should(HaveLen(6))
+		for i, dataCenter := range []string{"vla", "sas", "klg", "vla", "sas", "klg"} {
+			Expect(config.Hosts[i].WalleLocation.DataCenter).Should(Equal(dataCenter))
+		}
+		Expect(config.BlobStorageConfig.ServiceSet.Groups[0].Rings).Should(HaveLen(3))
+		Expect(config.BlobStorageConfig.ServiceSet.Groups[0].Rings[1].FailDomains[1].VDiskLocations[0].NodeID).Should(Equal(5))
+	})
+
+	It("Keep static group of configuration", func() {
+		storage := newTopologyStorage(v1alpha1.None, 1)
+		storage.Spec.Configuration = "blob_storage_config:\n  service_set:\n    groups:\n    - erasure_species: none\n"
+
+		Expect(buildStaticGroup(storage)).ShouldNot(ContainSubstring("rings"))
+	})
+
+	It("Refuse to generate static group without pdisks", func() {
+		storage := newTopologyStorage(v1alpha1.None, 1)
+		fsMode := corev1.PersistentVolumeFilesystem
+		storage.Spec.DataStore[0].VolumeMode = &fsMode
+
+		_, err := v1alpha1.BuildConfiguration(storage, nil)
+		Expect(err).Should(MatchError(ContainSubstring("without pdisks")))
+	})
 })
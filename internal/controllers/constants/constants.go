@@ -24,23 +24,43 @@ const (
 	StorageProvisionedCondition = "StorageProvisioned"
 	StoragePausedCondition      = "StoragePaused"
 	StorageReadyCondition       = "StorageReady"
+	StorageHealthyCondition     = "StorageHealthy"
+	StorageEphemeralCondition   = "StorageEphemeral"
+
+	SpecInvalidCondition = "SpecInvalid"
+
+	UpgradeRolledBackCondition = "UpgradeRolledBack"
+	RolloutStuckCondition      = "RolloutStuck"
 
 	DatabasePreparedCondition    = "DatabasePrepared"
 	DatabaseInitializedCondition = "DatabaseInitialized"
 	DatabaseProvisionedCondition = "DatabaseProvisioned"
 	DatabasePausedCondition      = "DatabasePaused"
 	DatabaseReadyCondition       = "DatabaseReady"
+	DatabaseClonedCondition      = "DatabaseCloned"
+	SchemaInitializedCondition   = "SchemaInitialized"
+	WaitingForStorageCondition   = "WaitingForStorage"
 
 	NodeSetPreparedCondition    = "NodeSetPrepared"
 	NodeSetProvisionedCondition = "NodeSetProvisioned"
 	NodeSetReadyCondition       = "NodeSetReady"
 	NodeSetPausedCondition      = "NodeSetPaused"
 
+	EncryptionKeyRotatedCondition = "KeyRotated"
+
 	CreateDatabaseOperationCondition = "CreateDatabaseOperation"
 	ReplaceConfigOperationCondition  = "ReplaceConfigOperation"
 
-	ConfigurationSyncedCondition  = "ConfigurationSynced"
-	RemoteResourceSyncedCondition = "ResourceSynced"
+	// ReadyCondition aggregates all sub-conditions of the resource
+	ReadyCondition = "Ready"
+
+	ConfigurationSyncedCondition    = "ConfigurationSynced"
+	RemoteResourceSyncedCondition   = "ResourceSynced"
+	DefineBoxSyncedCondition        = "DefineBoxSynced"
+	ConfigValidationFailedCondition = "ConfigValidationFailed"
+	ConfigInSyncCondition           = "ConfigInSync"
+	ConfigPendingCondition          = "ConfigPending"
+	UnsupportedVersionCondition     = "UnsupportedVersion"
 
 	Stop     = true
 	Continue = false
@@ -50,12 +70,21 @@ const (
 	ReasonCompleted   = "Completed"
 	ReasonFailed      = "Failed"
 
+	ReasonImagePullError   = "ImagePullError"
+	ReasonCrashLoopBackOff = "CrashLoopBackOff"
+	ReasonUnschedulable    = "Unschedulable"
+	ReasonConfigDrift      = "ConfigDriftDetected"
+
 	DefaultRequeueDelay                = 10 * time.Second
 	StatusUpdateRequeueDelay           = 1 * time.Second
 	ReplaceConfigOperationRequeueDelay = 15 * time.Second
 	SelfCheckRequeueDelay              = 30 * time.Second
 	StorageInitializationRequeueDelay  = 30 * time.Second
 	DatabaseInitializationRequeueDelay = 30 * time.Second
+	ReadyRequeueDelay                  = 5 * time.Minute
+
+	DefaultRolloutStuckTimeout = 10 * time.Minute
+	BackupCheckTimeout         = 5 * time.Minute
 
 	DatabasePending      ClusterState = "Pending"
 	DatabasePreparing    ClusterState = "Preparing"
@@ -88,9 +117,12 @@ const (
 
 	StorageAwaitRequeueDelay        = 30 * time.Second
 	SharedDatabaseAwaitRequeueDelay = 30 * time.Second
+	RBACInsufficientRequeueDelay    = 5 * time.Minute
+	TransientErrorRequeueDelay      = 5 * time.Second
 
 	OwnerControllerField = ".metadata.controller"
 	DatabaseRefField     = ".spec.databaseRef.name"
 	StorageRefField      = ".spec.storageRef.name"
 	SecretField          = ".spec.secrets"
+	ConfigMapField       = ".spec.configMaps"
 )
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ydb "github.com/ydb-platform/ydb-go-sdk/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// checkRunningBackups blocks deletion of the Database while export
+// operations of the database are running, so that removal of the tenant
+// does not corrupt the exported backup. Database which is not Ready, or
+// whose Storage is not Ready or is being deleted, can not have running
+// exports and is deleted without the check. When exports can not be
+// listed, deletion is blocked for BackupCheckTimeout only.
+func (r *Reconciler) checkRunningBackups(
+	ctx context.Context,
+	ydbCr *v1alpha1.Database,
+) error {
+	if ydbCr.Status.State != DatabaseReady {
+		return nil
+	}
+
+	running, err := r.listRunningExports(ctx, ydbCr)
+	if err != nil {
+		if time.Since(ydbCr.DeletionTimestamp.Time) > BackupCheckTimeout {
+			r.Recorder.Event(
+				ydbCr,
+				corev1.EventTypeWarning,
+				"DeletionUnblocked",
+				fmt.Sprintf("Running backups are not checked for %s, deleting: %s", BackupCheckTimeout, err),
+			)
+			return nil
+		}
+		r.Recorder.Event(
+			ydbCr,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to list export operations: %s", err),
+		)
+		return err
+	}
+
+	if len(running) > 0 {
+		message := fmt.Sprintf("Waiting for running backups to finish or to be cancelled: %v", running)
+		log.FromContext(ctx).Info(message)
+		r.Recorder.Event(
+			ydbCr,
+			corev1.EventTypeWarning,
+			"DeletionBlocked",
+			message,
+		)
+		return errors.New(message)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) listRunningExports(
+	ctx context.Context,
+	ydbCr *v1alpha1.Database,
+) ([]string, error) {
+	database := resources.NewDatabase(ydbCr)
+	storageClient, err := r.getStorageClient(ctx, &database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client of Storage cluster: %w", err)
+	}
+	storage := &v1alpha1.Storage{}
+	err = storageClient.Get(ctx, types.NamespacedName{
+		Name:      database.Spec.StorageClusterRef.Name,
+		Namespace: database.Spec.StorageClusterRef.Namespace,
+	}, storage)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Storage: %w", err)
+	}
+	if !storage.DeletionTimestamp.IsZero() || storage.Status.State != StorageReady {
+		return nil, nil
+	}
+	database.Storage = storage
+
+	creds, err := resources.GetYDBCredentials(ctx, database.Storage, r.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get YDB credentials: %w", err)
+	}
+	tlsOptions, err := resources.GetYDBTLSOption(ctx, database.Storage, r.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get YDB TLS options: %w", err)
+	}
+	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(database.Storage))
+
+	endpoint := fmt.Sprintf("%s%s", database.Spec.GetOperatorStorageEndpoint(), database.GetDatabasePath())
+	return cms.ListRunningExports(ctx, endpoint, ydbOpts)
+}
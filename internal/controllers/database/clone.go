@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleDatabaseClone copies schema, and optionally data, of the source
+// Database into the created Database by Job. Failed clone is reported in
+// DatabaseCloned condition and is not retried, Database stays usable.
+func (r *Reconciler) handleDatabaseClone(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step handleDatabaseClone")
+
+	condition := meta.FindStatusCondition(database.Status.Conditions, DatabaseClonedCondition)
+	if condition != nil && (condition.Status == metav1.ConditionTrue || condition.Reason == ReasonFailed) {
+		log.FromContext(ctx).Info("complete step handleDatabaseClone")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	sourceName := database.Spec.SourceDatabaseRef.Name
+	sourceNamespace := database.Spec.SourceDatabaseRef.Namespace
+	if sourceNamespace == "" {
+		sourceNamespace = database.Namespace
+	}
+
+	source := &v1alpha1.Database{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      sourceName,
+		Namespace: sourceNamespace,
+	}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.setClonePending(ctx, database, fmt.Sprintf("Waiting for source Database %s/%s", sourceNamespace, sourceName))
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get source Database: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	if source.Spec.StorageClusterRef.Name != database.Storage.Name ||
+		source.Spec.StorageClusterRef.Namespace != database.Storage.Namespace {
+		return r.setCloneFailed(ctx, database, fmt.Sprintf(
+			"Source Database %s/%s is not served by Storage %s/%s",
+			source.Namespace, source.Name, database.Storage.Namespace, database.Storage.Name,
+		))
+	}
+
+	if !meta.IsStatusConditionTrue(source.Status.Conditions, DatabaseInitializedCondition) {
+		return r.setClonePending(ctx, database, fmt.Sprintf("Waiting for source Database %s/%s to be initialized", source.Namespace, source.Name))
+	}
+
+	withOperatorToken := database.Storage.Spec.OperatorConnection != nil
+	if withOperatorToken {
+		if err := r.createOrUpdateOperatorTokenSecret(ctx, database); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to create operator token Secret: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+	}
+
+	cloneJob := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      fmt.Sprintf(resources.CloneJobNameFormat, database.Name),
+		Namespace: database.Namespace,
+	}, cloneJob)
+	if apierrors.IsNotFound(err) {
+		if err := r.createCloneJob(ctx, database, source, withOperatorToken); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to create clone Job: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			"CloningDatabase",
+			fmt.Sprintf("Cloning from source Database %s", source.GetDatabasePath()),
+		)
+		return r.setClonePending(ctx, database, fmt.Sprintf("Cloning from source Database %s", source.GetDatabasePath()))
+	}
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get clone Job: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	if cloneJob.Status.Succeeded > 0 {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			"CloningDatabase",
+			fmt.Sprintf("Database cloned from %s successfully", source.GetDatabasePath()),
+		)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               DatabaseClonedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonCompleted,
+			Message:            fmt.Sprintf("Cloned from source Database %s", source.GetDatabasePath()),
+		})
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	for _, jobCondition := range cloneJob.Status.Conditions {
+		if jobCondition.Type == batchv1.JobFailed && jobCondition.Status == corev1.ConditionTrue {
+			return r.setCloneFailed(ctx, database, fmt.Sprintf(
+				"Job %s failed, see its logs for details: %s", cloneJob.Name, jobCondition.Message,
+			))
+		}
+	}
+
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DatabaseInitializationRequeueDelay)}, nil
+}
+
+func (r *Reconciler) setClonePending(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	message string,
+) (bool, ctrl.Result, error) {
+	if !meta.IsStatusConditionPresentAndEqual(database.Status.Conditions, DatabaseClonedCondition, metav1.ConditionFalse) ||
+		meta.FindStatusCondition(database.Status.Conditions, DatabaseClonedCondition).Message != message {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               DatabaseClonedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonInProgress,
+			Message:            message,
+		})
+		return r.updateStatus(ctx, database, DatabaseInitializationRequeueDelay)
+	}
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DatabaseInitializationRequeueDelay)}, nil
+}
+
+func (r *Reconciler) setCloneFailed(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	message string,
+) (bool, ctrl.Result, error) {
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeWarning,
+		"CloningDatabase",
+		fmt.Sprintf("Failed to clone Database: %s", message),
+	)
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:               DatabaseClonedCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: database.Generation,
+		Reason:             ReasonFailed,
+		Message:            message,
+	})
+	return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+}
+
+func (r *Reconciler) createCloneJob(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	source *v1alpha1.Database,
+	withOperatorToken bool,
+) error {
+	builder := resources.GetDatabaseCloneJobBuilder(database.DeepCopy(), source, withOperatorToken)
+	newResource := builder.Placeholder(database)
+	_, err := resources.CreateOrUpdateOrMaybeIgnore(ctx, r.Client, newResource, func() error {
+		if err := builder.Build(newResource); err != nil {
+			return err
+		}
+		return ctrl.SetControllerReference(database.Unwrap(), newResource, r.Scheme)
+	}, shouldIgnoreJobUpdate())
+
+	return err
+}
+
+func (r *Reconciler) createOrUpdateOperatorTokenSecret(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) error {
+	creds, err := resources.GetYDBCredentials(ctx, database.Storage, r.Config)
+	if err != nil {
+		return err
+	}
+
+	tokenCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	token, err := creds.Token(
+		metadata.AppendToOutgoingContext(tokenCtx, "x-ydb-database", database.Storage.Spec.Domain),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get token from ydb credentials, error: %w", err)
+	}
+
+	builder := resources.GetOperatorTokenSecretBuilder(database, token)
+	newResource := builder.Placeholder(database)
+	_, err = resources.CreateOrUpdateOrMaybeIgnore(ctx, r.Client, newResource, func() error {
+		if err := builder.Build(newResource); err != nil {
+			return err
+		}
+		return ctrl.SetControllerReference(database.Unwrap(), newResource, r.Scheme)
+	}, resources.DoNotIgnoreChanges())
+
+	return err
+}
+
+func shouldIgnoreJobUpdate() resources.IgnoreChangesFunction {
+	return func(oldObj, newObj runtime.Object) bool {
+		if _, ok := oldObj.(*batchv1.Job); ok {
+			return true
+		}
+		return false
+	}
+}
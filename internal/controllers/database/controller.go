@@ -3,10 +3,11 @@ package database
 import (
 	"context"
 
-	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -15,6 +16,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -22,8 +25,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	ydbannotations "github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
 )
 
 // Reconciler reconciles a Database object
@@ -32,7 +40,15 @@ type Reconciler struct {
 	Scheme   *runtime.Scheme
 	Config   *rest.Config
 	Recorder record.EventRecorder
-	Log      logr.Logger
+
+	MaxConcurrentReconciles int
+	// CMSPollOptions bound checking of tenant creation operation on requeue
+	CMSPollOptions cms.PollOptions
+
+	storageClients         storageClients
+	requeueDelayWarnings   requeue.Warnings
+	versionIgnoredWarnings requeue.Warnings
+	initScripts            scripting.Runner
 }
 
 //+kubebuilder:rbac:groups=ydb.tech,resources=databases,verbs=get;list;watch;create;update;patch;delete
@@ -49,31 +65,79 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=secrets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	r.Log = log.FromContext(ctx)
-
 	resource := &v1alpha1.Database{}
 	err := r.Get(ctx, req.NamespacedName, resource)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			r.Log.Info("Database resource not found")
+			log.FromContext(ctx).Info("Database resource not found")
+			metrics.DeleteClusterReady(DatabaseKind, req.Namespace, req.Name)
+			r.storageClients.forget(req.NamespacedName)
 			return ctrl.Result{Requeue: false}, nil
 		}
-		r.Log.Error(err, "unexpected Get error")
+		log.FromContext(ctx).Error(err, "unexpected Get error")
 		return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
 
+	//nolint:nestif
+	// examine DeletionTimestamp to determine if object is under deletion
+	if resource.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is not being deleted, so if it does not have our finalizer,
+		// then lets add the finalizer and update the object. This is equivalent
+		// to registering our finalizer.
+		if !controllerutil.ContainsFinalizer(resource, ydbannotations.DatabaseFinalizerKey) {
+			controllerutil.AddFinalizer(resource, ydbannotations.DatabaseFinalizerKey)
+			if err := r.Client.Update(ctx, resource); err != nil {
+				return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			}
+		}
+	} else {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(resource, ydbannotations.DatabaseFinalizerKey) {
+			// our finalizer is present, so lets wait for backups of the
+			// database which are still running
+			if err := r.checkRunningBackups(ctx, resource); err != nil {
+				// if backups are running or can not be checked, return with
+				// error so that it can be retried.
+				return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			}
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(resource, ydbannotations.DatabaseFinalizerKey)
+			if err := r.Client.Update(ctx, resource); err != nil {
+				return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			}
+			metrics.DeleteClusterReady(DatabaseKind, resource.Namespace, resource.Name)
+			r.storageClients.forget(req.NamespacedName)
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{Requeue: false}, nil
+	}
+
+	metrics.SetClusterReady(
+		DatabaseKind,
+		resource.Namespace,
+		resource.Name,
+		meta.IsStatusConditionTrue(resource.Status.Conditions, DatabaseReadyCondition),
+	)
+
 	result, err := r.Sync(ctx, resource)
 	if err != nil {
-		r.Log.Error(err, "unexpected Sync error")
+		log.FromContext(ctx).Error(err, "unexpected Sync error")
 	}
 
 	return result, err
@@ -125,22 +189,28 @@ func createFieldIndexers(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&v1alpha1.Database{},
+		ConfigMapField,
+		func(obj client.Object) []string {
+			// ConfigMaps with initScripts are indexed, so that changed
+			// scripts are executed by reconcile
+			database := obj.(*v1alpha1.Database)
+			return database.GetReferencedConfigMaps()
+		}); err != nil {
+		return err
+	}
+
 	return mgr.GetFieldIndexer().IndexField(
 		context.Background(),
 		&v1alpha1.Database{},
 		SecretField,
 		func(obj client.Object) []string {
-			secrets := []string{}
-			// grab the Database object, extract secrets from spec...
+			// TLS certificates and encryption key are indexed as well,
+			// so that their rotation is picked up by reconcile
 			database := obj.(*v1alpha1.Database)
-
-			// ...append declared Secret to index...
-			for _, secret := range database.Spec.Secrets {
-				secrets = append(secrets, secret.Name)
-			}
-
-			// ...and if so, return it
-			return secrets
+			return database.GetReferencedSecrets()
 		})
 }
 
@@ -149,7 +219,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Recorder = mgr.GetEventRecorderFor(DatabaseKind)
 	controller := ctrl.NewControllerManagedBy(mgr)
 	if err := createFieldIndexers(mgr); err != nil {
-		r.Log.Error(err, "unexpected FieldIndexer error")
+		mgr.GetLogger().Error(err, "unexpected FieldIndexer error")
 		return err
 	}
 
@@ -166,19 +236,31 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.StatefulSet{},
 			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
 		).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Owns(&corev1.ConfigMap{},
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
 		Owns(&corev1.Service{},
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Owns(&corev1.Secret{},
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Watches(
 			&source.Kind{Type: &corev1.Secret{}},
 			handler.EnqueueRequestsFromMapFunc(r.findDatabasesForSecret),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.findDatabasesForConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		WithEventFilter(resources.IsDatabaseCreatePredicate()).
 		WithEventFilter(resources.IgnoreDeleteStateUnknownPredicate()).
+		WithOptions(ctrlcontroller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
@@ -206,3 +288,28 @@ func (r *Reconciler) findDatabasesForSecret(secret client.Object) []reconcile.Re
 	}
 	return requests
 }
+
+// Find all Databases which read initScripts from ConfigMap and make request for Reconcile
+func (r *Reconciler) findDatabasesForConfigMap(configMap client.Object) []reconcile.Request {
+	attachedDatabases := &v1alpha1.DatabaseList{}
+	err := r.List(
+		context.Background(),
+		attachedDatabases,
+		client.InNamespace(configMap.GetNamespace()),
+		client.MatchingFields{ConfigMapField: configMap.GetName()},
+	)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(attachedDatabases.Items))
+	for i, item := range attachedDatabases.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
@@ -13,18 +13,27 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	testobjects "github.com/ydb-platform/ydb-kubernetes-operator/e2e/tests/test-objects"
+	ydbannotations "github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/database"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/storage"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/test"
 )
 
@@ -221,6 +230,42 @@ var _ = Describe("Database controller medium tests", func() {
 		}, test.Timeout, test.Interval).Should(BeTrue())
 	})
 
+	It("Waits for pods of StatefulSet to become ready", func() {
+		By("Create test database")
+		databaseSample := *testobjects.DefaultDatabase()
+		Expect(k8sClient.Create(ctx, &databaseSample)).Should(Succeed())
+
+		By("Schedule all pods of StatefulSet without readiness")
+		Eventually(func() error {
+			sts := appsv1.StatefulSet{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      testobjects.DatabaseName,
+				Namespace: testobjects.YdbNamespace,
+			}, &sts); err != nil {
+				return err
+			}
+			sts.Status.ObservedGeneration = sts.Generation
+			sts.Status.Replicas = *sts.Spec.Replicas
+			sts.Status.ReadyReplicas = 0
+			return k8sClient.Status().Update(ctx, &sts)
+		}, test.Timeout, test.Interval).ShouldNot(HaveOccurred())
+
+		By("Check that Database is not provisioned until pods are ready")
+		Eventually(func(g Gomega) {
+			found := v1alpha1.Database{}
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      testobjects.DatabaseName,
+				Namespace: testobjects.YdbNamespace,
+			}, &found)).Should(Succeed())
+			condition := meta.FindStatusCondition(found.Status.Conditions, DatabaseProvisionedCondition)
+			g.Expect(condition).ShouldNot(BeNil())
+			g.Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			g.Expect(condition.Message).Should(ContainSubstring("ready pods"))
+			g.Expect(found.Status.Replicas).Should(BeZero())
+			g.Expect(meta.IsStatusConditionTrue(found.Status.Conditions, DatabaseReadyCondition)).Should(BeFalse())
+		}, test.Timeout, test.Interval).Should(Succeed())
+	})
+
 	It("Check iPDiscovery flag works", func() {
 		getDBSts := func(generation int64) appsv1.StatefulSet {
 			sts := appsv1.StatefulSet{}
@@ -304,4 +349,776 @@ var _ = Describe("Database controller medium tests", func() {
 
 		Expect(args).To(ContainElements([]string{"--grpc-public-address-v4", "--grpc-public-target-name-override"}))
 	})
+
+	It("Recreates deleted Service of Ready Database", func() {
+		By("Create test database")
+		databaseSample := *testobjects.DefaultDatabase()
+		Expect(k8sClient.Create(ctx, &databaseSample)).Should(Succeed())
+
+		By("Make all pods of StatefulSet ready")
+		Eventually(func() error {
+			sts := appsv1.StatefulSet{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      testobjects.DatabaseName,
+				Namespace: testobjects.YdbNamespace,
+			}, &sts); err != nil {
+				return err
+			}
+			sts.Status.ObservedGeneration = sts.Generation
+			sts.Status.Replicas = *sts.Spec.Replicas
+			sts.Status.ReadyReplicas = *sts.Spec.Replicas
+			return k8sClient.Status().Update(ctx, &sts)
+		}, test.Timeout, test.Interval).ShouldNot(HaveOccurred())
+
+		By("Wait for Database to become Ready with initialized tenant")
+		Eventually(func(g Gomega) {
+			found := v1alpha1.Database{}
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      testobjects.DatabaseName,
+				Namespace: testobjects.YdbNamespace,
+			}, &found)).Should(Succeed())
+			if !meta.IsStatusConditionTrue(found.Status.Conditions, DatabaseInitializedCondition) {
+				meta.SetStatusCondition(&found.Status.Conditions, metav1.Condition{
+					Type:   DatabaseInitializedCondition,
+					Status: metav1.ConditionTrue,
+					Reason: ReasonCompleted,
+				})
+				g.Expect(k8sClient.Status().Update(ctx, &found)).Should(Succeed())
+			}
+			g.Expect(found.Status.State).Should(Equal(DatabaseReady))
+			g.Expect(found.Status.ObservedGeneration).Should(Equal(found.Generation))
+		}, test.Timeout, test.Interval).Should(Succeed())
+
+		grpcService := corev1.Service{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{
+				Name:      fmt.Sprintf(resources.GRPCServiceNameFormat, testobjects.DatabaseName),
+				Namespace: testobjects.YdbNamespace,
+			}, &grpcService)
+		}, test.Timeout, test.Interval).ShouldNot(HaveOccurred())
+
+		By("Delete gRPC Service")
+		Expect(k8sClient.Delete(ctx, &grpcService)).Should(Succeed())
+
+		By("Check that gRPC Service is recreated")
+		Eventually(func(g Gomega) {
+			found := corev1.Service{}
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      fmt.Sprintf(resources.GRPCServiceNameFormat, testobjects.DatabaseName),
+				Namespace: testobjects.YdbNamespace,
+			}, &found)).Should(Succeed())
+			g.Expect(found.UID).ShouldNot(Equal(grpcService.UID))
+		}, test.Timeout, test.Interval).Should(Succeed())
+	})
+})
+
+// conflictingClient fails every status update with Conflict, as if the
+// object was changed concurrently after it was fetched
+type conflictingClient struct {
+	client.Client
+}
+
+func (c conflictingClient) Status() client.StatusWriter {
+	return conflictingStatusWriter{c.Client.Status()}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+}
+
+func (w conflictingStatusWriter) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return apierrors.NewConflict(v1alpha1.GroupVersion.WithResource("databases").GroupResource(), obj.GetName(), errors.New("object has been modified"))
+}
+
+var _ = Describe("Database status update", func() {
+	It("requeues immediately on conflict", func() {
+		databaseSample := testobjects.DefaultDatabase()
+		reconciler := &database.Reconciler{
+			Client:   conflictingClient{fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(databaseSample).Build()},
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+})
+
+var _ = Describe("Database waiting for Storage", func() {
+	It("waits for Storage bootstrap with backoff and a single Normal event", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		databaseSample := testobjects.DefaultDatabase()
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample).Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		var result ctrl.Result
+		for i := 0; i < 5; i++ {
+			var err error
+			result, err = reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(result.RequeueAfter).To(BeNumerically(">=", StatusUpdateRequeueDelay))
+		Expect(result.RequeueAfter).To(BeNumerically("<", StorageAwaitRequeueDelay))
+
+		found := &v1alpha1.Database{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, WaitingForStorageCondition)).To(BeTrue())
+
+		close(recorder.Events)
+		waiting := 0
+		for event := range recorder.Events {
+			Expect(event).ShouldNot(HavePrefix(corev1.EventTypeWarning))
+			if strings.Contains(event, WaitingForStorageCondition) {
+				waiting++
+			}
+		}
+		Expect(waiting).To(Equal(1))
+	})
+})
+
+var _ = Describe("Database deletion", func() {
+	It("adds finalizer and removes it from Database which is not Ready", func() {
+		databaseSample := testobjects.DefaultDatabase()
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(databaseSample).Build()
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ShouldNot(HaveOccurred())
+		found := &v1alpha1.Database{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(found.Finalizers).To(ContainElement(ydbannotations.DatabaseFinalizerKey))
+
+		Expect(fakeClient.Delete(context.Background(), found)).Should(Succeed())
+		_, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = fakeClient.Get(context.Background(), request.NamespacedName, found)
+		if err == nil {
+			Expect(found.Finalizers).ToNot(ContainElement(ydbannotations.DatabaseFinalizerKey))
+		} else {
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	})
+
+	It("removes finalizer of Ready Database whose Storage is not Ready", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StorageProvisioning
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Finalizers = []string{ydbannotations.DatabaseFinalizerKey}
+		databaseSample.Status.State = DatabaseReady
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample).Build()
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		Expect(fakeClient.Delete(context.Background(), databaseSample)).Should(Succeed())
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		found := &v1alpha1.Database{}
+		err = fakeClient.Get(context.Background(), request.NamespacedName, found)
+		if err == nil {
+			Expect(found.Finalizers).ToNot(ContainElement(ydbannotations.DatabaseFinalizerKey))
+		} else {
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	})
+
+	It("keeps finalizer of Ready Database while Storage is unreachable", func() {
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Finalizers = []string{ydbannotations.DatabaseFinalizerKey}
+		databaseSample.Status.State = DatabaseReady
+		databaseSample.Spec.StorageClusterRef.KubeconfigSecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "missing-kubeconfig"},
+			Key:                  "config",
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(databaseSample).Build()
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		Expect(fakeClient.Delete(context.Background(), databaseSample)).Should(Succeed())
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).Should(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(DefaultRequeueDelay))
+
+		found := &v1alpha1.Database{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(found.Finalizers).To(ContainElement(ydbannotations.DatabaseFinalizerKey))
+	})
+})
+
+var _ = Describe("Database pause", func() {
+	It("scales paused Database to zero and keeps tenant initialized", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StorageReady
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Spec.Pause = true
+		databaseSample.Status.State = DatabaseReady
+		meta.SetStatusCondition(&databaseSample.Status.Conditions, metav1.Condition{
+			Type:   DatabaseInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample).Build()
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		found := &v1alpha1.Database{}
+		for i := 0; i < 10 && found.Status.State != DatabasePaused; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		}
+		Expect(found.Status.State).To(Equal(DatabasePaused))
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, DatabaseInitializedCondition)).To(BeTrue())
+
+		sts := &appsv1.StatefulSet{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, sts)).Should(Succeed())
+		Expect(*sts.Spec.Replicas).To(BeZero())
+	})
+})
+
+var _ = Describe("Database with paused Storage", func() {
+	It("reports pause of Storage once", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StoragePaused
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+		databaseSample := testobjects.DefaultDatabase()
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample).Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		for i := 0; i < 6; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		found := &v1alpha1.Database{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, StoragePausedCondition)).To(BeTrue())
+
+		close(recorder.Events)
+		paused := 0
+		for event := range recorder.Events {
+			if strings.Contains(event, "StoragePaused") {
+				paused++
+			}
+		}
+		Expect(paused).To(Equal(1))
+	})
+
+	It("reports pause and resume of Storage to Ready Database", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StoragePaused
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Status.State = DatabaseReady
+		databaseBuilder := resources.NewDatabase(databaseSample)
+		databaseSample.Status.AppliedSchemaOperationQuotasHash = databaseBuilder.GetSchemaOperationQuotasHash()
+		for _, condition := range []string{DatabaseInitializedCondition, DatabaseProvisionedCondition, SchemaInitializedCondition} {
+			meta.SetStatusCondition(&databaseSample.Status.Conditions, metav1.Condition{
+				Type:   condition,
+				Status: metav1.ConditionTrue,
+				Reason: ReasonCompleted,
+			})
+		}
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      databaseSample.Name,
+				Namespace: databaseSample.Namespace,
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        databaseSample.Spec.Nodes,
+				ReadyReplicas:   databaseSample.Spec.Nodes,
+				UpdatedReplicas: databaseSample.Spec.Nodes,
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample, sts).Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		countEvents := func(reason string) int {
+			count := 0
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, reason) {
+					count++
+				}
+			}
+			return count
+		}
+
+		found := &v1alpha1.Database{}
+		for i := 0; i < 4; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(found.Status.State).To(Equal(DatabaseReady))
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, StoragePausedCondition)).To(BeTrue())
+		Expect(countEvents("StoragePaused")).To(Equal(1))
+
+		foundStorage := &v1alpha1.Storage{}
+		Expect(fakeClient.Get(context.Background(), types.NamespacedName{
+			Name:      storageSample.Name,
+			Namespace: storageSample.Namespace,
+		}, foundStorage)).Should(Succeed())
+		foundStorage.Status.State = StorageReady
+		Expect(fakeClient.Status().Update(context.Background(), foundStorage)).Should(Succeed())
+
+		for i := 0; i < 4; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(meta.FindStatusCondition(found.Status.Conditions, StoragePausedCondition)).To(BeNil())
+		Expect(countEvents("StorageResumed")).To(Equal(1))
+	})
+})
+
+var _ = Describe("Database with Storage in remote cluster", func() {
+	remoteNamespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "remote-storage",
+		},
+	}
+
+	BeforeEach(func() {
+		Expect(k8sClient.Create(ctx, remoteNamespace.DeepCopy())).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		test.DeleteAllObjects(env, k8sClient, remoteNamespace.DeepCopy())
+	})
+
+	It("gets Storage with client of kubeconfig Secret", func() {
+		// Storage is created in the envtest cluster only, which plays the
+		// role of remote cluster for Database kept by fake client
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Namespace = remoteNamespace.Name
+		Expect(k8sClient.Create(ctx, storageSample)).Should(Succeed())
+
+		kubeconfig := clientcmdapi.NewConfig()
+		kubeconfig.Clusters["remote"] = &clientcmdapi.Cluster{
+			Server:                   env.Config.Host,
+			CertificateAuthorityData: env.Config.CAData,
+		}
+		kubeconfig.AuthInfos["remote"] = &clientcmdapi.AuthInfo{
+			ClientCertificateData: env.Config.CertData,
+			ClientKeyData:         env.Config.KeyData,
+			Token:                 env.Config.BearerToken,
+		}
+		kubeconfig.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "remote"}
+		kubeconfig.CurrentContext = "remote"
+		kubeconfigData, err := clientcmd.Write(*kubeconfig)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		databaseSample := testobjects.DefaultDatabase()
+		kubeconfigSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "remote-kubeconfig",
+				Namespace: databaseSample.Namespace,
+			},
+			Data: map[string][]byte{"config": kubeconfigData},
+		}
+		databaseSample.Spec.StorageClusterRef.Namespace = remoteNamespace.Name
+		databaseSample.Spec.StorageClusterRef.KubeconfigSecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: kubeconfigSecret.Name},
+			Key:                  "config",
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(databaseSample, kubeconfigSecret).Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		for i := 0; i < 3; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		found := &v1alpha1.Database{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, WaitingForStorageCondition)).To(BeTrue())
+
+		By("removing finalizer of deleted Database...")
+		Expect(fakeClient.Delete(context.Background(), found)).Should(Succeed())
+		_, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		close(recorder.Events)
+		for event := range recorder.Events {
+			Expect(event).ShouldNot(HavePrefix(corev1.EventTypeWarning))
+		}
+	})
+})
+
+var _ = Describe("Database init scripts", func() {
+	script := "CREATE TABLE example (id Uint64, PRIMARY KEY (id));"
+
+	newReadyDatabase := func(appliedInitScripts ...string) (client.Client, ctrl.Request) {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StorageReady
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Spec.InitScripts = []v1alpha1.InitScript{{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "init-scripts"},
+				Key:                  "schema.yql",
+			},
+		}}
+		databaseSample.Status.State = DatabaseReady
+		databaseSample.Status.AppliedInitScripts = appliedInitScripts
+		databaseBuilder := resources.NewDatabase(databaseSample)
+		databaseSample.Status.AppliedSchemaOperationQuotasHash = databaseBuilder.GetSchemaOperationQuotasHash()
+		for _, condition := range []string{DatabaseInitializedCondition, DatabaseProvisionedCondition, SchemaInitializedCondition} {
+			meta.SetStatusCondition(&databaseSample.Status.Conditions, metav1.Condition{
+				Type:   condition,
+				Status: metav1.ConditionTrue,
+				Reason: ReasonCompleted,
+			})
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "init-scripts",
+				Namespace: databaseSample.Namespace,
+			},
+			Data: map[string]string{"schema.yql": script},
+		}
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      databaseSample.Name,
+				Namespace: databaseSample.Namespace,
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        databaseSample.Spec.Nodes,
+				ReadyReplicas:   databaseSample.Spec.Nodes,
+				UpdatedReplicas: databaseSample.Spec.Nodes,
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample, configMap, sts).Build()
+		return fakeClient, ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+	}
+
+	It("drops checksums of removed scripts without executing applied ones", func() {
+		fakeClient, request := newReadyDatabase(resources.SHAChecksum(script), resources.SHAChecksum("DROP TABLE removed;"))
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+
+		found := &v1alpha1.Database{}
+		for i := 0; i < 10 && len(found.Status.AppliedInitScripts) != 1; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		}
+		Expect(found.Status.AppliedInitScripts).To(Equal([]string{resources.SHAChecksum(script)}))
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, SchemaInitializedCondition)).To(BeTrue())
+
+		close(recorder.Events)
+		for event := range recorder.Events {
+			Expect(event).ShouldNot(ContainSubstring("InitScript"))
+		}
+	})
+
+	It("executes script changed in ConfigMap of Ready Database", func() {
+		fakeClient, request := newReadyDatabase(resources.SHAChecksum("CREATE TABLE previous (id Uint64, PRIMARY KEY (id));"))
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+
+		found := &v1alpha1.Database{}
+		for i := 0; i < 10 && !meta.IsStatusConditionPresentAndEqual(found.Status.Conditions, SchemaInitializedCondition, metav1.ConditionUnknown); i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		}
+		Expect(found.Status.AppliedInitScripts).To(BeEmpty())
+		condition := meta.FindStatusCondition(found.Status.Conditions, SchemaInitializedCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(condition.Message).To(Equal("Executing initScripts[0]"))
+	})
+})
+
+var _ = Describe("Database encryption key rotation", func() {
+	It("keeps Ready Database reconciled while nodes restart with new key", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StorageReady
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Generation = 2
+		databaseSample.Spec.Encryption = &v1alpha1.EncryptionConfig{
+			Enabled: true,
+			Key: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "encryption-key"},
+				Key:                  "key",
+			},
+		}
+		databaseSample.Status.State = DatabaseReady
+		databaseSample.Status.ObservedGeneration = databaseSample.Generation
+		databaseSample.Status.EncryptionKeyVersion = 1
+		databaseBuilder := resources.NewDatabase(databaseSample)
+		databaseSample.Status.AppliedSchemaOperationQuotasHash = databaseBuilder.GetSchemaOperationQuotasHash()
+		for _, condition := range []string{DatabaseInitializedCondition, DatabaseProvisionedCondition, SchemaInitializedCondition} {
+			meta.SetStatusCondition(&databaseSample.Status.Conditions, metav1.Condition{
+				Type:   condition,
+				Status: metav1.ConditionTrue,
+				Reason: ReasonCompleted,
+			})
+		}
+
+		keySecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "encryption-key",
+				Namespace: databaseSample.Namespace,
+			},
+			Data: map[string][]byte{"key": []byte("rotated")},
+		}
+		keysSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf(resources.EncryptionSecretNameFormat, databaseSample.Name),
+				Namespace: databaseSample.Namespace,
+			},
+			Data: map[string][]byte{resources.EncryptionKeyVersionName(1): []byte("initial")},
+		}
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        databaseSample.Name,
+				Namespace:   databaseSample.Namespace,
+				Annotations: map[string]string{ydbannotations.EncryptionKeyVersion: "1"},
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        databaseSample.Spec.Nodes,
+				ReadyReplicas:   databaseSample.Spec.Nodes,
+				UpdatedReplicas: databaseSample.Spec.Nodes,
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample, keySecret, keysSecret, sts).Build()
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+
+		found := &v1alpha1.Database{}
+		foundSts := &appsv1.StatefulSet{}
+		for i := 0; i < 10 && foundSts.Annotations[ydbannotations.EncryptionKeyVersion] != "2"; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+			Expect(found.Status.ObservedGeneration).To(Equal(found.Generation))
+			Expect(fakeClient.Get(context.Background(), request.NamespacedName, foundSts)).Should(Succeed())
+		}
+		Expect(found.Status.EncryptionKeyVersion).To(Equal(int32(2)))
+		Expect(foundSts.Annotations[ydbannotations.EncryptionKeyVersion]).To(Equal("2"))
+		Expect(meta.IsStatusConditionTrue(found.Status.Conditions, EncryptionKeyRotatedCondition)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Database image pinned by digest", func() {
+	It("reports ignored version once per generation", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StorageReady
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Spec.Image.Name = "cr.yandex/crptqonuodf51kdj7a7d/ydb@sha256:" + strings.Repeat("a", 64)
+		databaseSample.Spec.YDBVersion = "23.3.17"
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample).Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		for i := 0; i < 8; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		close(recorder.Events)
+		ignored := 0
+		for event := range recorder.Events {
+			if strings.Contains(event, "VersionIgnored") {
+				ignored++
+			}
+		}
+		Expect(ignored).To(Equal(1))
+	})
+})
+
+var _ = Describe("Database node set rollout", func() {
+	It("tracks rollout of node set StatefulSet", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Status.State = StorageReady
+		meta.SetStatusCondition(&storageSample.Status.Conditions, metav1.Condition{
+			Type:   StorageInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+
+		databaseSample := testobjects.DefaultDatabase()
+		databaseSample.Spec.NodeSets = []v1alpha1.DatabaseNodeSetSpecInline{{
+			Name:             "compute",
+			DatabaseNodeSpec: v1alpha1.DatabaseNodeSpec{Nodes: databaseSample.Spec.Nodes},
+		}}
+		databaseSample.Status.State = DatabaseReady
+		meta.SetStatusCondition(&databaseSample.Status.Conditions, metav1.Condition{
+			Type:   DatabaseInitializedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonCompleted,
+		})
+
+		nodeSetStatefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      databaseSample.Name + "-compute",
+				Namespace: databaseSample.Namespace,
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        databaseSample.Spec.Nodes,
+				ReadyReplicas:   databaseSample.Spec.Nodes,
+				CurrentRevision: "compute-1",
+				UpdateRevision:  "compute-2",
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, databaseSample, nodeSetStatefulSet).Build()
+		reconciler := &database.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      databaseSample.Name,
+			Namespace: databaseSample.Namespace,
+		}}
+
+		found := &v1alpha1.Database{}
+		for i := 0; i < 10 && meta.FindStatusCondition(found.Status.Conditions, RolloutStuckCondition) == nil; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		}
+
+		condition := meta.FindStatusCondition(found.Status.Conditions, RolloutStuckCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring(nodeSetStatefulSet.Name))
+	})
 })
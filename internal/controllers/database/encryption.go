@@ -0,0 +1,166 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ydbannotations "github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// syncEncryptionKey keeps every version of the encryption key referenced by
+// `spec.encryption.key` in the Secret with encryption keys. When content of
+// the referenced key changes, it is added as a new version: Database nodes
+// are restarted to encrypt with the new version, while previous versions
+// are still available for decryption of existing data.
+func (r *Reconciler) syncEncryptionKey(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	if database.Spec.Encryption == nil || !database.Spec.Encryption.Enabled || database.Spec.Encryption.Key == nil {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step syncEncryptionKey")
+
+	keySecret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      database.Spec.Encryption.Key.Name,
+		Namespace: database.Namespace,
+	}, keySecret); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get encryption key Secret %s: %s", database.Spec.Encryption.Key.Name, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	key, ok := keySecret.Data[database.Spec.Encryption.Key.Key]
+	if !ok {
+		err := fmt.Errorf("key %s not found in Secret %s", database.Spec.Encryption.Key.Key, keySecret.Name)
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get encryption key: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	keysSecret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      fmt.Sprintf(resources.EncryptionSecretNameFormat, database.Name),
+		Namespace: database.Namespace,
+	}, keysSecret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get Secret with encryption keys: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	version := int(database.Status.EncryptionKeyVersion)
+	if version > 0 && bytes.Equal(keysSecret.Data[resources.EncryptionKeyVersionName(version)], key) {
+		log.FromContext(ctx).Info("complete step syncEncryptionKey")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	// Versions are never removed from the Secret, version recorded in
+	// Secret but not in status is overwritten with the same key
+	version++
+	keysSecret.Name = fmt.Sprintf(resources.EncryptionSecretNameFormat, database.Name)
+	keysSecret.Namespace = database.Namespace
+	if _, err = controllerutil.CreateOrUpdate(ctx, r.Client, keysSecret, func() error {
+		if keysSecret.Data == nil {
+			keysSecret.Data = map[string][]byte{}
+		}
+		keysSecret.Data[resources.EncryptionKeyVersionName(version)] = key
+		keysSecret.Type = corev1.SecretTypeOpaque
+		return ctrl.SetControllerReference(database.Unwrap(), keysSecret, r.Scheme)
+	}); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to save encryption key version %d: %s", version, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	// The first version is the key Database was created with
+	if version > 1 {
+		message := fmt.Sprintf("Encryption key is rotated to version %d, previous versions are kept for decryption", version)
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			"KeyRotated",
+			message,
+		)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    EncryptionKeyRotatedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonCompleted,
+			Message: message,
+		})
+	}
+	database.Status.EncryptionKeyVersion = int32(version)
+	return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+}
+
+// isEncryptionKeyApplied reports whether StatefulSets of Database nodes are
+// already rolled out with the key version of status. Remote node sets are
+// rolled out by the operator of their cluster and are not checked.
+func (r *Reconciler) isEncryptionKeyApplied(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, error) {
+	if database.Spec.Encryption == nil || !database.Spec.Encryption.Enabled ||
+		database.Spec.Encryption.Key == nil || database.Status.EncryptionKeyVersion == 0 ||
+		database.Spec.ServerlessResources != nil {
+		return true, nil
+	}
+
+	names := []string{database.Name}
+	if database.Spec.NodeSets != nil {
+		names = []string{}
+		for _, nodeSetSpec := range database.Spec.NodeSets {
+			if nodeSetSpec.Remote == nil {
+				names = append(names, database.Name+"-"+nodeSetSpec.Name)
+			}
+		}
+	}
+
+	version := fmt.Sprint(database.Status.EncryptionKeyVersion)
+	for _, name := range names {
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Name:      name,
+			Namespace: database.Namespace,
+		}, sts); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if sts.Annotations[ydbannotations.EncryptionKeyVersion] != version {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -11,11 +11,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/shutdown"
 )
 
 func (r *Reconciler) setInitPipelineStatus(
@@ -24,10 +27,11 @@ func (r *Reconciler) setInitPipelineStatus(
 ) (bool, ctrl.Result, error) {
 	if database.Status.State == DatabasePreparing {
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:    DatabaseInitializedCondition,
-			Status:  metav1.ConditionFalse,
-			Reason:  ReasonInProgress,
-			Message: "Database has not been initialized yet",
+			Type:               DatabaseInitializedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonInProgress,
+			Message:            "Database has not been initialized yet",
 		})
 		database.Status.State = DatabaseInitializing
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
@@ -35,7 +39,7 @@ func (r *Reconciler) setInitPipelineStatus(
 
 	// This block is special internal logic that skips all Database initialization.
 	if value, ok := database.Annotations[v1alpha1.AnnotationSkipInitialization]; ok && value == v1alpha1.AnnotationValueTrue {
-		r.Log.Info("Database initialization disabled (with annotation), proceed with caution")
+		log.FromContext(ctx).Info("Database initialization disabled (with annotation), proceed with caution")
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeWarning,
@@ -58,10 +62,11 @@ func (r *Reconciler) setInitDatabaseCompleted(
 	message string,
 ) (bool, ctrl.Result, error) {
 	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-		Type:    DatabaseInitializedCondition,
-		Status:  metav1.ConditionTrue,
-		Reason:  ReasonCompleted,
-		Message: message,
+		Type:               DatabaseInitializedCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: database.Generation,
+		Reason:             ReasonCompleted,
+		Message:            message,
 	})
 	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 		Type:    CreateDatabaseOperationCondition,
@@ -98,23 +103,27 @@ func (r *Reconciler) checkCreateDatabaseOperation(
 		return r.updateStatus(ctx, database, DatabaseInitializationRequeueDelay)
 	}
 
-	operation := &cms.Operation{
-		StorageEndpoint: tenant.StorageEndpoint,
-		Domain:          tenant.Domain,
-		ID:              condition.Message,
-	}
-	response, err := operation.GetOperation(ctx, ydbOptions)
+	// Operation is checked once per reconcile, operation which is not
+	// ready after the timeout since submission is reported as a retryable
+	// error and keeps being checked
+	operation, err := cms.PollOperation(
+		ctx,
+		condition.Message,
+		condition.LastTransitionTime.Time,
+		cms.OperationGetter(tenant.StorageEndpoint, tenant.Domain, ydbOptions),
+		r.CMSPollOptions,
+	)
 	if err != nil {
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeWarning,
 			"InitializingFailed",
-			fmt.Sprintf("Failed to check creation operation, operationID %s: %s", operation.ID, err),
+			fmt.Sprintf("Failed to check creation operation, operationID %s: %s", condition.Message, err),
 		)
-		return Stop, ctrl.Result{RequeueAfter: DatabaseInitializationRequeueDelay}, err
+		return Stop, ctrl.Result{RequeueAfter: r.CMSPollOptions.GetInterval()}, err
 	}
 
-	finished, operationID, err := operation.CheckGetOperationResponse(ctx, response)
+	finished, operationID, err := cms.CheckOperationStatus(operation)
 	if err != nil {
 		errMessage := fmt.Sprintf("Error creating tenant %s: %s", tenant.Path, err)
 		r.Recorder.Event(
@@ -123,6 +132,7 @@ func (r *Reconciler) checkCreateDatabaseOperation(
 			"InitializingFailed",
 			errMessage,
 		)
+		database.Status.TenantCreationProgress = v1alpha1.TenantCreationFailed
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 			Type:    CreateDatabaseOperationCondition,
 			Status:  metav1.ConditionFalse,
@@ -139,13 +149,14 @@ func (r *Reconciler) checkCreateDatabaseOperation(
 			string(DatabaseInitializing),
 			fmt.Sprintf("Tenant creation operation is not completed, operationID: %s", operationID),
 		)
+		database.Status.TenantCreationProgress = v1alpha1.TenantCreationAllocating
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 			Type:    CreateDatabaseOperationCondition,
 			Status:  metav1.ConditionUnknown,
 			Reason:  ReasonInProgress,
 			Message: operationID,
 		})
-		return r.updateStatus(ctx, database, DatabaseInitializationRequeueDelay)
+		return r.updateStatus(ctx, database, r.CMSPollOptions.GetInterval())
 	}
 
 	r.Recorder.Event(
@@ -154,6 +165,8 @@ func (r *Reconciler) checkCreateDatabaseOperation(
 		string(DatabaseInitializing),
 		fmt.Sprintf("Tenant %s created", tenant.Path),
 	)
+	database.Status.TenantCreationProgress = v1alpha1.TenantCreationReady
+	database.Status.AppliedSchemaOperationQuotasHash = database.GetSchemaOperationQuotasHash()
 	return r.setInitDatabaseCompleted(ctx, database, "Database initialized successfully")
 }
 
@@ -163,11 +176,15 @@ func (r *Reconciler) initializeTenant(
 ) (bool, ctrl.Result, error) {
 	path := database.GetDatabasePath()
 	var storageUnits []v1alpha1.StorageUnit
+	var computationalUnits []v1alpha1.ComputationalUnit
+	var schemaOperationQuotas []v1alpha1.SchemaOperationQuota
 	var shared bool
 	var sharedDatabasePath string
 	switch {
 	case database.Spec.Resources != nil:
 		storageUnits = database.Spec.Resources.StorageUnits
+		computationalUnits = database.Spec.Resources.ComputationalUnits
+		schemaOperationQuotas = database.Spec.Resources.SchemaOperationQuotas
 		shared = false
 	case database.Spec.SharedResources != nil:
 		storageUnits = database.Spec.SharedResources.StorageUnits
@@ -190,7 +207,7 @@ func (r *Reconciler) initializeTenant(
 						database.Spec.ServerlessResources.SharedDatabaseRef.Namespace,
 					),
 				)
-				return Stop, ctrl.Result{RequeueAfter: SharedDatabaseAwaitRequeueDelay}, nil
+				return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(SharedDatabaseAwaitRequeueDelay)}, nil
 			}
 			r.Recorder.Event(
 				database,
@@ -232,12 +249,16 @@ func (r *Reconciler) initializeTenant(
 	}
 
 	tenant := &cms.Tenant{
-		StorageEndpoint:    database.Spec.StorageEndpoint,
+		StorageEndpoint:    database.Spec.GetOperatorStorageEndpoint(),
 		Domain:             database.Spec.Domain,
 		Path:               path,
 		StorageUnits:       storageUnits,
 		Shared:             shared,
 		SharedDatabasePath: sharedDatabasePath,
+		ParentPath:         database.Spec.ParentPath,
+
+		ComputationalUnits:    computationalUnits,
+		SchemaOperationQuotas: schemaOperationQuotas,
 	}
 
 	creds, err := resources.GetYDBCredentials(ctx, database.Storage, r.Config)
@@ -260,12 +281,58 @@ func (r *Reconciler) initializeTenant(
 		)
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
-	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions)
+	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(database.Storage))
 
 	if meta.IsStatusConditionPresentAndEqual(database.Status.Conditions, CreateDatabaseOperationCondition, metav1.ConditionUnknown) {
 		return r.checkCreateDatabaseOperation(ctx, database, tenant, ydbOpts)
 	}
 
+	if tenant.ParentPath != "" {
+		listResponse, err := tenant.ListDatabases(ctx, ydbOpts)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"InitializingFailed",
+				fmt.Sprintf("Error listing databases to check parent %s: %s", tenant.ParentPath, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DatabaseInitializationRequeueDelay}, err
+		}
+
+		parentExists, err := tenant.CheckParentExists(ctx, listResponse)
+		if err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"InitializingFailed",
+				fmt.Sprintf("Error checking parent %s of tenant %s: %s", tenant.ParentPath, tenant.Path, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DatabaseInitializationRequeueDelay}, err
+		}
+
+		if !parentExists {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"Pending",
+				fmt.Sprintf("Parent database %s of tenant %s does not exist", tenant.ParentPath, tenant.Path),
+			)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:               DatabaseInitializedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: database.Generation,
+				Reason:             ReasonInProgress,
+				Message:            fmt.Sprintf("Waiting for parent database %s", tenant.ParentPath),
+			})
+			return r.updateStatus(ctx, database, DatabaseInitializationRequeueDelay)
+		}
+	}
+
+	// Tenant creation is not interrupted on operator shutdown until its
+	// operation is saved to status and checked on the next reconcile
+	ctx, cancel := shutdown.Critical(ctx)
+	defer cancel()
+
 	response, err := tenant.CreateDatabase(ctx, ydbOpts)
 	if err != nil {
 		r.Recorder.Event(
@@ -295,13 +362,14 @@ func (r *Reconciler) initializeTenant(
 			string(DatabaseInitializing),
 			fmt.Sprintf("Tenant creation operation in progress, operationID: %s", operationID),
 		)
+		database.Status.TenantCreationProgress = v1alpha1.TenantCreationSubmitted
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 			Type:    CreateDatabaseOperationCondition,
 			Status:  metav1.ConditionUnknown,
 			Reason:  ReasonInProgress,
 			Message: operationID,
 		})
-		return r.updateStatus(ctx, database, DatabaseInitializationRequeueDelay)
+		return r.updateStatus(ctx, database, r.CMSPollOptions.GetInterval())
 	}
 	r.Recorder.Event(
 		database,
@@ -309,6 +377,8 @@ func (r *Reconciler) initializeTenant(
 		"Initialized",
 		fmt.Sprintf("Tenant %s created", tenant.Path),
 	)
+	database.Status.TenantCreationProgress = v1alpha1.TenantCreationReady
+	database.Status.AppliedSchemaOperationQuotasHash = database.GetSchemaOperationQuotasHash()
 
 	return r.setInitDatabaseCompleted(ctx, database, "Database initialized successfully")
 }
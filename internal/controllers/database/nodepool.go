@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// checkNodePool checks that Kubernetes nodes labeled with the pool from
+// `spec.nodePool` exist, otherwise pods of Database would never be scheduled
+func (r *Reconciler) checkNodePool(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	if database.Spec.NodePool == nil {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step checkNodePool")
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels{
+		database.Spec.NodePool.GetLabelKey(): database.Spec.NodePool.Name,
+	}); err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to list nodes of pool %s: %s", database.Spec.NodePool.Name, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	if len(nodes.Items) == 0 {
+		message := fmt.Sprintf(
+			"No nodes with label %s=%s found for node pool",
+			database.Spec.NodePool.GetLabelKey(),
+			database.Spec.NodePool.Name,
+		)
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"NodePoolNotFound",
+			message,
+		)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    DatabasePreparedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonFailed,
+			Message: message,
+		})
+		return r.updateStatus(ctx, database, DefaultRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step checkNodePool")
+	return Continue, ctrl.Result{}, nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	ydb "github.com/ydb-platform/ydb-go-sdk/v3"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// syncSchemaOperationQuotas applies changes of `spec.resources.schemaOperationQuotas`
+// to the created tenant with CMS AlterDatabase. Checksum of applied quotas is
+// kept in status, so that CMS is only requested when quotas are changed.
+func (r *Reconciler) syncSchemaOperationQuotas(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	quotasHash := database.GetSchemaOperationQuotasHash()
+	if database.Spec.Resources == nil || database.Status.AppliedSchemaOperationQuotasHash == quotasHash {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step syncSchemaOperationQuotas")
+
+	tenant := &cms.Tenant{
+		StorageEndpoint:       database.Spec.GetOperatorStorageEndpoint(),
+		Domain:                database.Spec.Domain,
+		Path:                  database.GetDatabasePath(),
+		SchemaOperationQuotas: database.Spec.Resources.SchemaOperationQuotas,
+	}
+
+	creds, err := resources.GetYDBCredentials(ctx, database.Storage, r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB credentials: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	tlsOptions, err := resources.GetYDBTLSOption(ctx, database.Storage, r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB TLS options: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(database.Storage))
+
+	response, err := tenant.AlterSchemaOperationQuotas(ctx, ydbOpts)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"QuotasSyncFailed",
+			fmt.Sprintf("Error altering schema operation quotas of tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	finished, operationID, err := tenant.CheckAlterDatabaseResponse(ctx, response)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"QuotasSyncFailed",
+			fmt.Sprintf("Error altering schema operation quotas of tenant %s: %s", tenant.Path, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if !finished {
+		// AlterDatabase is idempotent, not finished operation is requested
+		// again by the next reconcile
+		log.FromContext(ctx).Info("Alter tenant operation in progress", "operationID", operationID)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+	}
+
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		"QuotasSynced",
+		fmt.Sprintf("Schema operation quotas of tenant %s updated", tenant.Path),
+	)
+	database.Status.AppliedSchemaOperationQuotasHash = quotasHash
+	return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+}
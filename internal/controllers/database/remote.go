@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// storageClients caches clients of the clusters of Storage referenced with
+// kubeconfig. Clients are kept per Secret and recreated when kubeconfig in
+// the Secret is changed, e.g. on rotation of credentials. Client is evicted
+// when no Database references its Secret anymore.
+type storageClients struct {
+	mu      sync.Mutex
+	clients map[types.NamespacedName]*storageClient
+}
+
+type storageClient struct {
+	kubeconfigHash string
+	client         client.Client
+	databases      map[types.NamespacedName]struct{}
+}
+
+// release drops database from users of cached clients except the one of
+// keepSecret, clients without users are evicted. Caller holds the lock.
+func (c *storageClients) release(database types.NamespacedName, keepSecret *types.NamespacedName) {
+	for secretName, cached := range c.clients {
+		if keepSecret != nil && secretName == *keepSecret {
+			continue
+		}
+		delete(cached.databases, database)
+		if len(cached.databases) == 0 {
+			delete(c.clients, secretName)
+		}
+	}
+}
+
+// forget is called when Database is deleted or references Storage of the
+// own cluster, so that clients used by it only are evicted
+func (c *storageClients) forget(database types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.release(database, nil)
+}
+
+// getStorageClient returns client of the cluster where referenced Storage is
+// located: own client of Reconciler, unless Storage is referenced with kubeconfig
+func (r *Reconciler) getStorageClient(ctx context.Context, database *resources.DatabaseBuilder) (client.Client, error) {
+	databaseName := types.NamespacedName{Name: database.Name, Namespace: database.Namespace}
+	secretRef := database.Spec.StorageClusterRef.KubeconfigSecretRef
+	if secretRef == nil {
+		r.storageClients.forget(databaseName)
+		return r.Client, nil
+	}
+
+	secret := &corev1.Secret{}
+	secretName := types.NamespacedName{Name: secretRef.Name, Namespace: database.Namespace}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig Secret %s: %w", secretRef.Name, err)
+	}
+	kubeconfig, ok := secret.Data[secretRef.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %s does not exist in kubeconfig Secret %s", secretRef.Key, secretRef.Name)
+	}
+	kubeconfigHash := resources.SHAChecksum(string(kubeconfig))
+
+	r.storageClients.mu.Lock()
+	defer r.storageClients.mu.Unlock()
+
+	r.storageClients.release(databaseName, &secretName)
+
+	cached, ok := r.storageClients.clients[secretName]
+	if ok && cached.kubeconfigHash == kubeconfigHash {
+		cached.databases[databaseName] = struct{}{}
+		return cached.client, nil
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig from Secret %s: %w", secretRef.Name, err)
+	}
+	remoteClient, err := client.New(restConfig, client.Options{Scheme: r.Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client from kubeconfig Secret %s: %w", secretRef.Name, err)
+	}
+
+	// Databases sharing the Secret keep using the client with rotated kubeconfig
+	databases := map[types.NamespacedName]struct{}{}
+	if ok {
+		databases = cached.databases
+	}
+	databases[databaseName] = struct{}{}
+
+	if r.storageClients.clients == nil {
+		r.storageClients.clients = map[types.NamespacedName]*storageClient{}
+	}
+	r.storageClients.clients[secretName] = &storageClient{
+		kubeconfigHash: kubeconfigHash,
+		client:         remoteClient,
+		databases:      databases,
+	}
+	return remoteClient, nil
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// checkRolloutStuck reports rollout of Database StatefulSet which has not
+// progressed for the timeout in RolloutStuck condition, together with the
+// problem of a failing pod if it is found
+func (r *Reconciler) checkRolloutStuck(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	sts *appsv1.StatefulSet,
+) (bool, ctrl.Result, error) {
+	timeout, err := resources.RolloutStuckTimeoutFromAnnotations(database.Annotations)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			RolloutStuckCondition,
+			fmt.Sprintf("Using default rollout timeout: %s", err),
+		)
+	}
+
+	if !resources.SetRolloutStuckCondition(&database.Status.Conditions, sts, timeout) {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	if meta.IsStatusConditionTrue(database.Status.Conditions, RolloutStuckCondition) {
+		condition := meta.FindStatusCondition(database.Status.Conditions, RolloutStuckCondition)
+		message := fmt.Sprintf("%s, no progress for %s", condition.Message, timeout)
+
+		podList := &corev1.PodList{}
+		if err := r.List(ctx, podList,
+			client.InNamespace(database.Namespace),
+			client.MatchingLabels{labels.StatefulsetComponent: sts.Name},
+		); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to list Database pods: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+		if problem, found := resources.FindPodProblem(podList.Items); found {
+			message = fmt.Sprintf("%s: %s", message, problem.Message)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    RolloutStuckCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  problem.Reason,
+				Message: condition.Message,
+			})
+		}
+
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			RolloutStuckCondition,
+			message,
+		)
+	}
+
+	return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+}
+
+// checkNodeSetsRolloutStuck reports stuck rollout of StatefulSets of node
+// sets in RolloutStuck condition of Database. StatefulSets are checked one
+// at a time in order of spec, so that the condition tracks progress of a
+// single rollout. StatefulSets of remote node sets are in another cluster
+// and are not checked.
+func (r *Reconciler) checkNodeSetsRolloutStuck(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	var checked *appsv1.StatefulSet
+	for _, nodeSetSpec := range database.Spec.NodeSets {
+		if nodeSetSpec.Remote != nil {
+			continue
+		}
+
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Name:      database.Name + "-" + nodeSetSpec.Name,
+			Namespace: database.Namespace,
+		}, sts); err != nil {
+			if apierrors.IsNotFound(err) {
+				// StatefulSet of new node set is not created yet
+				continue
+			}
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to get StatefulSet of node set %s: %s", nodeSetSpec.Name, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		checked = sts
+		if resources.IsRollingOut(sts) {
+			break
+		}
+	}
+
+	if checked == nil {
+		return Continue, ctrl.Result{}, nil
+	}
+	return r.checkRolloutStuck(ctx, database, checked)
+}
@@ -0,0 +1,259 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	ydb "github.com/ydb-platform/ydb-go-sdk/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+// applyInitScripts executes `spec.initScripts` on the initialized Database
+// in list order. Checksums of executed scripts are kept in status, so that
+// every script is executed once. Scripts are executed in background and
+// their results are picked up on requeue.
+func (r *Reconciler) applyInitScripts(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	if (len(database.Spec.InitScripts) == 0 && len(database.Status.AppliedInitScripts) == 0) ||
+		database.Status.State == DatabasePaused {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step applyInitScripts")
+
+	scripts, err := r.getInitScripts(ctx, database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			err.Error(),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	hashes := make([]string, len(scripts))
+	for i, script := range scripts {
+		hashes[i] = resources.SHAChecksum(script)
+	}
+
+	// Checksums of removed scripts and of previous versions of scripts
+	// changed in ConfigMap are dropped, so that status lists current ones
+	if applied := keepAppliedInitScripts(database.Status.AppliedInitScripts, hashes); len(applied) != len(database.Status.AppliedInitScripts) {
+		database.Status.AppliedInitScripts = applied
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	applied := make(map[string]struct{}, len(database.Status.AppliedInitScripts))
+	for _, hash := range database.Status.AppliedInitScripts {
+		applied[hash] = struct{}{}
+	}
+
+	for i, script := range scripts {
+		hash := hashes[i]
+		if _, ok := applied[hash]; ok {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s", database.UID, hash)
+		started, finished, err := r.initScripts.Result(key)
+		if !started {
+			endpoint, ydbOpts, err := r.getInitScriptConnection(ctx, database)
+			if err != nil {
+				r.Recorder.Event(
+					database,
+					corev1.EventTypeWarning,
+					"ControllerError",
+					fmt.Sprintf("Failed to execute initScripts[%d]: %s", i, err),
+				)
+				return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+			}
+			logger := log.FromContext(ctx)
+			r.initScripts.Start(key, func(scriptCtx context.Context) error {
+				return scripting.Execute(log.IntoContext(scriptCtx, logger), endpoint, script, ydbOpts)
+			})
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    SchemaInitializedCondition,
+				Status:  metav1.ConditionUnknown,
+				Reason:  ReasonInProgress,
+				Message: fmt.Sprintf("Executing initScripts[%d]", i),
+			})
+			return r.updateStatus(ctx, database, DefaultRequeueDelay)
+		}
+
+		if !finished {
+			log.FromContext(ctx).Info(fmt.Sprintf("initScripts[%d] is still executing", i))
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+		}
+
+		if err != nil {
+			message := fmt.Sprintf("Failed to execute initScripts[%d]: %s", i, err)
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"InitScriptFailed",
+				message,
+			)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    SchemaInitializedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: message,
+			})
+			return r.updateStatus(ctx, database, DefaultRequeueDelay)
+		}
+
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			"InitScriptExecuted",
+			fmt.Sprintf("Executed initScripts[%d] with checksum %s", i, hash),
+		)
+		database.Status.AppliedInitScripts = append(database.Status.AppliedInitScripts, hash)
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    SchemaInitializedCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonInProgress,
+			Message: fmt.Sprintf("Executed initScripts[%d]", i),
+		})
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	if len(scripts) > 0 && !meta.IsStatusConditionTrue(database.Status.Conditions, SchemaInitializedCondition) {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    SchemaInitializedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonCompleted,
+			Message: "All initScripts are executed",
+		})
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step applyInitScripts")
+	return Continue, ctrl.Result{}, nil
+}
+
+// hasPendingInitScripts reports whether applyInitScripts has work to do
+// for Ready Database: scripts in ConfigMaps are changed without change
+// of Database spec.
+func (r *Reconciler) hasPendingInitScripts(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) bool {
+	if len(database.Spec.InitScripts) == 0 {
+		return len(database.Status.AppliedInitScripts) > 0
+	}
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, SchemaInitializedCondition) {
+		return true
+	}
+
+	scripts, err := r.getInitScripts(ctx, database)
+	if err != nil {
+		// Reported by applyInitScripts
+		return true
+	}
+	hashes := make([]string, len(scripts))
+	for i, script := range scripts {
+		hashes[i] = resources.SHAChecksum(script)
+	}
+	applied := keepAppliedInitScripts(database.Status.AppliedInitScripts, hashes)
+	if len(applied) != len(database.Status.AppliedInitScripts) {
+		return true
+	}
+	appliedSet := make(map[string]struct{}, len(applied))
+	for _, hash := range applied {
+		appliedSet[hash] = struct{}{}
+	}
+	for _, hash := range hashes {
+		if _, ok := appliedSet[hash]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// keepAppliedInitScripts returns checksums from applied which belong to
+// current scripts, in order of execution
+func keepAppliedInitScripts(applied, current []string) []string {
+	currentSet := make(map[string]struct{}, len(current))
+	for _, hash := range current {
+		currentSet[hash] = struct{}{}
+	}
+
+	var kept []string
+	for _, hash := range applied {
+		if _, ok := currentSet[hash]; ok {
+			kept = append(kept, hash)
+		}
+	}
+	return kept
+}
+
+func (r *Reconciler) getInitScripts(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) ([]string, error) {
+	scripts := make([]string, 0, len(database.Spec.InitScripts))
+	for i, initScript := range database.Spec.InitScripts {
+		script, err := r.getInitScript(ctx, database, initScript)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get initScripts[%d]: %w", i, err)
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts, nil
+}
+
+func (r *Reconciler) getInitScript(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+	initScript v1alpha1.InitScript,
+) (string, error) {
+	if initScript.ConfigMapKeyRef == nil {
+		return initScript.Query, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      initScript.ConfigMapKeyRef.Name,
+		Namespace: database.Namespace,
+	}, configMap); err != nil {
+		return "", fmt.Errorf("failed to get ConfigMap %s: %w", initScript.ConfigMapKeyRef.Name, err)
+	}
+
+	script, ok := configMap.Data[initScript.ConfigMapKeyRef.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s is not found in ConfigMap %s", initScript.ConfigMapKeyRef.Key, initScript.ConfigMapKeyRef.Name)
+	}
+	return script, nil
+}
+
+func (r *Reconciler) getInitScriptConnection(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (string, ydb.Option, error) {
+	creds, err := resources.GetYDBCredentials(ctx, database.Storage, r.Config)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get YDB credentials: %w", err)
+	}
+	tlsOptions, err := resources.GetYDBTLSOption(ctx, database.Storage, r.Config)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get YDB TLS options: %w", err)
+	}
+	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(database.Storage))
+
+	endpoint := fmt.Sprintf("%s%s", database.Spec.GetOperatorStorageEndpoint(), database.GetDatabasePath())
+	return endpoint, ydbOpts, nil
+}
@@ -5,28 +5,65 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/tracing"
 )
 
 var ErrIncorrectDatabaseResourcesConfiguration = errors.New("incorrect database resources configuration, " +
 	"must be one of: Resources, SharedResources, ServerlessResources")
 
+// Sync reconciles Database, steady-state requeue delays of reconcile steps
+// are replaced with the one from AnnotationRequeueDelay when it is set
 func (r *Reconciler) Sync(ctx context.Context, ydbCr *v1alpha1.Database) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "Database.Sync",
+		tracing.ObjectAttributes(DatabaseKind, ydbCr.Namespace, ydbCr.Name)...)
+	result, err := r.sync(ctx, ydbCr)
+
+	delay, ok, parseErr := requeue.DelayFromAnnotations(ydbCr.Annotations)
+	if parseErr != nil {
+		if r.requeueDelayWarnings.ShouldReport(ydbCr.UID, ydbCr.Annotations[v1alpha1.AnnotationRequeueDelay]) {
+			r.Recorder.Event(
+				ydbCr,
+				corev1.EventTypeWarning,
+				"RequeueDelay",
+				fmt.Sprintf("Using default requeue delays: %s", parseErr),
+			)
+		}
+	} else {
+		r.requeueDelayWarnings.Forget(ydbCr.UID)
+		if ok && result.RequeueAfter > 0 {
+			result.RequeueAfter = requeue.OverrideSteadyDelay(result.RequeueAfter, delay, DefaultRequeueDelay, ReadyRequeueDelay)
+		}
+	}
+
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *Reconciler) sync(ctx context.Context, ydbCr *v1alpha1.Database) (ctrl.Result, error) {
 	var stop bool
 	var result ctrl.Result
 	var err error
@@ -43,6 +80,16 @@ func (r *Reconciler) Sync(ctx context.Context, ydbCr *v1alpha1.Database) (ctrl.R
 		return result, err
 	}
 
+	stop, result, err = r.checkNodePool(ctx, &database)
+	if stop {
+		return result, err
+	}
+
+	stop, result, err = r.syncEncryptionKey(ctx, &database)
+	if stop {
+		return result, err
+	}
+
 	stop, result, err = r.handleResourcesSync(ctx, &database)
 	if stop {
 		return result, err
@@ -53,6 +100,15 @@ func (r *Reconciler) Sync(ctx context.Context, ydbCr *v1alpha1.Database) (ctrl.R
 		return result, err
 	}
 
+	// Owned objects are synced above for Ready Database too: they are
+	// deleted or edited out of band without change of generation
+	if database.Status.State == DatabaseReady && database.Status.ObservedGeneration == database.Generation {
+		stop, result, err = r.checkReadyDatabase(ctx, &database)
+		if stop {
+			return result, err
+		}
+	}
+
 	if !meta.IsStatusConditionTrue(database.Status.Conditions, DatabaseInitializedCondition) {
 		return r.handleTenantCreation(ctx, &database)
 	}
@@ -69,19 +125,137 @@ func (r *Reconciler) Sync(ctx context.Context, ydbCr *v1alpha1.Database) (ctrl.R
 		}
 	}
 
+	if database.Spec.SourceDatabaseRef != nil {
+		stop, result, err = r.handleDatabaseClone(ctx, &database)
+		if stop {
+			return result, err
+		}
+	}
+
 	stop, result, err = r.handlePauseResume(ctx, &database)
 	if stop {
 		return result, err
 	}
 
+	stop, result, err = r.syncSchemaOperationQuotas(ctx, &database)
+	if stop {
+		return result, err
+	}
+
+	stop, result, err = r.applyInitScripts(ctx, &database)
+	if stop {
+		return result, err
+	}
+
+	// Reconcile of the current generation is completed
+	if database.Status.ObservedGeneration != database.Generation {
+		database.Status.ObservedGeneration = database.Generation
+		_, result, err = r.updateStatus(ctx, &database, ReadyRequeueDelay)
+		return result, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// checkReadyDatabase ends reconcile of Ready Database with already
+// reconciled spec once owned objects are synced: it checks that Database
+// nodes are ready and initScripts are executed and comes back after a long
+// delay. Continue means that the full reconcile is required.
+func (r *Reconciler) checkReadyDatabase(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step checkReadyDatabase")
+
+	// Encryption key is rotated in Secret without change of spec, nodes
+	// are restarted with the new key version by the full reconcile
+	applied, err := r.isEncryptionKeyApplied(ctx, database)
+	if err != nil {
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if !applied {
+		log.FromContext(ctx).Info("Encryption key version is changed, running full reconcile")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	// Storage is paused and resumed without change of Database spec, the
+	// transition is reported by the full reconcile
+	if database.Storage != nil &&
+		(database.Storage.Status.State == StoragePaused) != meta.IsStatusConditionTrue(database.Status.Conditions, StoragePausedCondition) {
+		log.FromContext(ctx).Info("Storage pause is changed, running full reconcile")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	// Scripts in ConfigMaps are changed without change of spec
+	if r.hasPendingInitScripts(ctx, database) {
+		log.FromContext(ctx).Info("initScripts are changed, running full reconcile")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	ready, err := r.areDatabaseNodesReady(ctx, database)
+	if err != nil {
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if !ready {
+		log.FromContext(ctx).Info("Database nodes are not ready, running full reconcile")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("complete step checkReadyDatabase")
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(ReadyRequeueDelay)}, nil
+}
+
+func (r *Reconciler) areDatabaseNodesReady(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, error) {
+	if database.Spec.NodeSets == nil {
+		if database.Spec.ServerlessResources != nil {
+			return true, nil
+		}
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Name:      database.Name,
+			Namespace: database.Namespace,
+		}, sts); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return sts.Status.ObservedGeneration == sts.Generation &&
+			sts.Status.ReadyReplicas == database.Spec.Nodes &&
+			database.Status.Replicas == sts.Status.ReadyReplicas &&
+			database.Status.TotalNodes == sts.Status.Replicas, nil
+	}
+
+	for _, nodeSetSpec := range database.Spec.NodeSets {
+		nodeSetName := database.Name + "-" + nodeSetSpec.Name
+		key := types.NamespacedName{Name: nodeSetName, Namespace: database.Namespace}
+
+		var nodeSetConditions []metav1.Condition
+		if nodeSetSpec.Remote != nil {
+			nodeSet := &v1alpha1.RemoteDatabaseNodeSet{}
+			if err := r.Get(ctx, key, nodeSet); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			nodeSetConditions = nodeSet.Status.Conditions
+		} else {
+			nodeSet := &v1alpha1.DatabaseNodeSet{}
+			if err := r.Get(ctx, key, nodeSet); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			nodeSetConditions = nodeSet.Status.Conditions
+		}
+		if !meta.IsStatusConditionTrue(nodeSetConditions, NodeSetProvisionedCondition) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (r *Reconciler) setInitialStatus(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step setInitialStatus")
+	log.FromContext(ctx).Info("running step setInitialStatus")
 	if database.Status.Conditions == nil {
 		database.Status.Conditions = []metav1.Condition{}
 
@@ -104,12 +278,12 @@ func (r *Reconciler) setInitialStatus(
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step setInitialStatus")
+	log.FromContext(ctx).Info("complete step setInitialStatus")
 	return Continue, ctrl.Result{}, nil
 }
 
 func (r *Reconciler) waitForClusterResources(ctx context.Context, database *resources.DatabaseBuilder) (bool, ctrl.Result, error) {
-	r.Log.Info("running step waitForClusterResources")
+	log.FromContext(ctx).Info("running step waitForClusterResources")
 
 	if database.Status.State == DatabasePending {
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
@@ -122,11 +296,26 @@ func (r *Reconciler) waitForClusterResources(ctx context.Context, database *reso
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
+	storageClient, err := r.getStorageClient(ctx, database)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"Pending",
+			fmt.Sprintf("Failed to get client of Storage cluster: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: StorageAwaitRequeueDelay}, err
+	}
+
 	storage := &v1alpha1.Storage{}
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      database.Spec.StorageClusterRef.Name,
-		Namespace: database.Spec.StorageClusterRef.Namespace,
-	}, storage)
+	// Transient errors are retried in place, so that Storage is not
+	// awaited for the whole requeue delay because of a blip of API server
+	err = retry.OnError(requeue.TransientRetryBackoff, requeue.IsTransient, func() error {
+		return storageClient.Get(ctx, types.NamespacedName{
+			Name:      database.Spec.StorageClusterRef.Name,
+			Namespace: database.Spec.StorageClusterRef.Namespace,
+		}, storage)
+	})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			r.Recorder.Event(
@@ -139,7 +328,29 @@ func (r *Reconciler) waitForClusterResources(ctx context.Context, database *reso
 					database.Spec.StorageClusterRef.Namespace,
 				),
 			)
-			return Stop, ctrl.Result{RequeueAfter: StorageAwaitRequeueDelay}, nil
+			return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(StorageAwaitRequeueDelay)}, nil
+		}
+		if apierrors.IsForbidden(err) {
+			message := fmt.Sprintf(
+				"Operator has no permission to get Storage (%s/%s), check RBAC in namespace %s: %s",
+				database.Spec.StorageClusterRef.Namespace,
+				database.Spec.StorageClusterRef.Name,
+				database.Spec.StorageClusterRef.Namespace,
+				err,
+			)
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"RBACInsufficient",
+				message,
+			)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    DatabasePreparedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: message,
+			})
+			return r.updateStatus(ctx, database, RBACInsufficientRequeueDelay)
 		}
 		r.Recorder.Event(
 			database,
@@ -152,36 +363,130 @@ func (r *Reconciler) waitForClusterResources(ctx context.Context, database *reso
 				err,
 			),
 		)
+		if requeue.IsTransient(err) {
+			return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(TransientErrorRequeueDelay)}, nil
+		}
 		return Stop, ctrl.Result{RequeueAfter: StorageAwaitRequeueDelay}, err
 	}
 
+	// Database is often created together with Storage, so waiting for
+	// Storage bootstrap is expected and is retried with backoff
 	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
-		r.Recorder.Event(
-			database,
-			corev1.EventTypeWarning,
-			"Pending",
-			fmt.Sprintf(
-				"Referenced storage cluster (%s, %s) is not initialized",
-				database.Spec.StorageClusterRef.Name,
-				database.Spec.StorageClusterRef.Namespace,
-			),
+		message := fmt.Sprintf(
+			"Referenced storage cluster (%s, %s) is not initialized",
+			database.Spec.StorageClusterRef.Name,
+			database.Spec.StorageClusterRef.Namespace,
 		)
+		// Waiting is reported once per transition, not on every requeue
+		if !meta.IsStatusConditionTrue(database.Status.Conditions, WaitingForStorageCondition) {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeNormal,
+				WaitingForStorageCondition,
+				message,
+			)
+		}
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    WaitingForStorageCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonInProgress,
+			Message: message,
+		})
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:   DatabasePreparedCondition,
-			Status: metav1.ConditionFalse,
-			Reason: ReasonInProgress,
-			Message: fmt.Sprintf(
-				"Referenced storage cluster (%s, %s) is not initialized",
+			Type:    DatabasePreparedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInProgress,
+			Message: message,
+		})
+		waitingSince := meta.FindStatusCondition(database.Status.Conditions, WaitingForStorageCondition).LastTransitionTime
+		return r.updateStatus(ctx, database, requeue.Backoff(
+			time.Since(waitingSince.Time),
+			StatusUpdateRequeueDelay,
+			StorageAwaitRequeueDelay,
+		))
+	}
+
+	if meta.IsStatusConditionTrue(database.Status.Conditions, WaitingForStorageCondition) {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    WaitingForStorageCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonCompleted,
+			Message: "Referenced storage cluster is initialized",
+		})
+	}
+
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, DatabaseInitializedCondition) &&
+		!meta.IsStatusConditionPresentAndEqual(database.Status.Conditions, CreateDatabaseOperationCondition, metav1.ConditionUnknown) &&
+		storage.Status.TenantCapacity != nil && database.Spec.Resources != nil {
+		var storageUnits uint64
+		for _, unit := range database.Spec.Resources.StorageUnits {
+			storageUnits += unit.Count
+		}
+		if available := uint64(storage.Status.TenantCapacity.Available); storageUnits > available {
+			message := fmt.Sprintf(
+				"Referenced storage cluster (%s, %s) has no capacity for the tenant: %d storage units requested, %d available",
 				database.Spec.StorageClusterRef.Name,
 				database.Spec.StorageClusterRef.Namespace,
-			),
-		})
-		return r.updateStatus(ctx, database, StorageAwaitRequeueDelay)
+				storageUnits,
+				available,
+			)
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"NoCapacity",
+				message,
+			)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    DatabasePreparedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: message,
+			})
+			return r.updateStatus(ctx, database, StorageAwaitRequeueDelay)
+		}
+	}
+
+	// Pause of Storage is reported once per transition, the condition is
+	// kept while Storage is paused
+	storagePaused := storage.Status.State == StoragePaused
+	if storagePaused != meta.IsStatusConditionTrue(database.Status.Conditions, StoragePausedCondition) {
+		if storagePaused {
+			message := fmt.Sprintf(
+				"Referenced storage cluster (%s, %s) is paused",
+				database.Spec.StorageClusterRef.Name,
+				database.Spec.StorageClusterRef.Namespace,
+			)
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"StoragePaused",
+				message,
+			)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:    StoragePausedCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonNotRequired,
+				Message: message,
+			})
+		} else {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeNormal,
+				"StorageResumed",
+				fmt.Sprintf(
+					"Referenced storage cluster (%s, %s) is resumed",
+					database.Spec.StorageClusterRef.Name,
+					database.Spec.StorageClusterRef.Namespace,
+				),
+			)
+			meta.RemoveStatusCondition(&database.Status.Conditions, StoragePausedCondition)
+		}
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
 	database.Storage = storage
 
-	r.Log.Info("complete step waitForClusterResources")
+	log.FromContext(ctx).Info("complete step waitForClusterResources")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
@@ -189,19 +494,24 @@ func (r *Reconciler) waitForNodeSetsToProvisioned(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step waitForNodeSetsToProvisioned")
+	log.FromContext(ctx).Info("running step waitForNodeSetsToProvisioned")
 
 	if database.Status.State == DatabaseInitializing {
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:    DatabaseProvisionedCondition,
-			Status:  metav1.ConditionUnknown,
-			Reason:  ReasonInProgress,
-			Message: "Waiting for NodeSets conditions to be Provisioned",
+			Type:               DatabaseProvisionedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonInProgress,
+			Message:            "Waiting for NodeSets conditions to be Provisioned",
 		})
 		database.Status.State = DatabaseProvisioning
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
+	if stop, result, err := r.checkNodeSetsRolloutStuck(ctx, database); stop {
+		return stop, result, err
+	}
+
 	for _, nodeSetSpec := range database.Spec.NodeSets {
 		var nodeSetObject client.Object
 		var nodeSetKind string
@@ -255,9 +565,10 @@ func (r *Reconciler) waitForNodeSetsToProvisioned(
 				),
 			)
 			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-				Type:   DatabaseProvisionedCondition,
-				Status: metav1.ConditionFalse,
-				Reason: ReasonInProgress,
+				Type:               DatabaseProvisionedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: database.Generation,
+				Reason:             ReasonInProgress,
 				Message: fmt.Sprintf(
 					"Waiting %s with name %s for condition NodeSetProvisioned to be True",
 					nodeSetKind,
@@ -270,15 +581,16 @@ func (r *Reconciler) waitForNodeSetsToProvisioned(
 
 	if !meta.IsStatusConditionTrue(database.Status.Conditions, DatabaseProvisionedCondition) {
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:    DatabaseProvisionedCondition,
-			Status:  metav1.ConditionTrue,
-			Reason:  ReasonCompleted,
-			Message: "Successfully scaled to desired number of nodes",
+			Type:               DatabaseProvisionedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonCompleted,
+			Message:            "Successfully scaled to desired number of nodes",
 		})
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step waitForNodeSetsToProvisioned")
+	log.FromContext(ctx).Info("complete step waitForNodeSetsToProvisioned")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
@@ -286,14 +598,15 @@ func (r *Reconciler) waitForStatefulSetToScale(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step waitForStatefulSetToScale")
+	log.FromContext(ctx).Info("running step waitForStatefulSetToScale")
 
 	if database.Status.State == DatabaseInitializing {
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:    DatabaseProvisionedCondition,
-			Status:  metav1.ConditionUnknown,
-			Reason:  ReasonInProgress,
-			Message: fmt.Sprintf("Waiting for scale to desired nodes: %d", database.Spec.Nodes),
+			Type:               DatabaseProvisionedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonInProgress,
+			Message:            fmt.Sprintf("Waiting for scale to desired nodes: %d", database.Spec.Nodes),
 		})
 		database.Status.State = DatabaseProvisioning
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
@@ -327,33 +640,96 @@ func (r *Reconciler) waitForStatefulSetToScale(
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
 
-	if foundStatefulSet.Status.ReadyReplicas != database.Spec.Nodes {
+	// Paused Database is scaled to zero, tenant is kept in CMS
+	desiredNodes := database.Spec.Nodes
+	if database.Spec.Pause {
+		desiredNodes = 0
+	}
+
+	// Reported by the scale subresource of Database to autoscaler
+	selector := fmt.Sprintf("%s=%s", labels.StatefulsetComponent, database.Name)
+	scaleStatusChanged := database.Status.Replicas != foundStatefulSet.Status.ReadyReplicas ||
+		database.Status.Selector != selector ||
+		database.Status.ReadyNodes != foundStatefulSet.Status.ReadyReplicas ||
+		database.Status.TotalNodes != foundStatefulSet.Status.Replicas
+	database.Status.Replicas = foundStatefulSet.Status.ReadyReplicas
+	database.Status.Selector = selector
+	database.Status.ReadyNodes = foundStatefulSet.Status.ReadyReplicas
+	database.Status.TotalNodes = foundStatefulSet.Status.Replicas
+
+	if stop, result, err := r.checkRolloutStuck(ctx, database, foundStatefulSet); stop {
+		return stop, result, err
+	}
+
+	if foundStatefulSet.Status.ReadyReplicas != desiredNodes {
+		podList := &corev1.PodList{}
+		if err := r.List(ctx, podList,
+			client.InNamespace(database.Namespace),
+			client.MatchingLabels{labels.StatefulsetComponent: database.Name},
+		); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to list Database pods: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		if problem, found := resources.FindPodProblem(podList.Items); found {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				problem.Reason,
+				problem.Message,
+			)
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:               DatabaseProvisionedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: database.Generation,
+				Reason:             problem.Reason,
+				Message:            problem.Message,
+			})
+			return r.updateStatus(ctx, database, DefaultRequeueDelay)
+		}
+
+		// Pods may already be scheduled while not ready yet, scaling is
+		// detected by Replicas and readiness is gated by ReadyReplicas only
+		eventMessage := fmt.Sprintf("Waiting for number of running pods to match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, desiredNodes)
+		conditionMessage := fmt.Sprintf("Number of running pods does not match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, desiredNodes)
+		if foundStatefulSet.Status.Replicas == desiredNodes {
+			eventMessage = fmt.Sprintf("Waiting for pods to become ready: %d/%d", foundStatefulSet.Status.ReadyReplicas, desiredNodes)
+			conditionMessage = fmt.Sprintf("Number of ready pods does not match expected: %d/%d", foundStatefulSet.Status.ReadyReplicas, desiredNodes)
+		}
+
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeNormal,
 			string(DatabaseProvisioning),
-			fmt.Sprintf("Waiting for number of running pods to match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, database.Spec.Nodes),
+			eventMessage,
 		)
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:    DatabaseProvisionedCondition,
-			Status:  metav1.ConditionFalse,
-			Reason:  ReasonInProgress,
-			Message: fmt.Sprintf("Number of running pods does not match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, database.Spec.Nodes),
+			Type:               DatabaseProvisionedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonInProgress,
+			Message:            conditionMessage,
 		})
 		return r.updateStatus(ctx, database, DefaultRequeueDelay)
 	}
 
-	if !meta.IsStatusConditionTrue(database.Status.Conditions, DatabaseProvisionedCondition) {
+	if !meta.IsStatusConditionTrue(database.Status.Conditions, DatabaseProvisionedCondition) || scaleStatusChanged {
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-			Type:    DatabaseProvisionedCondition,
-			Status:  metav1.ConditionTrue,
-			Reason:  ReasonCompleted,
-			Message: fmt.Sprintf("Successfully scaled to desired number of nodes: %d", database.Spec.Nodes),
+			Type:               DatabaseProvisionedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonCompleted,
+			Message:            fmt.Sprintf("Successfully scaled to desired number of nodes: %d", desiredNodes),
 		})
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step waitForStatefulSetToScale")
+	log.FromContext(ctx).Info("complete step waitForStatefulSetToScale")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
@@ -379,10 +755,10 @@ func (r *Reconciler) handleResourcesSync(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step handleResourcesSync")
+	log.FromContext(ctx).Info("running step handleResourcesSync")
 
 	if !database.Spec.OperatorSync {
-		r.Log.Info("`operatorSync: false` is set, no further steps will be run")
+		log.FromContext(ctx).Info("`operatorSync: false` is set, no further steps will be run")
 		r.Recorder.Event(
 			database,
 			corev1.EventTypeNormal,
@@ -392,18 +768,33 @@ func (r *Reconciler) handleResourcesSync(
 		return Stop, ctrl.Result{}, nil
 	}
 
+	if stop, result, err := r.checkImageVersion(ctx, database); stop {
+		return stop, result, err
+	}
+
+	if stop, result, err := r.checkConfigDrift(ctx, database); stop {
+		return stop, result, err
+	}
+
+	syncErrors := resources.SyncErrors{}
+	managedResources := []v1alpha1.ManagedResource{}
 	for _, builder := range database.GetResourceBuilders(r.Config) {
 		newResource := builder.Placeholder(database)
+		managedResource := resources.ManagedResourceOf(newResource)
 
 		result, err := resources.CreateOrUpdateOrMaybeIgnore(ctx, r.Client, newResource, func() error {
 			var err error
 
 			err = builder.Build(newResource)
 			if err != nil {
+				reason := "ProvisioningFailed"
+				if errors.Is(err, resources.ErrConfigTooLarge) {
+					reason = "ConfigTooLarge"
+				}
 				r.Recorder.Event(
 					database,
 					corev1.EventTypeWarning,
-					"ProvisioningFailed",
+					reason,
 					fmt.Sprintf("Failed building resources: %s", err),
 				)
 				return err
@@ -436,13 +827,8 @@ func (r *Reconciler) handleResourcesSync(
 				"ProvisioningFailed",
 				eventMessage+fmt.Sprintf(", failed to sync, error: %s", err),
 			)
-			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
-				Type:    DatabasePreparedCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  ReasonInProgress,
-				Message: fmt.Sprintf("Failed to sync resources for generation %d", database.Generation),
-			})
-			return r.updateStatus(ctx, database, DefaultRequeueDelay)
+			syncErrors.Add(newResource, err)
+			managedResource.SyncError = err.Error()
 		} else if result == controllerutil.OperationResultCreated || result == controllerutil.OperationResultUpdated {
 			r.Recorder.Event(
 				database,
@@ -451,18 +837,252 @@ func (r *Reconciler) handleResourcesSync(
 				eventMessage+fmt.Sprintf(", changed, result: %s", result),
 			)
 		}
+		managedResources = append(managedResources, managedResource)
 	}
 
-	r.Log.Info("complete step handleResourcesSync")
+	if err := syncErrors.Aggregate(); err != nil {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:    DatabasePreparedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInProgress,
+			Message: fmt.Sprintf("Failed to sync resources for generation %d: %s", database.Generation, err),
+		})
+		log.FromContext(ctx).Error(err, "Failed to sync resources")
+		database.Status.ManagedResources = managedResources
+		return r.updateStatus(ctx, database, DefaultRequeueDelay)
+	}
+
+	if stop, result, err := r.deleteRemovedAdditionalResources(ctx, database); stop {
+		return stop, result, err
+	}
+
+	if !equality.Semantic.DeepEqual(database.Status.ManagedResources, managedResources) {
+		database.Status.ManagedResources = managedResources
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	if database.Spec.Autoscaling == nil {
+		if err := r.deleteAutoscaler(ctx, database); err != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ProvisioningFailed",
+				fmt.Sprintf("Failed to delete HorizontalPodAutoscaler: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+	}
+
+	configHash := resources.SHAChecksum(database.GetConfiguration())
+	if database.Status.ObservedConfigHash != configHash {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			"ConfigurationChanged",
+			fmt.Sprintf("Rendered configuration checksum changed to %s", configHash),
+		)
+		database.Status.ObservedConfigHash = configHash
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	if database.Spec.HasOwnConfiguration() && !meta.IsStatusConditionTrue(database.Status.Conditions, ConfigInSyncCondition) {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               ConfigInSyncCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonCompleted,
+			Message:            "Configuration ConfigMap matches spec",
+		})
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	var postgresConnectionString string
+	if database.Spec.IsPostgresEnabled() {
+		postgresConnectionString = database.GetPostgresConnectionString()
+	}
+	if database.Status.PostgresConnectionString != postgresConnectionString {
+		database.Status.PostgresConnectionString = postgresConnectionString
+		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step handleResourcesSync")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
+// deleteRemovedAdditionalResources deletes objects which were created from
+// `spec.additionalResources` and are not supplied there anymore
+func (r *Reconciler) deleteRemovedAdditionalResources(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	removed, err := resources.GetRemovedAdditionalResources(ctx, r.Client, database.Unwrap(), database.Spec.AdditionalResources)
+	if err != nil {
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeWarning,
+			"ProvisioningFailed",
+			fmt.Sprintf("Failed to list additional resources: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	for _, obj := range removed {
+		eventMessage := fmt.Sprintf(
+			"Resource: %s, Namespace: %s, Name: %s",
+			obj.GetObjectKind().GroupVersionKind().Kind,
+			obj.GetNamespace(),
+			obj.GetName(),
+		)
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"ProvisioningFailed",
+				eventMessage+fmt.Sprintf(", failed to delete, error: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+		r.Recorder.Event(
+			database,
+			corev1.EventTypeNormal,
+			"Syncing",
+			eventMessage+", deleted",
+		)
+	}
+
+	return Continue, ctrl.Result{}, nil
+}
+
+// checkImageVersion refuses to sync resources, and so to roll out the image,
+// while YDB version of the image is older than supported by the operator
+func (r *Reconciler) checkImageVersion(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	// Reported once per generation, not on every reconcile
+	if database.Spec.Image.HasDigest() && database.Spec.YDBVersion != "" {
+		if r.versionIgnoredWarnings.ShouldReport(database.UID, strconv.FormatInt(database.Generation, 10)) {
+			r.Recorder.Event(
+				database,
+				corev1.EventTypeWarning,
+				"VersionIgnored",
+				fmt.Sprintf("Image %s is pinned by digest, version %s is ignored", database.Spec.Image.Name, database.Spec.YDBVersion),
+			)
+		}
+	} else {
+		r.versionIgnoredWarnings.Forget(database.UID)
+	}
+
+	err := v1alpha1.CheckYDBVersion(database.Spec.Image, database.Spec.YDBVersion)
+	if err == nil {
+		if meta.FindStatusCondition(database.Status.Conditions, UnsupportedVersionCondition) != nil {
+			meta.RemoveStatusCondition(&database.Status.Conditions, UnsupportedVersionCondition)
+			return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+		}
+		return Continue, ctrl.Result{}, nil
+	}
+
+	condition := meta.FindStatusCondition(database.Status.Conditions, UnsupportedVersionCondition)
+	if condition != nil && condition.ObservedGeneration == database.Generation {
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+	}
+
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeWarning,
+		UnsupportedVersionCondition,
+		fmt.Sprintf("Image is not rolled out: %s", err),
+	)
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:               UnsupportedVersionCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: database.Generation,
+		Reason:             ReasonFailed,
+		Message:            err.Error(),
+	})
+	return r.updateStatus(ctx, database, DefaultRequeueDelay)
+}
+
+// checkConfigDrift detects out-of-band edits of own configuration ConfigMap
+// of Database. Drifted ConfigMap is reported and then re-applied by resources sync.
+func (r *Reconciler) checkConfigDrift(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) (bool, ctrl.Result, error) {
+	if !database.Spec.HasOwnConfiguration() {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      database.Name,
+		Namespace: database.Namespace,
+	}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return Continue, ctrl.Result{}, nil
+		}
+		log.FromContext(ctx).Error(err, "Failed to get configuration ConfigMap")
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	if !resources.IsConfigMapDrifted(configMap) ||
+		meta.IsStatusConditionFalse(database.Status.Conditions, ConfigInSyncCondition) {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeWarning,
+		ReasonConfigDrift,
+		fmt.Sprintf("ConfigMap %s was changed out of band, configuration of spec is re-applied", configMap.Name),
+	)
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:               ConfigInSyncCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: database.Generation,
+		Reason:             ReasonConfigDrift,
+		Message:            fmt.Sprintf("ConfigMap %s does not match configuration of spec", configMap.Name),
+	})
+	return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
+}
+
+// deleteAutoscaler removes HorizontalPodAutoscaler which is left
+// after `spec.autoscaling` is removed from Database.
+func (r *Reconciler) deleteAutoscaler(
+	ctx context.Context,
+	database *resources.DatabaseBuilder,
+) error {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      database.Name,
+		Namespace: database.Namespace,
+	}, hpa)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(hpa, database.Unwrap()) {
+		return nil
+	}
+
+	if err := r.Delete(ctx, hpa); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.Recorder.Event(
+		database,
+		corev1.EventTypeNormal,
+		"Provisioning",
+		fmt.Sprintf("HorizontalPodAutoscaler %s deleted", hpa.Name),
+	)
+	return nil
+}
+
 func (r *Reconciler) updateStatus(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 	requeueAfter time.Duration,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running updateStatus handler")
+	log.FromContext(ctx).Info("running updateStatus handler")
 
 	if meta.IsStatusConditionFalse(database.Status.Conditions, DatabasePreparedCondition) ||
 		meta.IsStatusConditionFalse(database.Status.Conditions, DatabaseInitializedCondition) ||
@@ -482,6 +1102,8 @@ func (r *Reconciler) updateStatus(
 		}
 	}
 
+	setReadyCondition(database)
+
 	databaseCr := &v1alpha1.Database{}
 	err := r.Get(ctx, types.NamespacedName{
 		Namespace: database.Namespace,
@@ -500,7 +1122,22 @@ func (r *Reconciler) updateStatus(
 	oldStatus := databaseCr.Status.State
 	databaseCr.Status.State = database.Status.State
 	databaseCr.Status.Conditions = database.Status.Conditions
+	databaseCr.Status.ObservedGeneration = database.Status.ObservedGeneration
+	databaseCr.Status.ObservedConfigHash = database.Status.ObservedConfigHash
+	databaseCr.Status.ManagedResources = database.Status.ManagedResources
+	databaseCr.Status.Replicas = database.Status.Replicas
+	databaseCr.Status.Selector = database.Status.Selector
+	databaseCr.Status.ReadyNodes = database.Status.ReadyNodes
+	databaseCr.Status.TotalNodes = database.Status.TotalNodes
+	databaseCr.Status.PostgresConnectionString = database.Status.PostgresConnectionString
+	databaseCr.Status.TenantCreationProgress = database.Status.TenantCreationProgress
+	databaseCr.Status.AppliedSchemaOperationQuotasHash = database.Status.AppliedSchemaOperationQuotasHash
+	databaseCr.Status.EncryptionKeyVersion = database.Status.EncryptionKeyVersion
+	databaseCr.Status.AppliedInitScripts = database.Status.AppliedInitScripts
 	err = r.Status().Update(ctx, databaseCr)
+	if result, conflict := requeue.OnConflict(log.FromContext(ctx), err); conflict {
+		return Stop, result, nil
+	}
 	if err != nil {
 		r.Recorder.Event(
 			database,
@@ -510,6 +1147,8 @@ func (r *Reconciler) updateStatus(
 		)
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
+	// Readiness is reported as soon as it is changed, not on the next reconcile
+	metrics.SetClusterReady(DatabaseKind, databaseCr.Namespace, databaseCr.Name, meta.IsStatusConditionTrue(databaseCr.Status.Conditions, DatabaseReadyCondition))
 	if oldStatus != database.Status.State {
 		r.Recorder.Event(
 			database,
@@ -519,15 +1158,15 @@ func (r *Reconciler) updateStatus(
 		)
 	}
 
-	r.Log.Info("complete updateStatus handler")
-	return Stop, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	log.FromContext(ctx).Info("complete updateStatus handler")
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(requeueAfter)}, nil
 }
 
 func (r *Reconciler) syncNodeSetSpecInline(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step syncNodeSetSpecInline")
+	log.FromContext(ctx).Info("running step syncNodeSetSpecInline")
 
 	databaseNodeSets := &v1alpha1.DatabaseNodeSetList{}
 	if err := r.List(ctx, databaseNodeSets,
@@ -640,15 +1279,66 @@ func (r *Reconciler) syncNodeSetSpecInline(
 		return r.updateStatus(ctx, database, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step syncNodeSetSpecInline")
+	log.FromContext(ctx).Info("complete step syncNodeSetSpecInline")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
+// setReadyCondition sets the aggregated Ready condition: Database is Ready
+// when tenant is initialized and all pods are scaled and running.
+func setReadyCondition(database *resources.DatabaseBuilder) {
+	if database.Spec.Pause {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               ReadyCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonNotRequired,
+			Message:            "Database is paused",
+		})
+		return
+	}
+
+	for _, conditionType := range []string{
+		DatabaseInitializedCondition,
+		DatabaseProvisionedCondition,
+		DatabaseReadyCondition,
+	} {
+		if !meta.IsStatusConditionTrue(database.Status.Conditions, conditionType) {
+			meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+				Type:               ReadyCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: database.Generation,
+				Reason:             ReasonInProgress,
+				Message:            fmt.Sprintf("Waiting for condition %s", conditionType),
+			})
+			return
+		}
+	}
+
+	if database.Status.ObservedGeneration != database.Generation {
+		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+			Type:               ReadyCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: database.Generation,
+			Reason:             ReasonInProgress,
+			Message:            fmt.Sprintf("Waiting for generation %d to be reconciled", database.Generation),
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: database.Generation,
+		Reason:             ReasonCompleted,
+		Message:            "Database is ready",
+	})
+}
+
 func (r *Reconciler) handlePauseResume(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step handlePauseResume")
+	log.FromContext(ctx).Info("running step handlePauseResume")
 
 	if database.Status.State == DatabaseProvisioning {
 		if database.Spec.Pause {
@@ -670,7 +1360,7 @@ func (r *Reconciler) handlePauseResume(
 	}
 
 	if database.Status.State == DatabaseReady && database.Spec.Pause {
-		r.Log.Info("`pause: true` was noticed, moving Database to state `Paused`")
+		log.FromContext(ctx).Info("`pause: true` was noticed, moving Database to state `Paused`")
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 			Type:    DatabaseReadyCondition,
 			Status:  metav1.ConditionFalse,
@@ -682,7 +1372,7 @@ func (r *Reconciler) handlePauseResume(
 	}
 
 	if database.Status.State == DatabasePaused && !database.Spec.Pause {
-		r.Log.Info("`pause: false` was noticed, moving Database to state `Ready`")
+		log.FromContext(ctx).Info("`pause: false` was noticed, moving Database to state `Ready`")
 		meta.SetStatusCondition(&database.Status.Conditions, metav1.Condition{
 			Type:    DatabasePausedCondition,
 			Status:  metav1.ConditionFalse,
@@ -713,7 +1403,7 @@ func (r *Reconciler) handlePauseResume(
 		}
 	}
 
-	r.Log.Info("complete step handlePauseResume")
+	log.FromContext(ctx).Info("complete step handlePauseResume")
 	return Continue, ctrl.Result{}, nil
 }
 
@@ -721,7 +1411,9 @@ func (r *Reconciler) handleTenantCreation(
 	ctx context.Context,
 	database *resources.DatabaseBuilder,
 ) (ctrl.Result, error) {
-	r.Log.Info("running step handleTenantCreation")
+	log.FromContext(ctx).Info("running step handleTenantCreation")
+	ctx, span := tracing.Start(ctx, "handleTenantCreation")
+	defer span.End()
 
 	stop, result, err := r.setInitPipelineStatus(ctx, database)
 	if stop {
@@ -733,6 +1425,6 @@ func (r *Reconciler) handleTenantCreation(
 		return result, err
 	}
 
-	r.Log.Info("complete step handleTenantCreation")
+	log.FromContext(ctx).Info("complete step handleTenantCreation")
 	return ctrl.Result{}, nil
 }
@@ -18,6 +18,7 @@ import (
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
 
@@ -298,7 +299,11 @@ func (r *Reconciler) updateStatus(
 	oldStatus := crDatabaseNodeSet.Status.State
 	crDatabaseNodeSet.Status.State = databaseNodeSet.Status.State
 	crDatabaseNodeSet.Status.Conditions = databaseNodeSet.Status.Conditions
-	if err = r.Status().Update(ctx, crDatabaseNodeSet); err != nil {
+	err = r.Status().Update(ctx, crDatabaseNodeSet)
+	if result, conflict := requeue.OnConflict(r.Log, err); conflict {
+		return Stop, result, nil
+	}
+	if err != nil {
 		r.Recorder.Event(
 			databaseNodeSet,
 			corev1.EventTypeWarning,
@@ -317,7 +322,7 @@ func (r *Reconciler) updateStatus(
 	}
 
 	r.Log.Info("complete updateStatus handler")
-	return Stop, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(requeueAfter)}, nil
 }
 
 func shouldIgnoreDatabaseNodeSetChange(databaseNodeSet *resources.DatabaseNodeSetResource) resources.IgnoreChangesFunction {
@@ -19,6 +19,7 @@ import (
 	ydbannotations "github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
 	ydblabels "github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
 
@@ -382,7 +383,7 @@ func (r *Reconciler) updateStatusRemoteObjects(
 		"Status for remote resources updated",
 	)
 
-	return Stop, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(requeueAfter)}, nil
 }
 
 func (r *Reconciler) getAnotherStorageNodeSets(
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/shutdown"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/tracing"
+)
+
+var errNoReadyStoragePod = errors.New("no ready Storage pod to execute command in")
+
+// applyAdditionalBSConfig applies `spec.additionalBSConfig` requests to the
+// initialized blobstorage. Checksums of applied requests are kept in status,
+// so that every request is applied only once.
+func (r *Reconciler) applyAdditionalBSConfig(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	if len(storage.Spec.AdditionalBSConfig) == 0 {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step applyAdditionalBSConfig")
+
+	applied := make(map[string]struct{}, len(storage.Status.AppliedBSConfig))
+	for _, hash := range storage.Status.AppliedBSConfig {
+		applied[hash] = struct{}{}
+	}
+
+	for i, command := range storage.Spec.AdditionalBSConfig {
+		proto, err := r.getBSConfigProto(ctx, storage, command)
+		if err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to get additionalBSConfig[%d]: %s", i, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		hash := resources.SHAChecksum(proto)
+		if _, ok := applied[hash]; ok {
+			continue
+		}
+
+		stdout, err := r.execInStoragePod(ctx, storage, storage.GetConfigInvokeCommand(proto))
+		if err == nil {
+			err = resources.CheckConfigInvokeResult(stdout)
+		}
+		if err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"BSConfigFailed",
+				fmt.Sprintf("Failed to apply additionalBSConfig[%d]: %s", i, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			"BSConfigApplied",
+			fmt.Sprintf("Applied additionalBSConfig[%d] with checksum %s", i, hash),
+		)
+		storage.Status.AppliedBSConfig = append(storage.Status.AppliedBSConfig, hash)
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step applyAdditionalBSConfig")
+	return Continue, ctrl.Result{}, nil
+}
+
+func (r *Reconciler) getBSConfigProto(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	command v1alpha1.BSConfigCommand,
+) (string, error) {
+	if command.ConfigMapKeyRef == nil {
+		return command.Proto, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      command.ConfigMapKeyRef.Name,
+		Namespace: storage.Namespace,
+	}, configMap); err != nil {
+		return "", fmt.Errorf("failed to get ConfigMap %s: %w", command.ConfigMapKeyRef.Name, err)
+	}
+
+	proto, ok := configMap.Data[command.ConfigMapKeyRef.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s is not found in ConfigMap %s", command.ConfigMapKeyRef.Key, command.ConfigMapKeyRef.Name)
+	}
+	return proto, nil
+}
+
+// execInStoragePod executes command in ready Storage pods and returns its
+// stdout. Token of operator connection, if any, is refreshed in the operator
+// token Secret, which is mounted in Storage pods and read by the command.
+// Pods are tried in the order of ordinals until command is executed in one
+// of them, so that a single unhealthy pod does not block the operator.
+func (r *Reconciler) execInStoragePod(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	cmd []string,
+) (string, error) {
+	ctx, span := tracing.Start(ctx, "execInStoragePod", attribute.String("command", strings.Join(cmd, " ")))
+	defer span.End()
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList,
+		client.InNamespace(storage.Namespace),
+		client.MatchingLabels(labels.StorageLabels(storage.Unwrap())),
+	); err != nil {
+		return "", fmt.Errorf("failed to list Storage pods: %w", err)
+	}
+	pods := resources.ReadyPodsByOrdinal(podList.Items)
+	if len(pods) == 0 {
+		return "", errNoReadyStoragePod
+	}
+
+	if storage.Spec.OperatorConnection != nil {
+		creds, err := resources.GetYDBCredentials(ctx, storage.Unwrap(), r.Config)
+		if err != nil {
+			return "", fmt.Errorf("failed to get YDB credentials: %w", err)
+		}
+		// Token is never passed in arguments of the command, which are seen
+		// in exec requests and in the process list of the pod
+		if err := r.createOrUpdateOperatorTokenSecret(ctx, storage, creds); err != nil {
+			return "", fmt.Errorf("failed to update operator token Secret: %w", err)
+		}
+	}
+
+	// Command which is started is not interrupted on operator shutdown,
+	// e.g. storage pools being defined are let to be defined
+	execCtx, cancel := shutdown.Critical(ctx)
+	defer cancel()
+
+	var errs []error
+	for _, pod := range pods {
+		stdout, _, err := exec.InPod(execCtx, r.Scheme, r.Config, pod.Namespace, pod.Name, v1alpha1.StorageContainerName, cmd)
+		if err == nil {
+			return stdout, nil
+		}
+		log.FromContext(ctx).Info("failed to execute command in Storage pod, trying next one",
+			"pod", pod.Name, "error", err.Error())
+		errs = append(errs, fmt.Errorf("pod %s: %w", pod.Name, err))
+	}
+	return "", errors.Join(errs...)
+}
@@ -2,18 +2,25 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ydb-platform/ydb-go-sdk/v3"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/shutdown"
 )
 
 func (r *Reconciler) replaceConfig(
@@ -22,9 +29,12 @@ func (r *Reconciler) replaceConfig(
 	cmsConfig *cms.Config,
 	ydbOptions ydb.Option,
 ) (bool, ctrl.Result, error) {
+	ctx, cancel := shutdown.Critical(ctx)
+	defer cancel()
+
 	response, err := cmsConfig.ReplaceConfig(ctx, ydbOptions)
 	if err != nil {
-		r.Log.Error(err, "failed to request CMS ReplaceConfig")
+		log.FromContext(ctx).Error(err, "failed to request CMS ReplaceConfig")
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
 			Type:               ReplaceConfigOperationCondition,
 			Status:             metav1.ConditionFalse,
@@ -37,7 +47,7 @@ func (r *Reconciler) replaceConfig(
 
 	finished, operationID, err := cmsConfig.CheckReplaceConfigResponse(ctx, response)
 	if err != nil {
-		r.Log.Error(err, "failed response CMS ReplaceConfig")
+		log.FromContext(ctx).Error(err, "failed response CMS ReplaceConfig")
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
 			Type:               ReplaceConfigOperationCondition,
 			Status:             metav1.ConditionFalse,
@@ -100,7 +110,7 @@ func (r *Reconciler) checkReplaceConfigOperation(
 	}
 	response, err := operation.GetOperation(ctx, ydbOptions)
 	if err != nil {
-		r.Log.Error(err, "request CMS GetOperation error")
+		log.FromContext(ctx).Error(err, "request CMS GetOperation error")
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeWarning,
@@ -159,7 +169,7 @@ func (r *Reconciler) setConfigPipelineStatus(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step setConfigPipelineStatus")
+	log.FromContext(ctx).Info("running step setConfigPipelineStatus")
 	isDynConfig, dynConfig, _ := v1alpha1.ParseDynConfig(storage.Spec.Configuration)
 	if !isDynConfig {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
@@ -212,7 +222,7 @@ func (r *Reconciler) setConfigPipelineStatus(
 		)
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
-	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions)
+	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(storage.Unwrap()))
 
 	response, err := cmsConfig.GetConfig(ctx, ydbOpts)
 	if err != nil {
@@ -244,7 +254,7 @@ func (r *Reconciler) setConfigPipelineStatus(
 			Reason:             ReasonNotRequired,
 			Message:            fmt.Sprintf("Configuration already synced to version %d", cmsConfig.Version),
 		})
-		r.Log.Info("complete step setConfigPipelineStatus")
+		log.FromContext(ctx).Info("complete step setConfigPipelineStatus")
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
@@ -255,7 +265,7 @@ func (r *Reconciler) setConfigPipelineStatus(
 		Reason:             ReasonInProgress,
 		Message:            fmt.Sprintf("Sync configuration in progress to version %d", dynConfig.Metadata.Version),
 	})
-	r.Log.Info("complete step setConfigPipelineStatus")
+	log.FromContext(ctx).Info("complete step setConfigPipelineStatus")
 	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 }
 
@@ -263,7 +273,7 @@ func (r *Reconciler) handleConfigurationSync(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step handleConfigurationSync")
+	log.FromContext(ctx).Info("running step handleConfigurationSync")
 
 	_, dynConfig, err := v1alpha1.ParseDynConfig(storage.Spec.Configuration)
 	if err != nil {
@@ -296,7 +306,7 @@ func (r *Reconciler) handleConfigurationSync(
 		)
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
-	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions)
+	ydbOpts := ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(storage.Unwrap()))
 
 	cmsConfig := &cms.Config{
 		StorageEndpoint:    storage.GetStorageEndpointWithProto(),
@@ -322,6 +332,210 @@ func (r *Reconciler) handleConfigurationSync(
 		Reason:             ReasonCompleted,
 		Message:            fmt.Sprintf("Configuration synced successfully to version %d", cmsConfig.Version),
 	})
-	r.Log.Info("complete step handleConfigurationSync")
+	log.FromContext(ctx).Info("complete step handleConfigurationSync")
+	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+}
+
+// validateConfiguration validates changed configuration against the running
+// cluster before it is written to the ConfigMap and rolled out to pods.
+// Validation is skipped until the Storage is initialized, since there is no
+// cluster to validate against yet, and with AnnotationSkipConfigValidation.
+// Only rejection of the configuration by the validate command blocks the
+// rollout, failure to execute the command is retried.
+func (r *Reconciler) validateConfiguration(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	configuration := storage.GetConfiguration()
+	if storage.Status.ObservedConfigHash == resources.SHAChecksum(configuration) ||
+		!meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	if value, ok := storage.Annotations[v1alpha1.AnnotationSkipConfigValidation]; ok && value == v1alpha1.AnnotationValueTrue {
+		if meta.FindStatusCondition(storage.Status.Conditions, ConfigValidationFailedCondition) != nil {
+			meta.RemoveStatusCondition(&storage.Status.Conditions, ConfigValidationFailedCondition)
+			return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+		}
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step validateConfiguration")
+
+	_, err := r.execInStoragePod(ctx, storage, storage.GetConfigValidateCommand(configuration))
+	if errors.Is(err, errNoReadyStoragePod) {
+		log.FromContext(ctx).Info("no ready Storage pod to validate configuration, skipping validation")
+		return Continue, ctrl.Result{}, nil
+	}
+	if err != nil && !exec.IsExitError(err) {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to validate configuration: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			ConfigValidationFailedCondition,
+			fmt.Sprintf("Configuration is rejected by the cluster: %s", err),
+		)
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:               ConfigValidationFailedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonFailed,
+			Message:            err.Error(),
+		})
+		return r.updateStatus(ctx, storage, DefaultRequeueDelay)
+	}
+
+	if meta.FindStatusCondition(storage.Status.Conditions, ConfigValidationFailedCondition) != nil {
+		meta.RemoveStatusCondition(&storage.Status.Conditions, ConfigValidationFailedCondition)
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step validateConfiguration")
+	return Continue, ctrl.Result{}, nil
+}
+
+// setConfigPendingCondition marks changed configuration as not applied to
+// the Pods yet, the Pods created after the condition transition have read
+// the new configuration from ConfigMap.
+func setConfigPendingCondition(storage *resources.StorageClusterBuilder, configHash string) {
+	meta.RemoveStatusCondition(&storage.Status.Conditions, ConfigPendingCondition)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               ConfigPendingCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonInProgress,
+		Message:            fmt.Sprintf("Configuration %s is applied after the Storage Pods are deleted", configHash),
+	})
+}
+
+// syncConfigPendingCondition resolves ConfigPending condition once all the
+// Storage Pods are recreated after the configuration change. The condition
+// is only maintained with Manual configChangePolicy.
+func (r *Reconciler) syncConfigPendingCondition(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	condition := meta.FindStatusCondition(storage.Status.Conditions, ConfigPendingCondition)
+	if condition == nil {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	if storage.Spec.ConfigChangePolicy != v1alpha1.ConfigChangePolicyManual {
+		meta.RemoveStatusCondition(&storage.Status.Conditions, ConfigPendingCondition)
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	if condition.Status != metav1.ConditionTrue {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList,
+		client.InNamespace(storage.Namespace),
+		client.MatchingLabels(labels.StorageLabels(storage.Unwrap())),
+	); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list Storage pods")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	pending := 0
+	for i := range podList.Items {
+		if podList.Items[i].CreationTimestamp.Before(&condition.LastTransitionTime) {
+			pending++
+		}
+	}
+	if pending > 0 {
+		log.FromContext(ctx).Info(fmt.Sprintf("%d Storage pods are not restarted with changed configuration", pending))
+		return Continue, ctrl.Result{}, nil
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeNormal,
+		ConfigPendingCondition,
+		"All the Storage pods are restarted with changed configuration",
+	)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               ConfigPendingCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonCompleted,
+		Message:            "Configuration is applied to all the Storage Pods",
+	})
 	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 }
+
+// syncConfigurationTemplate renders configuration template of Storage kept
+// in ConfigMap again, so that changes of the ConfigMap made after admission
+// of Storage are applied. Rendered configuration is stored in Storage spec
+// in the same way as the webhook does.
+func (r *Reconciler) syncConfigurationTemplate(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	if !storage.Spec.OperatorSync || storage.Spec.ConfigurationTemplate == nil ||
+		storage.Spec.ConfigurationTemplate.ConfigMapKeyRef == nil {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step syncConfigurationTemplate")
+
+	configuration, err := v1alpha1.RenderStorageConfiguration(ctx, r.Client, storage.Unwrap())
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ConfigurationTemplate",
+			fmt.Sprintf("Failed to render configuration template, current configuration is kept: %s", err),
+		)
+		return Continue, ctrl.Result{}, nil
+	}
+
+	if string(configuration) == storage.Spec.Configuration {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	storageCr := &v1alpha1.Storage{}
+	err = r.Client.Get(ctx, types.NamespacedName{
+		Namespace: storage.Namespace,
+		Name:      storage.Name,
+	}, storageCr)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			"Failed fetching CR before configuration update",
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	storageCr.Spec.Configuration = string(configuration)
+	if err = r.Client.Update(ctx, storageCr); err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to update configuration of Storage: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeNormal,
+		"ConfigurationTemplate",
+		fmt.Sprintf("Configuration is rendered from changed template ConfigMap %s",
+			storage.Spec.ConfigurationTemplate.ConfigMapKeyRef.Name),
+	)
+	// Reconcile is continued by the change of Storage spec
+	return Stop, ctrl.Result{RequeueAfter: StatusUpdateRequeueDelay}, nil
+}
@@ -5,11 +5,11 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/go-logr/logr"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -18,6 +18,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -28,6 +29,8 @@ import (
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	ydbannotations "github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
 )
 
@@ -37,9 +40,15 @@ type Reconciler struct {
 	Scheme   *runtime.Scheme
 	Config   *rest.Config
 	Recorder record.EventRecorder
-	Log      logr.Logger
 
-	WithServiceMonitors bool
+	WithServiceMonitors     bool
+	MaxConcurrentReconciles int
+	// SkipStorageInit disables initialization of all Storages, e.g. when
+	// bootstrap is performed by managed control plane
+	SkipStorageInit bool
+
+	requeueDelayWarnings   requeue.Warnings
+	versionIgnoredWarnings requeue.Warnings
 }
 
 //+kubebuilder:rbac:groups=ydb.tech,resources=storages,verbs=get;list;watch;create;update;patch;delete
@@ -54,6 +63,7 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
@@ -61,6 +71,7 @@ type Reconciler struct {
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
@@ -69,16 +80,15 @@ type Reconciler struct {
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	r.Log = log.FromContext(ctx)
-
 	resource := &v1alpha1.Storage{}
 	err := r.Get(ctx, req.NamespacedName, resource)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			r.Log.Info("Storage resource not found")
+			log.FromContext(ctx).Info("Storage resource not found")
+			metrics.DeleteClusterReady(StorageKind, req.Namespace, req.Name)
 			return ctrl.Result{Requeue: false}, nil
 		}
-		r.Log.Error(err, "unexpected Get error")
+		log.FromContext(ctx).Error(err, "unexpected Get error")
 		return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
 
@@ -109,15 +119,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			if err := r.Client.Update(ctx, resource); err != nil {
 				return ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 			}
+			metrics.DeleteClusterReady(StorageKind, resource.Namespace, resource.Name)
 		}
 
 		// Stop reconciliation as the item is being deleted
 		return ctrl.Result{Requeue: false}, nil
 	}
 
+	metrics.SetClusterReady(
+		StorageKind,
+		resource.Namespace,
+		resource.Name,
+		meta.IsStatusConditionTrue(resource.Status.Conditions, StorageReadyCondition),
+	)
+
 	result, err := r.Sync(ctx, resource)
 	if err != nil {
-		r.Log.Error(err, "unexpected Sync error")
+		log.FromContext(ctx).Error(err, "unexpected Sync error")
 	}
 
 	return result, err
@@ -180,18 +198,28 @@ func createFieldIndexers(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&v1alpha1.Storage{},
+		ConfigMapField,
+		func(obj client.Object) []string {
+			// ConfigMaps with configuration template and bs config requests
+			// are indexed, so that their changes are applied by reconcile
+			storage := obj.(*v1alpha1.Storage)
+			return storage.GetReferencedConfigMaps()
+		}); err != nil {
+		return err
+	}
+
 	return mgr.GetFieldIndexer().IndexField(
 		context.Background(),
 		&v1alpha1.Storage{},
 		SecretField,
 		func(obj client.Object) []string {
-			secrets := []string{}
+			// TLS certificates and credentials are indexed as well,
+			// so that their rotation is picked up by reconcile
 			storage := obj.(*v1alpha1.Storage)
-			for _, secret := range storage.Spec.Secrets {
-				secrets = append(secrets, secret.Name)
-			}
-
-			return secrets
+			return storage.GetReferencedSecrets()
 		})
 }
 
@@ -201,7 +229,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	controller := ctrl.NewControllerManagedBy(mgr)
 
 	if err := createFieldIndexers(mgr); err != nil {
-		r.Log.Error(err, "unexpected FieldIndexer error")
+		mgr.GetLogger().Error(err, "unexpected FieldIndexer error")
 		return err
 	}
 
@@ -212,7 +240,11 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return controller.
 		For(&v1alpha1.Storage{},
-			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+			// Annotations are watched to pick up reinitialization requests
+			builder.WithPredicates(predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			)),
 		).
 		Owns(&v1alpha1.RemoteStorageNodeSet{},
 			builder.WithPredicates(resources.LastAppliedAnnotationPredicate()), // TODO: YDBOPS-9194
@@ -234,8 +266,14 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.findStoragesForSecret),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.findStoragesForConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		WithEventFilter(resources.IsStorageCreatePredicate()).
 		WithEventFilter(resources.IgnoreDeleteStateUnknownPredicate()).
+		WithOptions(ctrlcontroller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
@@ -263,6 +301,30 @@ func (r *Reconciler) findStoragesForSecret(secret client.Object) []reconcile.Req
 	return requests
 }
 
+func (r *Reconciler) findStoragesForConfigMap(configMap client.Object) []reconcile.Request {
+	attachedStorages := &v1alpha1.StorageList{}
+	err := r.List(
+		context.Background(),
+		attachedStorages,
+		client.InNamespace(configMap.GetNamespace()),
+		client.MatchingFields{ConfigMapField: configMap.GetName()},
+	)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(attachedStorages.Items))
+	for i, item := range attachedStorages.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
+
 func (r *Reconciler) checkExistingDatabases(
 	ctx context.Context,
 	storage *v1alpha1.Storage,
@@ -277,7 +339,7 @@ func (r *Reconciler) checkExistingDatabases(
 		},
 	)
 	if err != nil {
-		r.Log.Error(err, "failed to list Databases")
+		log.FromContext(ctx).Error(err, "failed to list Databases")
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeWarning,
@@ -293,7 +355,7 @@ func (r *Reconciler) checkExistingDatabases(
 			databases = append(databases, database.Name)
 		}
 		errMessage := fmt.Sprintf("Waiting for existing Databases to be deleted: %v", databases)
-		r.Log.Info(errMessage)
+		log.FromContext(ctx).Info(errMessage)
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeNormal,
@@ -11,14 +11,21 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	testobjects "github.com/ydb-platform/ydb-kubernetes-operator/e2e/tests/test-objects"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/storage"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
@@ -28,12 +35,13 @@ import (
 var (
 	k8sClient client.Client
 	ctx       context.Context
+	env       *envtest.Environment
 )
 
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
-	test.SetupK8STestManager(&ctx, &k8sClient, func(mgr *manager.Manager) []test.Reconciler {
+	env = test.SetupK8STestManager(&ctx, &k8sClient, func(mgr *manager.Manager) []test.Reconciler {
 		return []test.Reconciler{
 			&storage.Reconciler{
 				Client: k8sClient,
@@ -58,7 +66,67 @@ var _ = Describe("Storage controller medium tests", func() {
 	})
 
 	AfterEach(func() {
-		Expect(k8sClient.Delete(ctx, &namespace)).Should(Succeed())
+		test.DeleteAllObjects(env, k8sClient, &namespace)
+	})
+
+	It("Recreates deleted Service of Ready Storage", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		Expect(k8sClient.Create(ctx, storageSample)).Should(Succeed())
+
+		By("Make all pods of StatefulSet ready")
+		Eventually(func() error {
+			sts := appsv1.StatefulSet{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      testobjects.StorageName,
+				Namespace: testobjects.YdbNamespace,
+			}, &sts); err != nil {
+				return err
+			}
+			sts.Status.ObservedGeneration = sts.Generation
+			sts.Status.Replicas = *sts.Spec.Replicas
+			sts.Status.ReadyReplicas = *sts.Spec.Replicas
+			return k8sClient.Status().Update(ctx, &sts)
+		}, test.Timeout, test.Interval).ShouldNot(HaveOccurred())
+
+		By("Set Storage Ready with reconciled generation")
+		Eventually(func(g Gomega) {
+			found := v1alpha1.Storage{}
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      testobjects.StorageName,
+				Namespace: testobjects.YdbNamespace,
+			}, &found)).Should(Succeed())
+			if found.Status.State != constants.StorageReady {
+				meta.SetStatusCondition(&found.Status.Conditions, metav1.Condition{
+					Type:   constants.StorageInitializedCondition,
+					Status: metav1.ConditionTrue,
+					Reason: constants.ReasonCompleted,
+				})
+				found.Status.State = constants.StorageReady
+				found.Status.ObservedGeneration = found.Generation
+				g.Expect(k8sClient.Status().Update(ctx, &found)).Should(Succeed())
+			}
+			g.Expect(found.Status.State).Should(Equal(constants.StorageReady))
+			g.Expect(found.Status.ObservedGeneration).Should(Equal(found.Generation))
+		}, test.Timeout, test.Interval).Should(Succeed())
+
+		grpcService := corev1.Service{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{
+			Name:      fmt.Sprintf(resources.GRPCServiceNameFormat, testobjects.StorageName),
+			Namespace: testobjects.YdbNamespace,
+		}, &grpcService)).Should(Succeed())
+
+		By("Delete gRPC Service")
+		Expect(k8sClient.Delete(ctx, &grpcService)).Should(Succeed())
+
+		By("Check that gRPC Service is recreated")
+		Eventually(func(g Gomega) {
+			found := corev1.Service{}
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      fmt.Sprintf(resources.GRPCServiceNameFormat, testobjects.StorageName),
+				Namespace: testobjects.YdbNamespace,
+			}, &found)).Should(Succeed())
+			g.Expect(found.UID).ShouldNot(Equal(grpcService.UID))
+		}, test.Timeout, test.Interval).Should(Succeed())
 	})
 
 	It("Checking field propagation to objects", func() {
@@ -178,6 +246,40 @@ var _ = Describe("Storage controller medium tests", func() {
 				return nil
 			}, test.Timeout, test.Interval).ShouldNot(HaveOccurred())
 
+			By("check that additional labels were added to all owned resources...")
+			Eventually(func() error {
+				foundServices := corev1.ServiceList{}
+				if err := k8sClient.List(ctx, &foundServices, client.InNamespace(testobjects.YdbNamespace)); err != nil {
+					return err
+				}
+				foundConfigMaps := corev1.ConfigMapList{}
+				if err := k8sClient.List(ctx, &foundConfigMaps, client.InNamespace(testobjects.YdbNamespace)); err != nil {
+					return err
+				}
+				var objects []client.Object
+				for i := range foundServices.Items {
+					objects = append(objects, &foundServices.Items[i])
+				}
+				for i := range foundConfigMaps.Items {
+					if foundConfigMaps.Items[i].Name == storageSample.Name {
+						objects = append(objects, &foundConfigMaps.Items[i])
+					}
+				}
+				for _, object := range objects {
+					if object.GetLabels()[testLabelKey] != testLabelValue {
+						return fmt.Errorf("label `ydb-label` is not set on %s. Current labels: %s", object.GetName(), object.GetLabels())
+					}
+				}
+				return nil
+			}, test.Timeout, test.Interval).ShouldNot(HaveOccurred())
+
+			By("check that Service selectors do not depend on additional labels...")
+			foundServices := corev1.ServiceList{}
+			Expect(k8sClient.List(ctx, &foundServices, client.InNamespace(testobjects.YdbNamespace))).Should(Succeed())
+			for _, service := range foundServices.Items {
+				Expect(service.Spec.Selector).ShouldNot(HaveKey(testLabelKey))
+			}
+
 			By("check that StatefulSet selector was not updated...")
 			Expect(*foundStatefulSets.Items[0].Spec.Selector).Should(BeEquivalentTo(
 				metav1.LabelSelector{
@@ -198,3 +300,76 @@ var _ = Describe("Storage controller medium tests", func() {
 		})
 	})
 })
+
+var _ = Describe("Storage configuration template", func() {
+	It("renders configuration again on change of template ConfigMap", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configuration-template",
+				Namespace: storageSample.Namespace,
+			},
+			Data: map[string]string{
+				"config.yaml": storageSample.Spec.Configuration + "\n# domain {{ .Spec.Domain }}\n",
+			},
+		}
+		storageSample.Spec.ConfigurationTemplate = &v1alpha1.ConfigurationTemplate{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				Key:                  "config.yaml",
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample, configMap).Build()
+		reconciler := &storage.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      storageSample.Name,
+			Namespace: storageSample.Namespace,
+		}}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		found := &v1alpha1.Storage{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(found.Spec.Configuration).To(HaveSuffix(fmt.Sprintf("# domain %s\n", storageSample.Spec.Domain)))
+	})
+})
+
+var _ = Describe("Storage freeze", func() {
+	It("does not freeze Storage which is not initialized yet", func() {
+		storageSample := testobjects.DefaultStorage(filepath.Join("..", "..", "..", "e2e", "tests", "data", "storage-mirror-3-dc-config.yaml"))
+		storageSample.Spec.Pause = true
+		storageSample.Spec.PauseMode = v1alpha1.PauseModeFreeze
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(storageSample).Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &storage.Reconciler{
+			Client:   fakeClient,
+			Scheme:   scheme.Scheme,
+			Recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Name:      storageSample.Name,
+			Namespace: storageSample.Namespace,
+		}}
+
+		for i := 0; i < 5; i++ {
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		found := &v1alpha1.Storage{}
+		Expect(fakeClient.Get(context.Background(), request.NamespacedName, found)).Should(Succeed())
+		Expect(found.Status.State).ToNot(Equal(constants.StoragePaused))
+
+		close(recorder.Events)
+		for event := range recorder.Events {
+			Expect(event).ToNot(ContainSubstring("pauseMode: Freeze"))
+		}
+	})
+})
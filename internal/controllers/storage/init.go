@@ -19,10 +19,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/tracing"
 )
 
 var mismatchItemConfigGenerationRegexp = regexp.MustCompile(".*mismatch.*ItemConfigGenerationProvided# " +
@@ -34,10 +37,11 @@ func (r *Reconciler) setInitPipelineStatus(
 ) (bool, ctrl.Result, error) {
 	if storage.Status.State == StoragePreparing {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:    StorageInitializedCondition,
-			Status:  metav1.ConditionUnknown,
-			Reason:  ReasonInProgress,
-			Message: "Storage has not been initialized yet",
+			Type:               StorageInitializedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonInProgress,
+			Message:            "Storage has not been initialized yet",
 		})
 		storage.Status.State = StorageInitializing
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
@@ -45,7 +49,7 @@ func (r *Reconciler) setInitPipelineStatus(
 
 	// This block is special internal logic that skips all Storage initialization.
 	if value, ok := storage.Annotations[v1alpha1.AnnotationSkipInitialization]; ok && value == v1alpha1.AnnotationValueTrue {
-		r.Log.Info("Storage initialization disabled (with annotation), proceed with caution")
+		log.FromContext(ctx).Info("Storage initialization disabled (with annotation), proceed with caution")
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeWarning,
@@ -55,30 +59,144 @@ func (r *Reconciler) setInitPipelineStatus(
 		return r.setInitStorageCompleted(ctx, storage, "Storage initialization not performed because initialization is skipped")
 	}
 
+	if r.SkipStorageInit {
+		log.FromContext(ctx).Info("Storage initialization disabled for operator")
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			"SkippingInit",
+			"Skipping initialization, it is disabled for operator with --skip-storage-init",
+		)
+		return r.setInitStorageCompleted(ctx, storage, "Storage initialization not performed because it is disabled for operator")
+	}
+
+	if value, ok := storage.Annotations[v1alpha1.AnnotationReinitialize]; ok && value == v1alpha1.AnnotationValueTrue &&
+		!isReinitializeBlobstorageRequested(storage) {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			"InitializingStorage",
+			"Skipping init blobstorage Job on reinitialization",
+		)
+		return r.setInitStorageCompleted(ctx, storage, "Storage reinitialized without init blobstorage Job")
+	}
+
 	if meta.IsStatusConditionTrue(storage.Status.Conditions, OldStorageInitializedCondition) {
 		return r.setInitStorageCompleted(ctx, storage, "Storage initialized successfully")
 	}
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
+func isReinitializeBlobstorageRequested(storage *resources.StorageClusterBuilder) bool {
+	value, ok := storage.Annotations[v1alpha1.AnnotationReinitializeBlobstorage]
+	return ok && value == v1alpha1.AnnotationValueTrue
+}
+
+// requestReinitialization resets conditions of initialization pipeline and
+// configuration sync, so both of them run again on next reconcile.
+func (r *Reconciler) requestReinitialization(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("Storage reinitialization requested (with annotation)")
+
+	if isReinitializeBlobstorageRequested(storage) {
+		initJob := &batchv1.Job{}
+		initJob.Name = fmt.Sprintf(resources.InitJobNameFormat, storage.Name)
+		initJob.Namespace = storage.Namespace
+		err := r.Delete(ctx, initJob, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil && !apierrors.IsNotFound(err) {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to delete Job: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeNormal,
+		"InitializingStorage",
+		"Storage reinitialization requested with annotation",
+	)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               StorageInitializedCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonInProgress,
+		Message:            "Storage reinitialization requested",
+	})
+	meta.RemoveStatusCondition(&storage.Status.Conditions, ConfigurationSyncedCondition)
+	meta.RemoveStatusCondition(&storage.Status.Conditions, ReplaceConfigOperationCondition)
+	meta.RemoveStatusCondition(&storage.Status.Conditions, DefineBoxSyncedCondition)
+	storage.Status.InitializedBoxHash = ""
+	storage.Status.AppliedBSConfig = nil
+	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+}
+
 func (r *Reconciler) setInitStorageCompleted(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 	message string,
 ) (bool, ctrl.Result, error) {
+	// Annotation must be removed before condition is set, otherwise
+	// reinitialization would be requested again
+	if err := r.removeReinitializeAnnotations(ctx, storage); err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to remove reinitialize annotations: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
 	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-		Type:    StorageInitializedCondition,
-		Status:  metav1.ConditionTrue,
-		Reason:  ReasonCompleted,
-		Message: message,
+		Type:               StorageInitializedCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonCompleted,
+		Message:            message,
 	})
+	storage.Status.InitializedBoxHash = storage.GetDefineBoxHash()
 	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 }
 
+func (r *Reconciler) removeReinitializeAnnotations(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) error {
+	_, reinitialize := storage.Annotations[v1alpha1.AnnotationReinitialize]
+	_, reinitializeBlobstorage := storage.Annotations[v1alpha1.AnnotationReinitializeBlobstorage]
+	if !reinitialize && !reinitializeBlobstorage {
+		return nil
+	}
+
+	storageCr := storage.Unwrap()
+	patch := client.MergeFrom(storageCr.DeepCopy())
+	delete(storageCr.Annotations, v1alpha1.AnnotationReinitialize)
+	delete(storageCr.Annotations, v1alpha1.AnnotationReinitializeBlobstorage)
+	return r.Patch(ctx, storageCr, patch)
+}
+
+func getInitRequeueDelay(storage *resources.StorageClusterBuilder) time.Duration {
+	if storage.Spec.InitJob != nil && storage.Spec.InitJob.RequeueDelay != nil &&
+		storage.Spec.InitJob.RequeueDelay.Duration > 0 {
+		return storage.Spec.InitJob.RequeueDelay.Duration
+	}
+	return StorageInitializationRequeueDelay
+}
+
 func (r *Reconciler) initializeBlobstorage(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "initializeBlobstorage")
+	defer span.End()
+
 	initJob := &batchv1.Job{}
 	err := r.Get(ctx, types.NamespacedName{
 		Name:      fmt.Sprintf(resources.InitJobNameFormat, storage.Name),
@@ -125,7 +243,7 @@ func (r *Reconciler) initializeBlobstorage(
 			"InitializingStorage",
 			fmt.Sprintf("Successfully created Job %s", fmt.Sprintf(resources.InitJobNameFormat, storage.Name)),
 		)
-		return Stop, ctrl.Result{RequeueAfter: StorageInitializationRequeueDelay}, nil
+		return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(getInitRequeueDelay(storage))}, nil
 	}
 
 	if err != nil {
@@ -138,8 +256,13 @@ func (r *Reconciler) initializeBlobstorage(
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
 
+	if initJob.DeletionTimestamp != nil {
+		log.FromContext(ctx).Info("Waiting for previous init Job to be deleted")
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+	}
+
 	if initJob.Status.Succeeded > 0 {
-		r.Log.Info("Init Job status succeeded")
+		log.FromContext(ctx).Info("Init Job status succeeded")
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeNormal,
@@ -169,7 +292,7 @@ func (r *Reconciler) initializeBlobstorage(
 	}
 
 	if initialized {
-		r.Log.Info("Storage is already initialized, continuing...")
+		log.FromContext(ctx).Info("Storage is already initialized, continuing...")
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeNormal,
@@ -196,9 +319,11 @@ func (r *Reconciler) initializeBlobstorage(
 			"Failed initBlobstorage Job, check Pod logs for addditional info",
 		)
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:   StorageInitializedCondition,
-			Status: metav1.ConditionFalse,
-			Reason: ReasonInProgress,
+			Type:               StorageInitializedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonFailed,
+			Message:            "Failed initBlobstorage Job, retrying",
 		})
 		if err := r.Delete(ctx, initJob, client.PropagationPolicy(metav1.DeletePropagationForeground)); err != nil {
 			r.Recorder.Event(
@@ -219,7 +344,7 @@ func (r *Reconciler) initializeBlobstorage(
 		"InitializingStorage",
 		fmt.Sprintf("Waiting for Job %s status update", initJob.Name),
 	)
-	return Stop, ctrl.Result{RequeueAfter: StorageInitializationRequeueDelay}, nil
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(getInitRequeueDelay(storage))}, nil
 }
 
 func (r *Reconciler) checkFailedJob(
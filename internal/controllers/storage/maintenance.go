@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+// handleMaintenanceRollout restarts Storage nodes one by one in Maintenance
+// rolling update mode: the partition is lowered to the next Pod only after
+// CMS grants maintenance task for its node, and the task is dropped once
+// the Pod is updated and ready. Expects all the Storage Pods to be ready.
+func (r *Reconciler) handleMaintenanceRollout(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	sts *appsv1.StatefulSet,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step handleMaintenanceRollout")
+
+	if sts.Status.ObservedGeneration != sts.Generation {
+		return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DefaultRequeueDelay)}, nil
+	}
+
+	rollingOut := sts.Status.UpdateRevision != sts.Status.CurrentRevision
+	partition := storage.Spec.Nodes
+	if storage.Status.UpdatePartition != nil {
+		partition = *storage.Status.UpdatePartition
+	}
+	podsUpdated := !rollingOut || sts.Status.UpdatedReplicas >= storage.Spec.Nodes-partition
+
+	if storage.Status.MaintenanceTask != "" {
+		if !podsUpdated {
+			return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DefaultRequeueDelay)}, nil
+		}
+
+		// Node of the Pod restarted under the task must be up again,
+		// otherwise CMS could permit maintenance of the next node
+		// while this one is still down
+		maintainedPodName := fmt.Sprintf("%s-%d", sts.Name, partition)
+		ready, err := r.isPodUpdatedAndReady(ctx, sts, maintainedPodName)
+		if err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to get Pod %s: %s", maintainedPodName, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+		if !ready {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeNormal,
+				string(StorageProvisioning),
+				fmt.Sprintf("Waiting for Pod %s to be ready to drop CMS maintenance task", maintainedPodName),
+			)
+			return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DefaultRequeueDelay)}, nil
+		}
+
+		ydbOpts, err := r.getYDBOptions(ctx, storage)
+		if err != nil {
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		maintenance := &cms.Maintenance{
+			StorageEndpoint: storage.GetStorageEndpointWithProto(),
+			Domain:          storage.Spec.Domain,
+			TaskUID:         storage.Status.MaintenanceTask,
+		}
+		if err := maintenance.DropMaintenance(ctx, ydbOpts); err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to drop CMS maintenance task %s: %s", storage.Status.MaintenanceTask, err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		storage.Status.MaintenanceTask = ""
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	var lowerBound int32
+	if storage.Spec.RollingUpdate.Partition != nil {
+		lowerBound = *storage.Spec.RollingUpdate.Partition
+	}
+	if !rollingOut || partition <= lowerBound {
+		log.FromContext(ctx).Info("complete step handleMaintenanceRollout")
+		return Continue, ctrl.Result{}, nil
+	}
+	if !podsUpdated {
+		return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DefaultRequeueDelay)}, nil
+	}
+
+	podName := fmt.Sprintf("%s-%d", sts.Name, partition-1)
+	var nodeID uint32
+	for _, node := range storage.Status.Nodes {
+		if node.PodName == podName {
+			nodeID = node.NodeID
+		}
+	}
+	if nodeID == 0 {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			string(StorageProvisioning),
+			fmt.Sprintf("Unknown YDB node ID of Pod %s, unable to request CMS maintenance", podName),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+	}
+
+	ydbOpts, err := r.getYDBOptions(ctx, storage)
+	if err != nil {
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	maintenance := &cms.Maintenance{
+		StorageEndpoint: storage.GetStorageEndpointWithProto(),
+		Domain:          storage.Spec.Domain,
+		TaskUID:         fmt.Sprintf("ydb-operator/%s/%s", storage.Namespace, podName),
+		Description:     fmt.Sprintf("Rolling update of Storage Pod %s", podName),
+	}
+	granted, err := maintenance.RequestMaintenance(ctx, []uint32{nodeID}, ydbOpts)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to request CMS maintenance of Pod %s: %s", podName, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if !granted {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			string(StorageProvisioning),
+			fmt.Sprintf("Waiting for CMS to permit maintenance of Pod %s", podName),
+		)
+		return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DefaultRequeueDelay)}, nil
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeNormal,
+		string(StorageProvisioning),
+		fmt.Sprintf("CMS permitted maintenance, updating Pod %s", podName),
+	)
+	storage.Status.MaintenanceTask = maintenance.TaskUID
+	storage.Status.UpdatePartition = ptr.Int32(partition - 1)
+	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+}
+
+// isPodUpdatedAndReady checks that the Pod runs the update revision of
+// StatefulSet and reports Ready condition
+func (r *Reconciler) isPodUpdatedAndReady(
+	ctx context.Context,
+	sts *appsv1.StatefulSet,
+	podName string,
+) (bool, error) {
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      podName,
+		Namespace: sts.Namespace,
+	}, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != sts.Status.UpdateRevision {
+		return false, nil
+	}
+	return len(resources.ReadyPodsByOrdinal([]corev1.Pod{*pod})) == 1, nil
+}
+
+func (r *Reconciler) getYDBOptions(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (ydb.Option, error) {
+	creds, err := resources.GetYDBCredentials(ctx, storage.Unwrap(), r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB credentials: %s", err),
+		)
+		return nil, err
+	}
+
+	tlsOptions, err := resources.GetYDBTLSOption(ctx, storage.Unwrap(), r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB TLS options: %s", err),
+		)
+		return nil, err
+	}
+
+	return ydb.MergeOptions(ydb.WithCredentials(creds), tlsOptions, resources.GetYDBKeepaliveOption(storage.Unwrap())), nil
+}
@@ -2,13 +2,19 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	ydbCredentials "github.com/ydb-platform/ydb-go-sdk/v3/credentials"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,25 +23,76 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/tracing"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/viewer"
 )
 
+// Sync reconciles Storage, steady-state requeue delays of reconcile steps
+// are replaced with the one from AnnotationRequeueDelay when it is set
 func (r *Reconciler) Sync(ctx context.Context, cr *v1alpha1.Storage) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "Storage.Sync",
+		tracing.ObjectAttributes(StorageKind, cr.Namespace, cr.Name)...)
+	result, err := r.sync(ctx, cr)
+
+	delay, ok, parseErr := requeue.DelayFromAnnotations(cr.Annotations)
+	if parseErr != nil {
+		if r.requeueDelayWarnings.ShouldReport(cr.UID, cr.Annotations[v1alpha1.AnnotationRequeueDelay]) {
+			r.Recorder.Event(
+				cr,
+				corev1.EventTypeWarning,
+				"RequeueDelay",
+				fmt.Sprintf("Using default requeue delays: %s", parseErr),
+			)
+		}
+	} else {
+		r.requeueDelayWarnings.Forget(cr.UID)
+		if ok && result.RequeueAfter > 0 {
+			result.RequeueAfter = requeue.OverrideSteadyDelay(result.RequeueAfter, delay, DefaultRequeueDelay, ReadyRequeueDelay)
+		}
+	}
+
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *Reconciler) sync(ctx context.Context, cr *v1alpha1.Storage) (ctrl.Result, error) {
 	var stop bool
 	var result ctrl.Result
 	var err error
 
 	storage := resources.NewCluster(cr)
 
+	stop, result, err = r.validateSpec(ctx, &storage)
+	if stop {
+		return result, err
+	}
+
+	stop, result, err = r.syncConfigurationTemplate(ctx, &storage)
+	if stop {
+		return result, err
+	}
+
 	stop, result, err = r.setInitialStatus(ctx, &storage)
 	if stop {
 		return result, err
 	}
 
+	// Storage is frozen only once it is initialized, initialization is not
+	// interrupted halfway
+	if storage.Spec.Pause && storage.Spec.PauseMode == v1alpha1.PauseModeFreeze &&
+		meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		return r.handleFreeze(ctx, &storage)
+	}
+
 	stop, result, err = r.handleResourcesSync(ctx, &storage)
 	if stop {
 		return result, err
@@ -46,7 +103,33 @@ func (r *Reconciler) Sync(ctx context.Context, cr *v1alpha1.Storage) (ctrl.Resul
 		return result, err
 	}
 
+	// Owned objects are synced above for Ready Storage too: they are
+	// deleted or edited out of band without change of generation
+	if isSteadyState(&storage) {
+		// Requests of `spec.additionalBSConfig` referenced from ConfigMaps
+		// change without change of generation
+		stop, result, err = r.applyAdditionalBSConfig(ctx, &storage)
+		if stop {
+			return result, err
+		}
+
+		stop, result, err = r.checkReadyStorage(ctx, &storage)
+		if stop {
+			return result, err
+		}
+	}
+
+	if value, ok := storage.Annotations[v1alpha1.AnnotationReinitialize]; ok && value == v1alpha1.AnnotationValueTrue &&
+		meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		_, result, err = r.requestReinitialization(ctx, &storage)
+		return result, err
+	}
+
 	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		if storage.Status.State == StoragePaused && !storage.Spec.Pause {
+			_, result, err = r.handlePauseResume(ctx, &storage)
+			return result, err
+		}
 		return r.handleBlobstorageInit(ctx, &storage)
 	}
 
@@ -82,20 +165,173 @@ func (r *Reconciler) Sync(ctx context.Context, cr *v1alpha1.Storage) (ctrl.Resul
 		return result, err
 	}
 
+	stop, result, err = r.applyAdditionalBSConfig(ctx, &storage)
+	if stop {
+		return result, err
+	}
+
 	stop, result, err = r.runSelfCheck(ctx, &storage, false)
 	if stop {
 		return result, err
 	}
 
+	stop, result, err = r.syncStorageGroups(ctx, &storage)
+	if stop {
+		return result, err
+	}
+
+	stop, result, err = r.syncNodesStatus(ctx, &storage)
+	if stop {
+		return result, err
+	}
+
+	// Reconcile of the current generation is completed
+	if storage.Status.ObservedGeneration != storage.Generation {
+		storage.Status.ObservedGeneration = storage.Generation
+		_, result, err = r.updateStatus(ctx, &storage, ReadyRequeueDelay)
+		return result, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// isSteadyState reports whether Storage is Ready with already reconciled
+// spec and nothing is requested outside of spec, e.g. by annotations or by
+// pending configuration, so that checkReadyStorage is enough.
+func isSteadyState(storage *resources.StorageClusterBuilder) bool {
+	if storage.Status.State != StorageReady || storage.Status.ObservedGeneration != storage.Generation {
+		return false
+	}
+	if value, ok := storage.Annotations[v1alpha1.AnnotationReinitialize]; ok && value == v1alpha1.AnnotationValueTrue {
+		return false
+	}
+	if isReinitializeBlobstorageRequested(storage) {
+		return false
+	}
+	return !meta.IsStatusConditionTrue(storage.Status.Conditions, ConfigPendingCondition)
+}
+
+// checkReadyStorage ends reconcile of Ready Storage with already reconciled
+// spec once owned objects are synced: it checks that Storage nodes are ready
+// and SelfCheck reports healthy cluster, and comes back after a long delay.
+// Continue means that the full reconcile is required.
+func (r *Reconciler) checkReadyStorage(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step checkReadyStorage")
+
+	ready, err := r.areStorageNodesReady(ctx, storage)
+	if err != nil {
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if !ready {
+		log.FromContext(ctx).Info("Storage nodes are not ready, running full reconcile")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	stop, result, err := r.runSelfCheck(ctx, storage, false)
+	if stop {
+		return stop, result, err
+	}
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageHealthyCondition) {
+		log.FromContext(ctx).Info("Storage is not healthy, running full reconcile")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("complete step checkReadyStorage")
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(ReadyRequeueDelay)}, nil
+}
+
+func (r *Reconciler) areStorageNodesReady(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, error) {
+	if storage.Spec.NodeSets == nil {
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Name:      storage.Name,
+			Namespace: storage.Namespace,
+		}, sts); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return sts.Status.ObservedGeneration == sts.Generation &&
+			sts.Status.ReadyReplicas == storage.Spec.Nodes, nil
+	}
+
+	for _, nodeSetSpec := range storage.Spec.NodeSets {
+		nodeSetName := storage.Name + "-" + nodeSetSpec.Name
+		key := types.NamespacedName{Name: nodeSetName, Namespace: storage.Namespace}
+
+		var nodeSetConditions []metav1.Condition
+		if nodeSetSpec.Remote != nil {
+			nodeSet := &v1alpha1.RemoteStorageNodeSet{}
+			if err := r.Get(ctx, key, nodeSet); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			nodeSetConditions = nodeSet.Status.Conditions
+		} else {
+			nodeSet := &v1alpha1.StorageNodeSet{}
+			if err := r.Get(ctx, key, nodeSet); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			nodeSetConditions = nodeSet.Status.Conditions
+		}
+		if !meta.IsStatusConditionTrue(nodeSetConditions, NodeSetProvisionedCondition) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// validateSpec repeats validation of the Storage webhook, so that invalid
+// spec stops reconcile early when webhooks are not installed.
+func (r *Reconciler) validateSpec(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step validateSpec")
+
+	if err := storage.Unwrap().ValidateSpec(); err != nil {
+		condition := meta.FindStatusCondition(storage.Status.Conditions, SpecInvalidCondition)
+		if condition != nil && condition.Message == err.Error() &&
+			condition.ObservedGeneration == storage.Generation {
+			return Stop, ctrl.Result{}, nil
+		}
+
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"SpecInvalid",
+			fmt.Sprintf("Invalid Storage spec: %s", err),
+		)
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:               SpecInvalidCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonFailed,
+			Message:            err.Error(),
+		})
+		_, _, err = r.updateStatus(ctx, storage, DefaultRequeueDelay)
+		// Reconcile is resumed by the next change of Storage spec
+		return Stop, ctrl.Result{}, err
+	}
+
+	if meta.FindStatusCondition(storage.Status.Conditions, SpecInvalidCondition) != nil {
+		meta.RemoveStatusCondition(&storage.Status.Conditions, SpecInvalidCondition)
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step validateSpec")
+	return Continue, ctrl.Result{}, nil
+}
+
 func (r *Reconciler) setInitialStatus(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step setInitialStatus")
-	if storage.Status.Conditions == nil {
+	log.FromContext(ctx).Info("running step setInitialStatus")
+	if len(storage.Status.Conditions) == 0 {
 		storage.Status.Conditions = []metav1.Condition{}
 
 		if storage.Spec.Pause {
@@ -117,7 +353,24 @@ func (r *Reconciler) setInitialStatus(
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step setInitialStatus")
+	if storage.Spec.EphemeralDataStore &&
+		!meta.IsStatusConditionTrue(storage.Status.Conditions, StorageEphemeralCondition) {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"EphemeralDataStore",
+			"Data of storage nodes is kept in emptyDir volumes and is lost on pod restart",
+		)
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    StorageEphemeralCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonCompleted,
+			Message: "Data store is not durable, data is lost on pod restart",
+		})
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step setInitialStatus")
 	return Continue, ctrl.Result{}, nil
 }
 
@@ -125,14 +378,15 @@ func (r *Reconciler) waitForStatefulSetToScale(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step waitForStatefulSetToScale")
+	log.FromContext(ctx).Info("running step waitForStatefulSetToScale")
 
 	if storage.Status.State == StorageInitializing {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:    StorageProvisionedCondition,
-			Status:  metav1.ConditionUnknown,
-			Reason:  ReasonInProgress,
-			Message: fmt.Sprintf("Waiting for scale to desired nodes: %d", storage.Spec.Nodes),
+			Type:               StorageProvisionedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonInProgress,
+			Message:            fmt.Sprintf("Waiting for scale to desired nodes: %d", storage.Spec.Nodes),
 		})
 		storage.Status.State = StorageProvisioning
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
@@ -162,7 +416,68 @@ func (r *Reconciler) waitForStatefulSetToScale(
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
 
+	var updatePartition *int32
+	if foundStatefulSet.Spec.UpdateStrategy.RollingUpdate != nil {
+		updatePartition = foundStatefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	if !reflect.DeepEqual(storage.Status.UpdatePartition, updatePartition) {
+		storage.Status.UpdatePartition = updatePartition
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	// Report versions of nodes during rollout, as the checks below
+	// stop reconcile until the rollout is finished
+	if foundStatefulSet.Status.UpdateRevision != foundStatefulSet.Status.CurrentRevision &&
+		meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		if stop, result, err := r.syncNodesStatus(ctx, storage); stop {
+			return stop, result, err
+		}
+	}
+
+	if stop, result, err := r.checkRolloutStuck(ctx, storage, foundStatefulSet); stop {
+		return stop, result, err
+	}
+
+	if shouldRollbackUpgrade(storage, foundStatefulSet) &&
+		foundStatefulSet.Status.UpdatedReplicas > 0 &&
+		meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		if stop, result, err := r.checkUpgradeHealth(ctx, storage); stop {
+			return stop, result, err
+		}
+	}
+
 	if foundStatefulSet.Status.ReadyReplicas != storage.Spec.Nodes {
+		podList := &corev1.PodList{}
+		if err := r.List(ctx, podList,
+			client.InNamespace(storage.Namespace),
+			client.MatchingLabels{labels.StatefulsetComponent: storage.Name},
+		); err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to list Storage pods: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+
+		if problem, found := resources.FindPodProblem(podList.Items); found {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				problem.Reason,
+				problem.Message,
+			)
+			meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+				Type:               StorageProvisionedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: storage.Generation,
+				Reason:             problem.Reason,
+				Message:            problem.Message,
+			})
+			return r.updateStatus(ctx, storage, DefaultRequeueDelay)
+		}
+
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeNormal,
@@ -170,45 +485,164 @@ func (r *Reconciler) waitForStatefulSetToScale(
 			fmt.Sprintf("Waiting for number of running nodes to match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, storage.Spec.Nodes),
 		)
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:    StorageProvisionedCondition,
-			Status:  metav1.ConditionFalse,
-			Reason:  ReasonInProgress,
-			Message: fmt.Sprintf("Number of running nodes does not match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, storage.Spec.Nodes),
+			Type:               StorageProvisionedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonInProgress,
+			Message:            fmt.Sprintf("Number of running nodes does not match expected: %d != %d", foundStatefulSet.Status.ReadyReplicas, storage.Spec.Nodes),
 		})
 		return r.updateStatus(ctx, storage, DefaultRequeueDelay)
 	}
 
 	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageProvisionedCondition) {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:    StorageProvisionedCondition,
-			Status:  metav1.ConditionTrue,
-			Reason:  ReasonCompleted,
-			Message: fmt.Sprintf("Successfully scaled to desired number of nodes: %d", storage.Spec.Nodes),
+			Type:               StorageProvisionedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonCompleted,
+			Message:            fmt.Sprintf("Successfully scaled to desired number of nodes: %d", storage.Spec.Nodes),
 		})
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step waitForStatefulSetToScale")
+	if storage.Spec.RollingUpdate != nil &&
+		storage.Spec.RollingUpdate.Mode == v1alpha1.RollingUpdateModeMaintenance {
+		stop, result, err := r.handleMaintenanceRollout(ctx, storage, foundStatefulSet)
+		if stop {
+			return stop, result, err
+		}
+	}
+
+	// Partition is advanced in Auto mode on the next resources sync, so
+	// keep reconciling until the rolling update is finished
+	if storage.Spec.RollingUpdate != nil &&
+		storage.Spec.RollingUpdate.Mode != v1alpha1.RollingUpdateModeManual &&
+		storage.Spec.RollingUpdate.Mode != v1alpha1.RollingUpdateModeMaintenance &&
+		updatePartition != nil && *updatePartition > 0 &&
+		foundStatefulSet.Status.UpdateRevision != foundStatefulSet.Status.CurrentRevision {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			string(StorageProvisioning),
+			fmt.Sprintf("Waiting for rolling update of Pods with ordinal >= %d", *updatePartition),
+		)
+		return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(DefaultRequeueDelay)}, nil
+	}
+
+	// Remember the image of completed rollout to roll back to it if
+	// the next upgrade fails
+	if !storage.IsUpgradeRolledBack() &&
+		foundStatefulSet.Status.ObservedGeneration == foundStatefulSet.Generation &&
+		foundStatefulSet.Status.UpdateRevision == foundStatefulSet.Status.CurrentRevision &&
+		(!reflect.DeepEqual(storage.Status.PreviousImage, storage.Spec.Image) ||
+			meta.FindStatusCondition(storage.Status.Conditions, UpgradeRolledBackCondition) != nil) {
+		storage.Status.PreviousImage = storage.Spec.Image.DeepCopy()
+		meta.RemoveStatusCondition(&storage.Status.Conditions, UpgradeRolledBackCondition)
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step waitForStatefulSetToScale")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
+// shouldRollbackUpgrade reports whether the StatefulSet is rolling out
+// an image which differs from the one of the last completed rollout
+func shouldRollbackUpgrade(storage *resources.StorageClusterBuilder, sts *appsv1.StatefulSet) bool {
+	return storage.Status.PreviousImage != nil &&
+		storage.Spec.Image != nil &&
+		!storage.IsUpgradeRolledBack() &&
+		!reflect.DeepEqual(storage.Status.PreviousImage, storage.Spec.Image) &&
+		sts.Status.UpdateRevision != sts.Status.CurrentRevision
+}
+
+// checkUpgradeHealth runs SelfCheck of the cluster once nodes are restarted
+// with the new image and rolls the upgrade back if the cluster is neither
+// healthy nor degraded. Failure to run SelfCheck does not roll back, as
+// it may be caused by the operator connectivity rather than the upgrade.
+func (r *Reconciler) checkUpgradeHealth(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	creds, err := resources.GetYDBCredentials(ctx, storage.Unwrap(), r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB credentials: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	result, err := r.getSelfCheckResult(ctx, storage, creds)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "SelfCheck of upgraded cluster failed, upgrade is not rolled back")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	switch result.SelfCheckResult {
+	case Ydb_Monitoring.SelfCheck_GOOD, Ydb_Monitoring.SelfCheck_DEGRADED:
+		return Continue, ctrl.Result{}, nil
+	default:
+		return r.rollbackUpgrade(ctx, storage, fmt.Sprintf(
+			"SelfCheck result: %s, issues found: %d",
+			result.SelfCheckResult.String(),
+			len(result.IssueLog),
+		))
+	}
+}
+
+// rollbackUpgrade reverts Storage nodes to the previous image, which is
+// applied to the StatefulSet on the next resources sync
+func (r *Reconciler) rollbackUpgrade(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	reason string,
+) (bool, ctrl.Result, error) {
+	message := fmt.Sprintf(
+		"Upgrade to image %s failed, rolled back to image %s: %s",
+		storage.Spec.Image.Name,
+		storage.Status.PreviousImage.Name,
+		reason,
+	)
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeWarning,
+		UpgradeRolledBackCondition,
+		message,
+	)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               UpgradeRolledBackCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonFailed,
+		Message:            message,
+	})
+	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+}
+
 func (r *Reconciler) waitForNodeSetsToProvisioned(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step waitForNodeSetsToProvisioned")
+	log.FromContext(ctx).Info("running step waitForNodeSetsToProvisioned")
 
 	if storage.Status.State == StorageInitializing {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:    StorageProvisionedCondition,
-			Status:  metav1.ConditionUnknown,
-			Reason:  ReasonInProgress,
-			Message: "Waiting for NodeSets conditions to be Provisioned",
+			Type:               StorageProvisionedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonInProgress,
+			Message:            "Waiting for NodeSets conditions to be Provisioned",
 		})
 		storage.Status.State = StorageProvisioning
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
+	if stop, result, err := r.checkNodeSetsRolloutStuck(ctx, storage); stop {
+		return stop, result, err
+	}
+
 	for _, nodeSetSpec := range storage.Spec.NodeSets {
 		var nodeSetObject client.Object
 		var nodeSetKind string
@@ -263,9 +697,10 @@ func (r *Reconciler) waitForNodeSetsToProvisioned(
 				),
 			)
 			meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-				Type:   StorageProvisionedCondition,
-				Status: metav1.ConditionFalse,
-				Reason: ReasonInProgress,
+				Type:               StorageProvisionedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: storage.Generation,
+				Reason:             ReasonInProgress,
 				Message: fmt.Sprintf(
 					"Waiting %s with name %s for condition NodeSetProvisioned to be True",
 					nodeSetKind,
@@ -278,15 +713,16 @@ func (r *Reconciler) waitForNodeSetsToProvisioned(
 
 	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageProvisionedCondition) {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-			Type:    StorageProvisionedCondition,
-			Status:  metav1.ConditionTrue,
-			Reason:  ReasonCompleted,
-			Message: "Successfully scaled to desired number of nodes",
+			Type:               StorageProvisionedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonCompleted,
+			Message:            "Successfully scaled to desired number of nodes",
 		})
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step waitForNodeSetsToProvisioned")
+	log.FromContext(ctx).Info("complete step waitForNodeSetsToProvisioned")
 	return Continue, ctrl.Result{}, nil
 }
 
@@ -305,7 +741,7 @@ func (r *Reconciler) handleResourcesSync(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step handleResourcesSync")
+	log.FromContext(ctx).Info("running step handleResourcesSync")
 
 	if storage.Status.State == StoragePending {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
@@ -319,7 +755,7 @@ func (r *Reconciler) handleResourcesSync(
 	}
 
 	if !storage.Spec.OperatorSync {
-		r.Log.Info("`operatorSync: false` is set, no further steps will be run")
+		log.FromContext(ctx).Info("`operatorSync: false` is set, no further steps will be run")
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeNormal,
@@ -329,18 +765,37 @@ func (r *Reconciler) handleResourcesSync(
 		return Stop, ctrl.Result{}, nil
 	}
 
+	if stop, result, err := r.checkImageVersion(ctx, storage); stop {
+		return stop, result, err
+	}
+
+	if stop, result, err := r.validateConfiguration(ctx, storage); stop {
+		return stop, result, err
+	}
+
+	if stop, result, err := r.checkConfigDrift(ctx, storage); stop {
+		return stop, result, err
+	}
+
+	syncErrors := resources.SyncErrors{}
+	managedResources := []v1alpha1.ManagedResource{}
 	for _, builder := range storage.GetResourceBuilders(r.Config) {
 		newResource := builder.Placeholder(storage)
+		managedResource := resources.ManagedResourceOf(newResource)
 
 		result, err := resources.CreateOrUpdateOrMaybeIgnore(ctx, r.Client, newResource, func() error {
 			var err error
 
 			err = builder.Build(newResource)
 			if err != nil {
+				reason := "ProvisioningFailed"
+				if errors.Is(err, resources.ErrConfigTooLarge) {
+					reason = "ConfigTooLarge"
+				}
 				r.Recorder.Event(
 					storage,
 					corev1.EventTypeWarning,
-					"ProvisioningFailed",
+					reason,
 					fmt.Sprintf("Failed building resources: %s", err),
 				)
 				return err
@@ -373,13 +828,8 @@ func (r *Reconciler) handleResourcesSync(
 				"ProvisioningFailed",
 				eventMessage+fmt.Sprintf(", failed to sync, error: %s", err),
 			)
-			meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
-				Type:    StoragePreparedCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  ReasonInProgress,
-				Message: fmt.Sprintf("Failed to sync resources for generation %d", storage.Generation),
-			})
-			return r.updateStatus(ctx, storage, DefaultRequeueDelay)
+			syncErrors.Add(newResource, err)
+			managedResource.SyncError = err.Error()
 		} else if result == controllerutil.OperationResultCreated || result == controllerutil.OperationResultUpdated {
 			r.Recorder.Event(
 				storage,
@@ -388,43 +838,144 @@ func (r *Reconciler) handleResourcesSync(
 				eventMessage+fmt.Sprintf(", changed, result: %s", result),
 			)
 		}
+		managedResources = append(managedResources, managedResource)
 	}
 
-	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StoragePreparedCondition) {
+	if err := syncErrors.Aggregate(); err != nil {
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
 			Type:    StoragePreparedCondition,
-			Status:  metav1.ConditionTrue,
-			Reason:  ReasonCompleted,
-			Message: "Successfully synced resources",
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInProgress,
+			Message: fmt.Sprintf("Failed to sync resources for generation %d: %s", storage.Generation, err),
 		})
-		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+		log.FromContext(ctx).Error(err, "Failed to sync resources")
+		storage.Status.ManagedResources = managedResources
+		return r.updateStatus(ctx, storage, DefaultRequeueDelay)
 	}
 
-	r.Log.Info("complete step handleResourcesSync")
-	return Continue, ctrl.Result{Requeue: false}, nil
-}
+	if stop, result, err := r.deleteRemovedAdditionalResources(ctx, storage); stop {
+		return stop, result, err
+	}
 
-func (r *Reconciler) syncNodeSetSpecInline(
-	ctx context.Context,
-	storage *resources.StorageClusterBuilder,
-) (bool, ctrl.Result, error) {
-	r.Log.Info("running step syncNodeSetSpecInline")
-	matchingFields := client.MatchingFields{
-		OwnerControllerField: storage.Name,
+	if !equality.Semantic.DeepEqual(storage.Status.ManagedResources, managedResources) {
+		storage.Status.ManagedResources = managedResources
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
-	storageNodeSets := &v1alpha1.StorageNodeSetList{}
-	if err := r.List(ctx, storageNodeSets,
-		client.InNamespace(storage.Namespace),
-		matchingFields,
-	); err != nil {
+	configHash := resources.SHAChecksum(storage.GetConfiguration())
+	if storage.Status.ObservedConfigHash != configHash {
 		r.Recorder.Event(
 			storage,
-			corev1.EventTypeWarning,
-			"ProvisioningFailed",
-			fmt.Sprintf("Failed to list StorageNodeSets: %s", err),
+			corev1.EventTypeNormal,
+			"ConfigurationChanged",
+			fmt.Sprintf("Rendered configuration checksum changed to %s", configHash),
 		)
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		if storage.Status.ObservedConfigHash != "" &&
+			storage.Spec.ConfigChangePolicy == v1alpha1.ConfigChangePolicyManual {
+			setConfigPendingCondition(storage, configHash)
+		}
+		storage.Status.ObservedConfigHash = configHash
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	if stop, result, err := r.checkDefineBoxDrift(ctx, storage); stop {
+		return stop, result, err
+	}
+
+	if stop, result, err := r.syncConfigPendingCondition(ctx, storage); stop {
+		return stop, result, err
+	}
+
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, ConfigInSyncCondition) {
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:               ConfigInSyncCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonCompleted,
+			Message:            "Configuration ConfigMap matches spec",
+		})
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StoragePreparedCondition) {
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    StoragePreparedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonCompleted,
+			Message: "Successfully synced resources",
+		})
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step handleResourcesSync")
+	return Continue, ctrl.Result{Requeue: false}, nil
+}
+
+// deleteRemovedAdditionalResources deletes objects which were created from
+// `spec.additionalResources` and are not supplied there anymore
+func (r *Reconciler) deleteRemovedAdditionalResources(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	removed, err := resources.GetRemovedAdditionalResources(ctx, r.Client, storage.Unwrap(), storage.Spec.AdditionalResources)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ProvisioningFailed",
+			fmt.Sprintf("Failed to list additional resources: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	for _, obj := range removed {
+		eventMessage := fmt.Sprintf(
+			"Resource: %s, Namespace: %s, Name: %s",
+			obj.GetObjectKind().GroupVersionKind().Kind,
+			obj.GetNamespace(),
+			obj.GetName(),
+		)
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ProvisioningFailed",
+				eventMessage+fmt.Sprintf(", failed to delete, error: %s", err),
+			)
+			return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+		}
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			"Syncing",
+			eventMessage+", deleted",
+		)
+	}
+
+	return Continue, ctrl.Result{}, nil
+}
+
+func (r *Reconciler) syncNodeSetSpecInline(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step syncNodeSetSpecInline")
+	matchingFields := client.MatchingFields{
+		OwnerControllerField: storage.Name,
+	}
+
+	storageNodeSets := &v1alpha1.StorageNodeSetList{}
+	if err := r.List(ctx, storageNodeSets,
+		client.InNamespace(storage.Namespace),
+		matchingFields,
+	); err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ProvisioningFailed",
+			fmt.Sprintf("Failed to list StorageNodeSets: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
 
 	for _, storageNodeSet := range storageNodeSets.Items {
@@ -521,7 +1072,7 @@ func (r *Reconciler) syncNodeSetSpecInline(
 		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
 	}
 
-	r.Log.Info("complete step syncNodeSetSpecInline")
+	log.FromContext(ctx).Info("complete step syncNodeSetSpecInline")
 	return Continue, ctrl.Result{Requeue: false}, nil
 }
 
@@ -530,7 +1081,7 @@ func (r *Reconciler) runSelfCheck(
 	storage *resources.StorageClusterBuilder,
 	waitForGoodResultWithoutIssues bool,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step runSelfCheck")
+	log.FromContext(ctx).Info("running step runSelfCheck")
 
 	creds, err := resources.GetYDBCredentials(ctx, storage.Unwrap(), r.Config)
 	if err != nil {
@@ -542,26 +1093,51 @@ func (r *Reconciler) runSelfCheck(
 		)
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
-	tlsOptions, err := resources.GetYDBTLSOption(ctx, storage.Unwrap(), r.Config)
-	if err != nil {
-		r.Recorder.Event(
-			storage,
-			corev1.EventTypeWarning,
-			"ControllerError",
-			fmt.Sprintf("Failed to get YDB TLS options: %s", err),
-		)
-		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
-	}
-
-	result, err := healthcheck.GetSelfCheckResult(ctx, storage, creds, tlsOptions)
+	result, err := r.getSelfCheckResult(ctx, storage, creds)
 	if err != nil {
-		r.Log.Error(err, "GetSelfCheckResult error")
+		log.FromContext(ctx).Error(err, "GetSelfCheckResult error")
 		return Stop, ctrl.Result{RequeueAfter: SelfCheckRequeueDelay}, err
 	}
 
 	eventType := corev1.EventTypeNormal
+	healthyCondition := metav1.Condition{
+		Type:    StorageHealthyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonCompleted,
+		Message: fmt.Sprintf("SelfCheck result: %s", result.SelfCheckResult.String()),
+	}
 	if result.SelfCheckResult != Ydb_Monitoring.SelfCheck_GOOD {
 		eventType = corev1.EventTypeWarning
+		healthyCondition.Status = metav1.ConditionFalse
+		healthyCondition.Reason = ReasonFailed
+	}
+
+	// Storage groups are unknown when SelfCheck result carries no storage
+	// status, such cluster is not reported as healthy
+	storageGroups, reported := healthcheck.CountStorageGroups(result)
+	expectedGroups := storage.GetDesiredStorageGroups()
+	enoughGroups := reported && storageGroups >= expectedGroups
+	if reported {
+		healthyCondition.Message = fmt.Sprintf(
+			"SelfCheck result: %s, storage groups: %d/%d",
+			result.SelfCheckResult.String(),
+			storageGroups,
+			expectedGroups,
+		)
+	} else {
+		healthyCondition.Message = fmt.Sprintf(
+			"SelfCheck result: %s, storage groups: unknown",
+			result.SelfCheckResult.String(),
+		)
+		if healthyCondition.Status == metav1.ConditionTrue {
+			healthyCondition.Status = metav1.ConditionUnknown
+			healthyCondition.Reason = ReasonInProgress
+		}
+	}
+	if reported && !enoughGroups {
+		eventType = corev1.EventTypeWarning
+		healthyCondition.Status = metav1.ConditionFalse
+		healthyCondition.Reason = ReasonFailed
 	}
 
 	r.Recorder.Event(
@@ -569,26 +1145,285 @@ func (r *Reconciler) runSelfCheck(
 		eventType,
 		"SelfCheck",
 		fmt.Sprintf(
-			"SelfCheck result: %s, issues found: %d",
-			result.SelfCheckResult.String(),
+			"%s, issues found: %d",
+			healthyCondition.Message,
 			len(result.IssueLog),
 		),
 	)
 
-	if waitForGoodResultWithoutIssues && result.SelfCheckResult.String() != "GOOD" {
+	if waitForGoodResultWithoutIssues && (result.SelfCheckResult.String() != "GOOD" || !enoughGroups) {
 		return Stop, ctrl.Result{RequeueAfter: SelfCheckRequeueDelay}, err
 	}
 
-	r.Log.Info("complete step runSelfCheck")
+	groupsChanged := false
+	if storage.Spec.Groups != nil && reported {
+		groups := &v1alpha1.StorageGroupsStatus{
+			Desired: int32(expectedGroups),
+			Current: int32(storageGroups),
+		}
+		groupsChanged = storage.Status.Groups == nil || *storage.Status.Groups != *groups
+		storage.Status.Groups = groups
+	}
+
+	if !meta.IsStatusConditionPresentAndEqual(storage.Status.Conditions, StorageHealthyCondition, healthyCondition.Status) || groupsChanged {
+		meta.SetStatusCondition(&storage.Status.Conditions, healthyCondition)
+		return r.updateStatus(ctx, storage, SelfCheckRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step runSelfCheck")
 	return Continue, ctrl.Result{}, nil
 }
 
+// syncStorageGroups adds storage groups to the first storage pool when
+// `spec.groups` exceeds the number of groups reported by healthcheck.
+// Groups are never removed.
+func (r *Reconciler) syncStorageGroups(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	if storage.Spec.Groups == nil || storage.Status.Groups == nil ||
+		storage.Status.Groups.Current >= storage.Status.Groups.Desired {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("running step syncStorageGroups")
+
+	stdout, err := r.execInStoragePod(ctx, storage, storage.GetReadStoragePoolsCommand())
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to read storage pools: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	pools, err := resources.ParseStoragePools(stdout)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to parse storage pools: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	if len(pools) == 0 {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			"Failed to find storage pool to add groups: no storage pools defined",
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+	}
+
+	// Groups already requested from BS controller are not requested twice,
+	// healthcheck reports them when they are created
+	definedGroups := storage.GetExpectedStorageGroups()
+	for _, pool := range pools {
+		definedGroups += pool.NumGroups
+	}
+	desiredGroups := storage.GetDesiredStorageGroups()
+	if definedGroups >= desiredGroups {
+		log.FromContext(ctx).Info("waiting for storage groups to be created", "defined", definedGroups, "desired", desiredGroups)
+		return Stop, ctrl.Result{RequeueAfter: SelfCheckRequeueDelay}, nil
+	}
+
+	pool := pools[0]
+	numGroups := pool.NumGroups + desiredGroups - definedGroups
+	stdout, err = r.execInStoragePod(ctx, storage, storage.GetDefineStoragePoolCommand(pool, numGroups))
+	if err == nil {
+		err = resources.CheckConfigInvokeResult(stdout)
+	}
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to add storage groups to pool %s: %s", pool.Name, err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeNormal,
+		"StorageGroupsAdded",
+		fmt.Sprintf("Adding %d storage groups to pool %s", numGroups-pool.NumGroups, pool.Name),
+	)
+	return Stop, ctrl.Result{RequeueAfter: SelfCheckRequeueDelay}, nil
+}
+
+func (r *Reconciler) syncNodesStatus(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step syncNodesStatus")
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList,
+		client.InNamespace(storage.Namespace),
+		client.MatchingLabels(labels.StorageLabels(storage.Unwrap())),
+	); err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to list Storage pods: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	creds, err := resources.GetYDBCredentials(ctx, storage.Unwrap(), r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB credentials: %s", err),
+		)
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+	token, err := creds.Token(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to get token for viewer request")
+		return Continue, ctrl.Result{}, nil
+	}
+	tlsConfig, err := resources.GetStatusServiceTLSConfig(ctx, storage.Unwrap(), r.Config)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to get status service TLS config")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	viewerClient := viewer.NewClient(storage.GetStatusServiceEndpointWithProto(), token, tlsConfig)
+	defer viewerClient.Close()
+
+	nodesInfo, err := viewerClient.GetNodesInfo(ctx)
+	if err != nil {
+		// Nodes status is informational only, do not block reconcile
+		log.FromContext(ctx).Error(err, "failed to get nodes info from viewer")
+		return Continue, ctrl.Result{}, nil
+	}
+
+	var nodes []v1alpha1.StorageNodeStatus
+	var versions []string
+	for _, pod := range podList.Items {
+		if nodeInfo, found := viewer.FindNodeByPodName(nodesInfo, pod.Name); found {
+			nodes = append(nodes, v1alpha1.StorageNodeStatus{
+				PodName:        pod.Name,
+				NodeID:         nodeInfo.NodeID,
+				State:          nodeInfo.SystemState,
+				RunningVersion: nodeInfo.Version,
+			})
+			versions = append(versions, nodeInfo.Version)
+		} else {
+			// Node of the Pod is not known to viewer yet
+			versions = append(versions, "")
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].PodName < nodes[j].PodName
+	})
+	version := viewer.SummarizeVersions(versions)
+
+	tenantCapacity := storage.Status.TenantCapacity
+	if capacity, err := getTenantCapacity(ctx, viewerClient); err != nil {
+		log.FromContext(ctx).Error(err, "failed to get tenant capacity from viewer")
+	} else {
+		tenantCapacity = capacity
+	}
+
+	if !reflect.DeepEqual(storage.Status.Nodes, nodes) || storage.Status.Version != version ||
+		!reflect.DeepEqual(storage.Status.TenantCapacity, tenantCapacity) {
+		storage.Status.Nodes = nodes
+		storage.Status.Version = version
+		storage.Status.TenantCapacity = tenantCapacity
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	log.FromContext(ctx).Info("complete step syncNodesStatus")
+	return Continue, ctrl.Result{}, nil
+}
+
+// getTenantCapacity correlates storage groups of the cluster with storage
+// groups allocated to tenants. Every storage unit of a tenant is backed by
+// a storage group, so each free group can host a tenant of one unit.
+func getTenantCapacity(ctx context.Context, viewerClient *viewer.Client) (*v1alpha1.StorageTenantCapacity, error) {
+	storageGroups, err := viewerClient.GetStorageGroupsCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenants, err := viewerClient.GetTenantsInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count, allocatedGroups := viewer.SummarizeTenants(tenants)
+	var available uint64
+	if storageGroups > allocatedGroups {
+		available = storageGroups - allocatedGroups
+	}
+
+	return &v1alpha1.StorageTenantCapacity{
+		Tenants:                int32(count),
+		StorageGroups:          int32(storageGroups),
+		AllocatedStorageGroups: int32(allocatedGroups),
+		Available:              int32(available),
+	}, nil
+}
+
+func (r *Reconciler) getSelfCheckResult(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+	creds ydbCredentials.Credentials,
+) (*Ydb_Monitoring.SelfCheckResult, error) {
+	ctx, span := tracing.Start(ctx, "getSelfCheckResult")
+	defer span.End()
+
+	if storage.Spec.HealthCheck != nil && storage.Spec.HealthCheck.Mechanism == v1alpha1.HealthCheckHTTP {
+		tlsConfig, err := resources.GetStatusServiceTLSConfig(ctx, storage.Unwrap(), r.Config)
+		if err != nil {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"ControllerError",
+				fmt.Sprintf("Failed to get status service TLS config: %s", err),
+			)
+			return nil, err
+		}
+		return healthcheck.GetSelfCheckResultHTTP(ctx, healthcheck.HTTPTarget{
+			Endpoint:    storage.GetHealthCheckEndpoint(),
+			Path:        storage.GetHealthCheckHTTPPath(),
+			Credentials: creds,
+			TLSConfig:   tlsConfig,
+		})
+	}
+
+	tlsOptions, err := resources.GetYDBTLSOption(ctx, storage.Unwrap(), r.Config)
+	if err != nil {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeWarning,
+			"ControllerError",
+			fmt.Sprintf("Failed to get YDB TLS options: %s", err),
+		)
+		return nil, err
+	}
+	return healthcheck.GetSelfCheckResult(ctx, healthcheck.Target{
+		Endpoint:    storage.GetHealthCheckEndpoint(),
+		Domain:      storage.Spec.Domain,
+		Credentials: creds,
+		Options:     []ydb.Option{tlsOptions, resources.GetYDBKeepaliveOption(storage.Unwrap())},
+	})
+}
+
 func (r *Reconciler) updateStatus(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 	requeueAfter time.Duration,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running updateStatus handler")
+	log.FromContext(ctx).Info("running updateStatus handler")
 
 	if meta.IsStatusConditionFalse(storage.Status.Conditions, StoragePreparedCondition) ||
 		meta.IsStatusConditionFalse(storage.Status.Conditions, StorageInitializedCondition) ||
@@ -608,6 +1443,8 @@ func (r *Reconciler) updateStatus(
 		}
 	}
 
+	setReadyCondition(storage)
+
 	storageCr := &v1alpha1.Storage{}
 	err := r.Get(ctx, types.NamespacedName{
 		Namespace: storage.Namespace,
@@ -626,7 +1463,23 @@ func (r *Reconciler) updateStatus(
 	oldStatus := storageCr.Status.State
 	storageCr.Status.State = storage.Status.State
 	storageCr.Status.Conditions = storage.Status.Conditions
-	if err = r.Status().Update(ctx, storageCr); err != nil {
+	storageCr.Status.ObservedGeneration = storage.Status.ObservedGeneration
+	storageCr.Status.ObservedConfigHash = storage.Status.ObservedConfigHash
+	storageCr.Status.ManagedResources = storage.Status.ManagedResources
+	storageCr.Status.InitializedBoxHash = storage.Status.InitializedBoxHash
+	storageCr.Status.Groups = storage.Status.Groups
+	storageCr.Status.AppliedBSConfig = storage.Status.AppliedBSConfig
+	storageCr.Status.UpdatePartition = storage.Status.UpdatePartition
+	storageCr.Status.Nodes = storage.Status.Nodes
+	storageCr.Status.PreviousImage = storage.Status.PreviousImage
+	storageCr.Status.MaintenanceTask = storage.Status.MaintenanceTask
+	storageCr.Status.Version = storage.Status.Version
+	storageCr.Status.TenantCapacity = storage.Status.TenantCapacity
+	err = r.Status().Update(ctx, storageCr)
+	if result, conflict := requeue.OnConflict(log.FromContext(ctx), err); conflict {
+		return Stop, result, nil
+	}
+	if err != nil {
 		r.Recorder.Event(
 			storage,
 			corev1.EventTypeWarning,
@@ -635,6 +1488,8 @@ func (r *Reconciler) updateStatus(
 		)
 		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
 	}
+	// Readiness is reported as soon as it is changed, not on the next reconcile
+	metrics.SetClusterReady(StorageKind, storageCr.Namespace, storageCr.Name, meta.IsStatusConditionTrue(storageCr.Status.Conditions, StorageReadyCondition))
 	if oldStatus != storage.Status.State {
 		r.Recorder.Event(
 			storage,
@@ -644,15 +1499,213 @@ func (r *Reconciler) updateStatus(
 		)
 	}
 
-	r.Log.Info("complete updateStatus handler")
-	return Stop, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	log.FromContext(ctx).Info("complete updateStatus handler")
+	return Stop, ctrl.Result{RequeueAfter: requeue.WithJitter(requeueAfter)}, nil
+}
+
+// checkDefineBoxDrift reports changes of the box definition made after
+// blobstorage initialization. They are never applied automatically, since
+// reinitialization or reconfiguration of blobstorage is destructive
+func (r *Reconciler) checkDefineBoxDrift(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	boxHash := storage.GetDefineBoxHash()
+	if boxHash == "" {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	// Storage initialized before the box definition was tracked
+	if storage.Status.InitializedBoxHash == "" {
+		storage.Status.InitializedBoxHash = boxHash
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	if storage.Status.InitializedBoxHash != boxHash {
+		if !meta.IsStatusConditionFalse(storage.Status.Conditions, DefineBoxSyncedCondition) {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				ReasonConfigDrift,
+				"DefineBox of the configuration differs from the one Storage was initialized with, "+
+					"reinitialization or reconfiguration of blobstorage is required",
+			)
+			meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+				Type:               DefineBoxSyncedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: storage.Generation,
+				Reason:             ReasonConfigDrift,
+				Message:            fmt.Sprintf("DefineBox checksum %s differs from initialized %s", boxHash, storage.Status.InitializedBoxHash),
+			})
+			return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+		}
+		return Continue, ctrl.Result{}, nil
+	}
+
+	if !meta.IsStatusConditionTrue(storage.Status.Conditions, DefineBoxSyncedCondition) {
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:               DefineBoxSyncedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonCompleted,
+			Message:            "DefineBox matches the initialized one",
+		})
+		return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+	}
+
+	return Continue, ctrl.Result{}, nil
+}
+
+// checkImageVersion refuses to sync resources, and so to roll out the image,
+// while YDB version of the image is older than supported by the operator
+func (r *Reconciler) checkImageVersion(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	// Reported once per generation, not on every reconcile
+	if storage.Spec.Image.HasDigest() && storage.Spec.YDBVersion != "" {
+		if r.versionIgnoredWarnings.ShouldReport(storage.UID, strconv.FormatInt(storage.Generation, 10)) {
+			r.Recorder.Event(
+				storage,
+				corev1.EventTypeWarning,
+				"VersionIgnored",
+				fmt.Sprintf("Image %s is pinned by digest, version %s is ignored", storage.Spec.Image.Name, storage.Spec.YDBVersion),
+			)
+		}
+	} else {
+		r.versionIgnoredWarnings.Forget(storage.UID)
+	}
+
+	err := v1alpha1.CheckYDBVersion(storage.Spec.Image, storage.Spec.YDBVersion)
+	if err == nil {
+		if meta.FindStatusCondition(storage.Status.Conditions, UnsupportedVersionCondition) != nil {
+			meta.RemoveStatusCondition(&storage.Status.Conditions, UnsupportedVersionCondition)
+			return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+		}
+		return Continue, ctrl.Result{}, nil
+	}
+
+	condition := meta.FindStatusCondition(storage.Status.Conditions, UnsupportedVersionCondition)
+	if condition != nil && condition.ObservedGeneration == storage.Generation {
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, nil
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeWarning,
+		UnsupportedVersionCondition,
+		fmt.Sprintf("Image is not rolled out: %s", err),
+	)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               UnsupportedVersionCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonFailed,
+		Message:            err.Error(),
+	})
+	return r.updateStatus(ctx, storage, DefaultRequeueDelay)
+}
+
+// checkConfigDrift detects out-of-band edits of the configuration ConfigMap.
+// Drifted ConfigMap is reported and then re-applied by resources sync.
+func (r *Reconciler) checkConfigDrift(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (bool, ctrl.Result, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      storage.Name,
+		Namespace: storage.Namespace,
+	}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return Continue, ctrl.Result{}, nil
+		}
+		log.FromContext(ctx).Error(err, "Failed to get configuration ConfigMap")
+		return Stop, ctrl.Result{RequeueAfter: DefaultRequeueDelay}, err
+	}
+
+	if !resources.IsConfigMapDrifted(configMap) ||
+		meta.IsStatusConditionFalse(storage.Status.Conditions, ConfigInSyncCondition) {
+		return Continue, ctrl.Result{}, nil
+	}
+
+	r.Recorder.Event(
+		storage,
+		corev1.EventTypeWarning,
+		ReasonConfigDrift,
+		fmt.Sprintf("ConfigMap %s was changed out of band, configuration of spec is re-applied", configMap.Name),
+	)
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               ConfigInSyncCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonConfigDrift,
+		Message:            fmt.Sprintf("ConfigMap %s does not match configuration of spec", configMap.Name),
+	})
+	return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+}
+
+// setReadyCondition sets the aggregated Ready condition: Storage is Ready
+// when it is initialized, all pods are scaled and healthcheck is GOOD.
+func setReadyCondition(storage *resources.StorageClusterBuilder) {
+	if storage.Spec.Pause {
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:               ReadyCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonNotRequired,
+			Message:            "Storage is paused",
+		})
+		return
+	}
+
+	for _, conditionType := range []string{
+		StorageInitializedCondition,
+		StorageProvisionedCondition,
+		StorageReadyCondition,
+		StorageHealthyCondition,
+	} {
+		if !meta.IsStatusConditionTrue(storage.Status.Conditions, conditionType) {
+			meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+				Type:               ReadyCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: storage.Generation,
+				Reason:             ReasonInProgress,
+				Message:            fmt.Sprintf("Waiting for condition %s", conditionType),
+			})
+			return
+		}
+	}
+
+	if storage.Status.ObservedGeneration != storage.Generation {
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:               ReadyCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: storage.Generation,
+			Reason:             ReasonInProgress,
+			Message:            fmt.Sprintf("Waiting for generation %d to be reconciled", storage.Generation),
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: storage.Generation,
+		Reason:             ReasonCompleted,
+		Message:            "Storage is ready",
+	})
 }
 
 func (r *Reconciler) handlePauseResume(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (bool, ctrl.Result, error) {
-	r.Log.Info("running step handlePauseResume")
+	log.FromContext(ctx).Info("running step handlePauseResume")
 
 	if storage.Status.State == StorageProvisioning {
 		if storage.Spec.Pause {
@@ -674,7 +1727,7 @@ func (r *Reconciler) handlePauseResume(
 	}
 
 	if storage.Status.State == StorageReady && storage.Spec.Pause {
-		r.Log.Info("`pause: true` was noticed, moving Storage to state `Paused`")
+		log.FromContext(ctx).Info("`pause: true` was noticed, moving Storage to state `Paused`")
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
 			Type:    StorageReadyCondition,
 			Status:  metav1.ConditionFalse,
@@ -686,7 +1739,21 @@ func (r *Reconciler) handlePauseResume(
 	}
 
 	if storage.Status.State == StoragePaused && !storage.Spec.Pause {
-		r.Log.Info("`pause: false` was noticed, moving Storage to state `Ready`")
+		// Storage paused before its initialization was completed resumes
+		// the initialization rather than becomes Ready
+		if !meta.IsStatusConditionTrue(storage.Status.Conditions, StorageInitializedCondition) {
+			log.FromContext(ctx).Info("`pause: false` was noticed, moving Storage to state `Initializing`")
+			meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+				Type:    StoragePausedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonNotRequired,
+				Message: "Transitioning to state Initializing",
+			})
+			storage.Status.State = StorageInitializing
+			return r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+		}
+
+		log.FromContext(ctx).Info("`pause: false` was noticed, moving Storage to state `Ready`")
 		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
 			Type:    StoragePausedCondition,
 			Status:  metav1.ConditionFalse,
@@ -717,15 +1784,53 @@ func (r *Reconciler) handlePauseResume(
 		}
 	}
 
-	r.Log.Info("complete step handlePauseResume")
+	log.FromContext(ctx).Info("complete step handlePauseResume")
 	return Continue, ctrl.Result{}, nil
 }
 
+// handleFreeze is the only step of reconcile for Storage paused with
+// `pauseMode: Freeze`: resources of the Storage are not modified and the
+// Storage Pods keep running until it is resumed.
+func (r *Reconciler) handleFreeze(
+	ctx context.Context,
+	storage *resources.StorageClusterBuilder,
+) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("running step handleFreeze")
+
+	if storage.Status.State != StoragePaused ||
+		!meta.IsStatusConditionTrue(storage.Status.Conditions, StoragePausedCondition) {
+		r.Recorder.Event(
+			storage,
+			corev1.EventTypeNormal,
+			"Paused",
+			"Storage is frozen with `pauseMode: Freeze`, resources are not modified until it is resumed",
+		)
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    StorageReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNotRequired,
+			Message: "Transitioning to state Paused",
+		})
+		meta.SetStatusCondition(&storage.Status.Conditions, metav1.Condition{
+			Type:    StoragePausedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonCompleted,
+			Message: "Storage Pods keep running, resources are not modified",
+		})
+		storage.Status.State = StoragePaused
+		_, result, err := r.updateStatus(ctx, storage, StatusUpdateRequeueDelay)
+		return result, err
+	}
+
+	log.FromContext(ctx).Info("complete step handleFreeze")
+	return ctrl.Result{}, nil
+}
+
 func (r *Reconciler) handleBlobstorageInit(
 	ctx context.Context,
 	storage *resources.StorageClusterBuilder,
 ) (ctrl.Result, error) {
-	r.Log.Info("running step handleBlobstorageInit")
+	log.FromContext(ctx).Info("running step handleBlobstorageInit")
 
 	stop, result, err := r.setInitPipelineStatus(ctx, storage)
 	if stop {
@@ -737,6 +1842,6 @@ func (r *Reconciler) handleBlobstorageInit(
 		return result, err
 	}
 
-	r.Log.Info("complete step handleBlobstorageInit")
+	log.FromContext(ctx).Info("complete step handleBlobstorageInit")
 	return ctrl.Result{}, nil
 }
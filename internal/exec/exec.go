@@ -11,9 +11,18 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 )
 
+// IsExitError reports whether command was executed in pod and exited with
+// non-zero code, unlike failures to execute the command at all
+func IsExitError(err error) bool {
+	var exitErr utilexec.ExitError
+	return errors.As(err, &exitErr)
+}
+
 func InPod(
+	ctx context.Context,
 	scheme *runtime.Scheme,
 	config *rest.Config,
 	namespace, name, container string,
@@ -47,7 +56,7 @@ func InPod(
 
 	var stdout, stderr bytes.Buffer
 	err = exec.StreamWithContext(
-		context.TODO(),
+		ctx,
 		remotecommand.StreamOptions{
 			Stdin:  nil,
 			Stdout: &stdout,
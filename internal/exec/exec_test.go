@@ -0,0 +1,34 @@
+package exec_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/exec"
+)
+
+func TestExec(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Exec suite")
+}
+
+var _ = Describe("Exec errors", func() {
+	exitErr := utilexec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1}
+
+	It("detects non-zero exit code of the command", func() {
+		Expect(exec.IsExitError(exitErr)).To(BeTrue())
+		Expect(exec.IsExitError(pkgerrors.Wrapf(exitErr, "failed to stream execution results back"))).To(BeTrue())
+		Expect(exec.IsExitError(errors.Join(errors.New("pod storage-0: unavailable"), fmt.Errorf("pod storage-1: %w", exitErr)))).To(BeTrue())
+	})
+
+	It("does not treat failure to execute the command as exit code", func() {
+		Expect(exec.IsExitError(nil)).To(BeFalse())
+		Expect(exec.IsExitError(pkgerrors.Wrapf(errors.New("connection refused"), "failed to initialize SPDY executor"))).To(BeFalse())
+	})
+})
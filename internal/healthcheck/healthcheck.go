@@ -2,6 +2,7 @@ package healthcheck
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 
 	"github.com/ydb-platform/ydb-go-genproto/Ydb_Monitoring_V1"
@@ -12,26 +13,41 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/connection"
-	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/viewer"
 )
 
+// Target is the endpoint to request SelfCheck from via grpc
+type Target struct {
+	// Endpoint with scheme, e.g. grpcs://storage-grpc.ydb.svc.cluster.local:2135
+	Endpoint string
+	// Domain is the root storage domain, e.g. Root
+	Domain      string
+	Credentials ydbCredentials.Credentials
+	// Options are additional options of connection, e.g. TLS
+	Options []ydb.Option
+}
+
+// HTTPTarget is the status service endpoint to request SelfCheck from
+type HTTPTarget struct {
+	// Endpoint with scheme, e.g. https://storage-status.ydb.svc.cluster.local:8765
+	Endpoint string
+	// Path of the JSON viewer healthcheck handler
+	Path        string
+	Credentials ydbCredentials.Credentials
+	TLSConfig   *tls.Config
+}
+
 func GetSelfCheckResult(
 	ctx context.Context,
-	cluster *resources.StorageClusterBuilder,
-	creds ydbCredentials.Credentials,
-	opts ...ydb.Option,
+	target Target,
 ) (*Ydb_Monitoring.SelfCheckResult, error) {
 	logger := log.FromContext(ctx)
-	getSelfCheckURL := fmt.Sprintf(
-		"%s/%s",
-		cluster.GetStorageEndpointWithProto(),
-		cluster.Storage.Spec.Domain,
-	)
+	getSelfCheckURL := fmt.Sprintf("%s/%s", target.Endpoint, target.Domain)
 
 	db, err := connection.Open(ctx,
 		getSelfCheckURL,
-		ydb.WithCredentials(creds),
-		ydb.MergeOptions(opts...),
+		ydb.WithCredentials(target.Credentials),
+		ydb.MergeOptions(target.Options...),
 	)
 	if err != nil {
 		return nil, err
@@ -41,17 +57,59 @@ func GetSelfCheckResult(
 	}()
 
 	client := Ydb_Monitoring_V1.NewMonitoringServiceClient(ydb.GRPCConn(db))
-	response, err := client.SelfCheck(ctx, &Ydb_Monitoring.SelfCheckRequest{})
+	response, err := client.SelfCheck(ctx, &Ydb_Monitoring.SelfCheckRequest{
+		ReturnVerboseStatus: true,
+	})
 	if err != nil {
 		logger.Error(err, "Failed to call SelfCheck")
 		return nil, err
 	}
 
 	result := &Ydb_Monitoring.SelfCheckResult{}
-	if err = proto.Unmarshal(response.Operation.Result.GetValue(), result); err != nil {
+	if err = proto.Unmarshal(response.GetOperation().GetResult().GetValue(), result); err != nil {
 		logger.Error(err, "Failed to unmarshal SelfCheck response")
 		return result, err
 	}
 
 	return result, nil
 }
+
+// GetSelfCheckResultHTTP requests healthcheck from the status service of
+// Storage, for the case when grpc endpoint is not reachable by operator.
+func GetSelfCheckResultHTTP(
+	ctx context.Context,
+	target HTTPTarget,
+) (*Ydb_Monitoring.SelfCheckResult, error) {
+	logger := log.FromContext(ctx)
+
+	token, err := target.Credentials.Token(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to get token for SelfCheck")
+		return nil, err
+	}
+
+	viewerClient := viewer.NewClient(target.Endpoint, token, target.TLSConfig)
+	defer viewerClient.Close()
+
+	return viewerClient.GetSelfCheckResult(ctx, target.Path)
+}
+
+// CountStorageGroups returns the number of distinct storage groups
+// reported in the verbose status of SelfCheck result. The second value
+// is false when result carries no storage status to count groups from.
+func CountStorageGroups(result *Ydb_Monitoring.SelfCheckResult) (int, bool) {
+	groups := make(map[string]struct{})
+	reported := false
+	for _, database := range result.GetDatabaseStatus() {
+		if database.GetStorage() == nil {
+			continue
+		}
+		reported = true
+		for _, pool := range database.GetStorage().GetPools() {
+			for _, group := range pool.GetGroups() {
+				groups[group.GetId()] = struct{}{}
+			}
+		}
+	}
+	return len(groups), reported
+}
@@ -0,0 +1,119 @@
+package healthcheck_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ydb-platform/ydb-go-genproto/Ydb_Monitoring_V1"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Operations"
+	ydb "github.com/ydb-platform/ydb-go-sdk/v3"
+	"github.com/ydb-platform/ydb-go-sdk/v3/balancers"
+	ydbCredentials "github.com/ydb-platform/ydb-go-sdk/v3/credentials"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+)
+
+func TestHealthCheck(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HealthCheck suite")
+}
+
+type monitoringServer struct {
+	Ydb_Monitoring_V1.UnimplementedMonitoringServiceServer
+}
+
+func (s *monitoringServer) SelfCheck(
+	context.Context,
+	*Ydb_Monitoring.SelfCheckRequest,
+) (*Ydb_Monitoring.SelfCheckResponse, error) {
+	result, err := anypb.New(&Ydb_Monitoring.SelfCheckResult{
+		SelfCheckResult: Ydb_Monitoring.SelfCheck_GOOD,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Ydb_Monitoring.SelfCheckResponse{
+		Operation: &Ydb_Operations.Operation{
+			Ready:  true,
+			Status: Ydb.StatusIds_SUCCESS,
+			Result: result,
+		},
+	}, nil
+}
+
+var _ = Describe("Testing healthcheck", func() {
+	It("Get SelfCheck result from explicit grpc endpoint", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ShouldNot(HaveOccurred())
+		server := grpc.NewServer()
+		Ydb_Monitoring_V1.RegisterMonitoringServiceServer(server, &monitoringServer{})
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		defer server.Stop()
+
+		result, err := healthcheck.GetSelfCheckResult(context.Background(), healthcheck.Target{
+			Endpoint:    "grpc://" + listener.Addr().String(),
+			Domain:      "Root",
+			Credentials: ydbCredentials.NewAnonymousCredentials(),
+			Options:     []ydb.Option{ydb.WithBalancer(balancers.SingleConn())},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.GetSelfCheckResult()).Should(Equal(Ydb_Monitoring.SelfCheck_GOOD))
+	})
+
+	It("Get SelfCheck result from explicit status endpoint", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/viewer/json/healthcheck" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"self_check_result":"DEGRADED"}`))
+		}))
+		defer server.Close()
+
+		result, err := healthcheck.GetSelfCheckResultHTTP(context.Background(), healthcheck.HTTPTarget{
+			Endpoint:    server.URL,
+			Path:        "/viewer/json/healthcheck",
+			Credentials: ydbCredentials.NewAnonymousCredentials(),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.GetSelfCheckResult()).Should(Equal(Ydb_Monitoring.SelfCheck_DEGRADED))
+	})
+})
+
+var _ = Describe("Counting storage groups", func() {
+	It("counts distinct groups of all storage pools", func() {
+		result := &Ydb_Monitoring.SelfCheckResult{
+			DatabaseStatus: []*Ydb_Monitoring.DatabaseStatus{{
+				Storage: &Ydb_Monitoring.StorageStatus{
+					Pools: []*Ydb_Monitoring.StoragePoolStatus{
+						{Groups: []*Ydb_Monitoring.StorageGroupStatus{{Id: "1"}, {Id: "2"}}},
+						{Groups: []*Ydb_Monitoring.StorageGroupStatus{{Id: "2"}, {Id: "3"}}},
+					},
+				},
+			}},
+		}
+		groups, reported := healthcheck.CountStorageGroups(result)
+		Expect(reported).To(BeTrue())
+		Expect(groups).To(Equal(3))
+	})
+
+	It("reports unknown groups without storage status", func() {
+		result := &Ydb_Monitoring.SelfCheckResult{
+			SelfCheckResult: Ydb_Monitoring.SelfCheck_GOOD,
+			DatabaseStatus:  []*Ydb_Monitoring.DatabaseStatus{{}},
+		}
+		_, reported := healthcheck.CountStorageGroups(result)
+		Expect(reported).To(BeFalse())
+	})
+})
@@ -27,6 +27,8 @@ const (
 	DatabaseNodeSetComponent = "ydb.tech/database-nodeset"
 	// RemoteClusterKey The specialization of a remote k8s cluster
 	RemoteClusterKey = "ydb.tech/remote-cluster"
+	// AdditionalResourceKey Marks objects supplied in `spec.additionalResources`
+	AdditionalResourceKey = "ydb.tech/additional-resource"
 
 	StorageGeneration  = "ydb.tech/storage-generation"
 	DatabaseGeneration = "ydb.tech/database-generation"
@@ -38,6 +40,7 @@ const (
 	InterconnectComponent = "interconnect"
 	StatusComponent       = "status"
 	DatastreamsComponent  = "datastreams"
+	PostgresComponent     = "postgres"
 )
 
 type Labels map[string]string
@@ -72,6 +75,29 @@ func DatabaseLabels(database *v1alpha1.Database) Labels {
 	return l
 }
 
+// StorageSelectorLabels are labels of Storage pods which do not depend on
+// user-defined labels. Services select pods by them, so that editing labels
+// of Storage does not leave Services without endpoints until pods are
+// recreated with new labels.
+func StorageSelectorLabels(cluster *v1alpha1.Storage) Labels {
+	return selectorLabels(cluster.Name, StorageComponent)
+}
+
+// DatabaseSelectorLabels are labels of Database pods which do not depend
+// on user-defined labels, see StorageSelectorLabels
+func DatabaseSelectorLabels(database *v1alpha1.Database) Labels {
+	return selectorLabels(database.Name, DynamicComponent)
+}
+
+func selectorLabels(instance, component string) Labels {
+	return Labels{
+		NameKey:      "ydb",
+		InstanceKey:  instance,
+		ManagedByKey: "ydb-operator",
+		ComponentKey: component,
+	}
+}
+
 func (l Labels) AsMap() map[string]string {
 	return l
 }
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// clusterReady reports readiness of every managed Storage and Database,
+// it is served by the operator together with controller-runtime metrics
+var clusterReady = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "ydb_cluster_ready",
+		Help: "Whether the managed cluster resource is Ready (1) or not (0)",
+	},
+	[]string{"name", "namespace", "kind"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(clusterReady)
+}
+
+// SetClusterReady sets readiness of the cluster resource
+func SetClusterReady(kind, namespace, name string, ready bool) {
+	value := 0.0
+	if ready {
+		value = 1
+	}
+	clusterReady.WithLabelValues(name, namespace, kind).Set(value)
+}
+
+// DeleteClusterReady removes readiness series of the deleted cluster
+// resource, so that it is not reported as not ready forever
+func DeleteClusterReady(kind, namespace, name string) {
+	clusterReady.DeleteLabelValues(name, namespace, kind)
+}
@@ -0,0 +1,74 @@
+package metrics_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metrics suite")
+}
+
+// clusterReady returns value of ydb_cluster_ready series of the resource
+// served by the operator, false if the series is not reported
+func clusterReady(kind, namespace, name string) (float64, bool) {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).ShouldNot(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "ydb_cluster_ready" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["kind"] == kind && labels["namespace"] == namespace && labels["name"] == name {
+				return metric.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+var _ = Describe("Cluster readiness", func() {
+	It("reports readiness of cluster resource", func() {
+		metrics.SetClusterReady("Storage", "ydb", "storage", false)
+		value, ok := clusterReady("Storage", "ydb", "storage")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeZero())
+
+		metrics.SetClusterReady("Storage", "ydb", "storage", true)
+		value, ok = clusterReady("Storage", "ydb", "storage")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(1.0))
+	})
+
+	It("keeps series of resources apart", func() {
+		metrics.SetClusterReady("Storage", "ydb", "cluster", true)
+		metrics.SetClusterReady("Database", "ydb", "cluster", false)
+
+		value, _ := clusterReady("Storage", "ydb", "cluster")
+		Expect(value).To(Equal(1.0))
+		value, _ = clusterReady("Database", "ydb", "cluster")
+		Expect(value).To(BeZero())
+	})
+
+	It("removes series of deleted resource", func() {
+		metrics.SetClusterReady("Database", "ydb", "database", true)
+		metrics.DeleteClusterReady("Database", "ydb", "database")
+
+		_, ok := clusterReady("Database", "ydb", "database")
+		Expect(ok).To(BeFalse())
+
+		// Deleting series which is not reported is a no-op
+		metrics.DeleteClusterReady("Database", "ydb", "database")
+	})
+})
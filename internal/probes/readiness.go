@@ -0,0 +1,67 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+var (
+	ErrCachesNotSynced = errors.New("informer caches are not synced yet")
+	ErrNotLeader       = errors.New("operator replica is not the elected leader")
+)
+
+// CacheSyncer is implemented by the cache of controller manager
+type CacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// Readiness is a ready check of the operator replica, which passes once
+// informer caches are synced and, when required, leader election is won.
+// It runs on every replica, not only on the leader.
+type Readiness struct {
+	cache   CacheSyncer
+	elected <-chan struct{}
+
+	synced atomic.Bool
+}
+
+// NewReadiness returns ready check waiting for caches, elected is closed
+// when leader election is won and nil when leadership is not required
+func NewReadiness(cache CacheSyncer, elected <-chan struct{}) *Readiness {
+	return &Readiness{
+		cache:   cache,
+		elected: elected,
+	}
+}
+
+// Start waits for caches to be synced, it implements manager.Runnable
+func (r *Readiness) Start(ctx context.Context) error {
+	if r.cache.WaitForCacheSync(ctx) {
+		r.synced.Store(true)
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that
+// standby replicas report their readiness too
+func (r *Readiness) NeedLeaderElection() bool {
+	return false
+}
+
+// Check implements healthz.Checker
+func (r *Readiness) Check(_ *http.Request) error {
+	if !r.synced.Load() {
+		return ErrCachesNotSynced
+	}
+	if r.elected == nil {
+		return nil
+	}
+	select {
+	case <-r.elected:
+		return nil
+	default:
+		return ErrNotLeader
+	}
+}
@@ -0,0 +1,46 @@
+package probes_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/probes"
+)
+
+func TestProbes(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Probes suite")
+}
+
+type fakeCache bool
+
+func (c fakeCache) WaitForCacheSync(context.Context) bool {
+	return bool(c)
+}
+
+var _ = Describe("Readiness", func() {
+	It("is not ready until caches are synced", func() {
+		readiness := probes.NewReadiness(fakeCache(false), nil)
+		Expect(readiness.Start(context.Background())).To(Succeed())
+		Expect(readiness.Check(nil)).To(MatchError(probes.ErrCachesNotSynced))
+	})
+
+	It("is ready with synced caches when leadership is not required", func() {
+		readiness := probes.NewReadiness(fakeCache(true), nil)
+		Expect(readiness.Start(context.Background())).To(Succeed())
+		Expect(readiness.Check(nil)).To(Succeed())
+	})
+
+	It("is ready only after leader election is won", func() {
+		elected := make(chan struct{})
+		readiness := probes.NewReadiness(fakeCache(true), elected)
+		Expect(readiness.Start(context.Background())).To(Succeed())
+		Expect(readiness.Check(nil)).To(MatchError(probes.ErrNotLeader))
+
+		close(elected)
+		Expect(readiness.Check(nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,123 @@
+package requeue
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+// JitterFactor is the maximum relative deviation applied to requeue delays.
+const JitterFactor = 0.1
+
+// WithJitter returns delay randomized by up to ±JitterFactor, so that
+// many objects requeued with the same delay do not wake up simultaneously.
+func WithJitter(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return delay
+	}
+
+	deviation := (rand.Float64()*2 - 1) * JitterFactor * float64(delay)
+	return delay + time.Duration(deviation)
+}
+
+// Backoff returns delay growing exponentially with time elapsed since
+// waiting has started, bounded by minDelay and maxDelay. Requeueing after
+// the returned delay doubles the time waited so far.
+func Backoff(elapsed, minDelay, maxDelay time.Duration) time.Duration {
+	if elapsed < minDelay {
+		return minDelay
+	}
+	if elapsed > maxDelay {
+		return maxDelay
+	}
+	return elapsed
+}
+
+// DelayFromAnnotations returns requeue delay requested by
+// AnnotationRequeueDelay, ok is false when annotation is not set.
+func DelayFromAnnotations(annotations map[string]string) (time.Duration, bool, error) {
+	return v1alpha1.ParseDurationAnnotation(annotations, v1alpha1.AnnotationRequeueDelay)
+}
+
+// OverrideSteadyDelay returns delay randomized by WithJitter instead of
+// requeueAfter when the latter is one of steadyDelays, possibly randomized
+// by WithJitter as well. Other delays, e.g. of waiting for status update
+// or for an operation to finish, are kept.
+func OverrideSteadyDelay(requeueAfter, delay time.Duration, steadyDelays ...time.Duration) time.Duration {
+	for _, steadyDelay := range steadyDelays {
+		deviation := time.Duration(JitterFactor * float64(steadyDelay))
+		if requeueAfter >= steadyDelay-deviation && requeueAfter <= steadyDelay+deviation {
+			return WithJitter(delay)
+		}
+	}
+	return requeueAfter
+}
+
+// Warnings remembers invalid values of AnnotationRequeueDelay, or other
+// values warned about, already reported for objects, so that a warning is
+// emitted once per value rather than on every reconcile. Zero value is
+// ready to use.
+type Warnings struct {
+	reported sync.Map
+}
+
+// ShouldReport records invalid value of the object and reports
+// whether it differs from the one reported before.
+func (w *Warnings) ShouldReport(uid types.UID, value string) bool {
+	previous, loaded := w.reported.Swap(uid, value)
+	return !loaded || previous.(string) != value
+}
+
+// Forget is called when value of the object is valid again, so that
+// the next invalid value is reported.
+func (w *Warnings) Forget(uid types.UID) {
+	w.reported.Delete(uid)
+}
+
+// TransientRetryBackoff bounds in-process retries of API requests which
+// failed with transient errors, before the object is requeued
+var TransientRetryBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: 200 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   JitterFactor,
+}
+
+// OnConflict returns immediate requeue when update of object failed because
+// it was changed after it was fetched, so that the update is retried with
+// the fresh object instead of reporting the error
+func OnConflict(logger logr.Logger, err error) (ctrl.Result, bool) {
+	if !apierrors.IsConflict(err) {
+		return ctrl.Result{}, false
+	}
+	logger.Info("conflict on status update, requeue", "reason", err.Error())
+	return ctrl.Result{Requeue: true}, true
+}
+
+// IsTransient reports whether API request failed because of a temporary
+// problem of API server or network, and is likely to succeed when retried
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
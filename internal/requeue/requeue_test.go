@@ -0,0 +1,126 @@
+package requeue_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/requeue"
+)
+
+func TestRequeue(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Requeue suite")
+}
+
+var _ = Describe("Requeue delay annotation", func() {
+	It("is not set without annotation", func() {
+		_, ok, err := requeue.DelayFromAnnotations(map[string]string{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("parses positive duration", func() {
+		delay, ok, err := requeue.DelayFromAnnotations(map[string]string{
+			v1alpha1.AnnotationRequeueDelay: "2m",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(2 * time.Minute))
+	})
+
+	It("rejects invalid and non-positive duration", func() {
+		for _, value := range []string{"often", "0s", "-5s"} {
+			_, ok, err := requeue.DelayFromAnnotations(map[string]string{
+				v1alpha1.AnnotationRequeueDelay: value,
+			})
+			Expect(ok).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring(v1alpha1.AnnotationRequeueDelay)))
+		}
+	})
+
+	It("overrides steady-state delays only", func() {
+		steadyDelays := []time.Duration{10 * time.Second, 5 * time.Minute}
+		override := time.Minute
+
+		for _, requeueAfter := range []time.Duration{
+			10 * time.Second,
+			requeue.WithJitter(10 * time.Second),
+			requeue.WithJitter(5 * time.Minute),
+		} {
+			Expect(requeue.OverrideSteadyDelay(requeueAfter, override, steadyDelays...)).To(
+				BeNumerically("~", override, float64(override)*requeue.JitterFactor),
+			)
+		}
+
+		for _, requeueAfter := range []time.Duration{time.Second, 30 * time.Second} {
+			Expect(requeue.OverrideSteadyDelay(requeueAfter, override, steadyDelays...)).To(Equal(requeueAfter))
+		}
+	})
+
+	It("reports invalid value once", func() {
+		warnings := &requeue.Warnings{}
+		Expect(warnings.ShouldReport("uid", "often")).To(BeTrue())
+		Expect(warnings.ShouldReport("uid", "often")).To(BeFalse())
+		Expect(warnings.ShouldReport("other", "often")).To(BeTrue())
+		Expect(warnings.ShouldReport("uid", "rarely")).To(BeTrue())
+
+		warnings.Forget("uid")
+		Expect(warnings.ShouldReport("uid", "rarely")).To(BeTrue())
+	})
+})
+
+var _ = DescribeTable("Backoff delay",
+	func(elapsed, expected time.Duration) {
+		Expect(requeue.Backoff(elapsed, 10*time.Second, 5*time.Minute)).To(Equal(expected))
+	},
+	Entry("just started waiting", time.Duration(0), 10*time.Second),
+	Entry("waiting less than minimal delay", 3*time.Second, 10*time.Second),
+	Entry("waiting exactly minimal delay", 10*time.Second, 10*time.Second),
+	Entry("waiting between delays", 90*time.Second, 90*time.Second),
+	Entry("waiting exactly maximal delay", 5*time.Minute, 5*time.Minute),
+	Entry("waiting longer than maximal delay", time.Hour, 5*time.Minute),
+)
+
+var _ = DescribeTable("Transient errors",
+	func(err error, expected bool) {
+		Expect(requeue.IsTransient(err)).To(Equal(expected))
+	},
+	Entry("no error", nil, false),
+	Entry("server timeout", apierrors.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "get", 1), true),
+	Entry("timeout", apierrors.NewTimeoutError("timeout", 1), true),
+	Entry("too many requests", apierrors.NewTooManyRequests("throttled", 1), true),
+	Entry("service unavailable", apierrors.NewServiceUnavailable("unavailable"), true),
+	Entry("internal error", apierrors.NewInternalError(errors.New("failure")), true),
+	Entry("network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true),
+	Entry("wrapped network error", fmt.Errorf("failed to get Pod: %w", &net.DNSError{IsTimeout: true}), true),
+	Entry("not found", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "pod"), false),
+	Entry("conflict", apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "pod", errors.New("modified")), false),
+	Entry("forbidden", apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "pod", errors.New("denied")), false),
+	Entry("plain error", errors.New("failure"), false),
+)
+
+var _ = Describe("Status update conflict", func() {
+	It("requeues immediately on conflict", func() {
+		result, conflict := requeue.OnConflict(logr.Discard(), apierrors.NewConflict(v1alpha1.GroupVersion.WithResource("storages").GroupResource(), "storage", errors.New("object has been modified")))
+		Expect(conflict).To(BeTrue())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+
+	It("leaves other errors to caller", func() {
+		_, conflict := requeue.OnConflict(logr.Discard(), apierrors.NewNotFound(schema.GroupResource{Resource: "storages"}, "storage"))
+		Expect(conflict).To(BeFalse())
+		_, conflict = requeue.OnConflict(logr.Discard(), nil)
+		Expect(conflict).To(BeFalse())
+	})
+})
@@ -0,0 +1,151 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+)
+
+// AdditionalResourceBuilder applies an object supplied by user in
+// `spec.additionalResources` of Storage or Database.
+type AdditionalResourceBuilder struct {
+	client.Object
+
+	Raw    runtime.RawExtension
+	Labels map[string]string
+}
+
+func getAdditionalResourceBuilders(
+	object client.Object,
+	additionalResources []runtime.RawExtension,
+	labels map[string]string,
+) []ResourceBuilder {
+	var builders []ResourceBuilder
+	for _, raw := range additionalResources {
+		builders = append(builders, &AdditionalResourceBuilder{
+			Object: object,
+			Raw:    raw,
+			Labels: labels,
+		})
+	}
+	return builders
+}
+
+func (b *AdditionalResourceBuilder) Build(obj client.Object) error {
+	current, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New("failed to cast to Unstructured object")
+	}
+
+	desired, err := api.ParseAdditionalResource(b.Raw)
+	if err != nil {
+		return err
+	}
+
+	// Object which exists without the label is not created from
+	// `spec.additionalResources`, e.g. it is managed by operator itself
+	if current.GetResourceVersion() != "" && current.GetLabels()[labels.AdditionalResourceKey] != "true" {
+		return fmt.Errorf(
+			"%s %s already exists and is not created from spec.additionalResources",
+			current.GetKind(),
+			current.GetName(),
+		)
+	}
+
+	// Content is rebuilt from scratch, so that fields removed
+	// from spec are removed from the object as well
+	for key := range current.Object {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		delete(current.Object, key)
+	}
+	for key, value := range desired.Object {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		current.Object[key] = value
+	}
+
+	if current.GetName() == "" {
+		current.SetName(desired.GetName())
+	}
+	current.SetNamespace(b.GetNamespace())
+
+	objLabels := CopyDict(desired.GetLabels())
+	for k, v := range b.Labels {
+		objLabels[k] = v
+	}
+	objLabels[labels.AdditionalResourceKey] = "true"
+	current.SetLabels(objLabels)
+
+	objAnnotations := CopyDict(current.GetAnnotations())
+	for k, v := range desired.GetAnnotations() {
+		objAnnotations[k] = v
+	}
+	current.SetAnnotations(objAnnotations)
+
+	return nil
+}
+
+func (b *AdditionalResourceBuilder) Placeholder(cr client.Object) client.Object {
+	obj := &unstructured.Unstructured{}
+	// Object which can not be parsed is rejected by webhook,
+	// otherwise it fails to sync because of missing kind
+	if desired, err := api.ParseAdditionalResource(b.Raw); err == nil {
+		obj.SetGroupVersionKind(desired.GroupVersionKind())
+		obj.SetName(desired.GetName())
+	}
+	obj.SetNamespace(cr.GetNamespace())
+	return obj
+}
+
+// GetRemovedAdditionalResources lists objects created from
+// `spec.additionalResources` of owner which are not supplied there anymore
+func GetRemovedAdditionalResources(
+	ctx context.Context,
+	c client.Client,
+	owner client.Object,
+	additionalResources []runtime.RawExtension,
+) ([]client.Object, error) {
+	supplied := map[string]bool{}
+	for _, raw := range additionalResources {
+		desired, err := api.ParseAdditionalResource(raw)
+		if err != nil {
+			return nil, err
+		}
+		supplied[desired.GroupVersionKind().GroupKind().String()+"/"+desired.GetName()] = true
+	}
+
+	var removed []client.Object
+	for _, gvk := range api.AdditionalResourceKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list,
+			client.InNamespace(owner.GetNamespace()),
+			client.MatchingLabels{labels.AdditionalResourceKey: "true"},
+		); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !metav1.IsControlledBy(obj, owner) {
+				continue
+			}
+			if supplied[gvk.GroupKind().String()+"/"+obj.GetName()] {
+				continue
+			}
+			removed = append(removed, obj)
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,119 @@
+package resources_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+var _ = Describe("AdditionalResourceBuilder", func() {
+	It("applies user-supplied object in namespace of the cluster", func() {
+		storage := newTestStorage()
+		builder := &resources.AdditionalResourceBuilder{
+			Object: storage,
+			Raw: runtime.RawExtension{
+				Raw: []byte(`{
+					"apiVersion": "networking.k8s.io/v1",
+					"kind": "NetworkPolicy",
+					"metadata": {"name": "storage-policy", "labels": {"team": "ydb"}},
+					"spec": {"podSelector": {}}
+				}`),
+			},
+			Labels: map[string]string{"app.kubernetes.io/name": "ydb"},
+		}
+
+		obj := builder.Placeholder(storage).(*unstructured.Unstructured)
+		Expect(obj.GetKind()).To(Equal("NetworkPolicy"))
+		Expect(obj.GetName()).To(Equal("storage-policy"))
+		Expect(obj.GetNamespace()).To(Equal(storage.Namespace))
+
+		obj.SetAnnotations(map[string]string{"existing": "annotation"})
+		Expect(builder.Build(obj)).To(Succeed())
+		Expect(obj.Object).To(HaveKey("spec"))
+		Expect(obj.GetLabels()).To(Equal(map[string]string{
+			"team":                       "ydb",
+			"app.kubernetes.io/name":     "ydb",
+			labels.AdditionalResourceKey: "true",
+		}))
+		Expect(obj.GetAnnotations()).To(HaveKeyWithValue("existing", "annotation"))
+	})
+
+	It("removes fields which are not supplied anymore", func() {
+		storage := newTestStorage()
+		builder := &resources.AdditionalResourceBuilder{
+			Object: storage,
+			Raw: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "extra"}, "data": {"a": "1"}}`),
+			},
+		}
+
+		obj := builder.Placeholder(storage).(*unstructured.Unstructured)
+		obj.SetResourceVersion("1")
+		obj.SetLabels(map[string]string{labels.AdditionalResourceKey: "true"})
+		obj.Object["binaryData"] = map[string]interface{}{"b": "Mg=="}
+		obj.Object["data"] = map[string]interface{}{"a": "0", "c": "3"}
+
+		Expect(builder.Build(obj)).To(Succeed())
+		Expect(obj.Object).NotTo(HaveKey("binaryData"))
+		Expect(obj.Object["data"]).To(Equal(map[string]interface{}{"a": "1"}))
+	})
+
+	It("refuses to take over objects not created from spec", func() {
+		storage := newTestStorage()
+		builder := &resources.AdditionalResourceBuilder{
+			Object: storage,
+			Raw: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "other-grpc"}}`),
+			},
+		}
+
+		obj := builder.Placeholder(storage).(*unstructured.Unstructured)
+		obj.SetResourceVersion("1")
+		Expect(builder.Build(obj)).To(MatchError(ContainSubstring("already exists")))
+	})
+
+	It("lists objects removed from spec", func() {
+		storage := newTestStorage()
+		storage.UID = "storage-uid"
+		ownerRef := metav1.OwnerReference{
+			APIVersion: "ydb.tech/v1alpha1",
+			Kind:       "Storage",
+			Name:       storage.Name,
+			UID:        storage.UID,
+			Controller: ptr.Bool(true),
+		}
+		newConfigMap := func(name string, owned bool) *corev1.ConfigMap {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: storage.Namespace,
+				Labels:    map[string]string{labels.AdditionalResourceKey: "true"},
+			}}
+			if owned {
+				cm.OwnerReferences = []metav1.OwnerReference{ownerRef}
+			}
+			return cm
+		}
+		c := fake.NewClientBuilder().WithObjects(
+			newConfigMap("kept", true),
+			newConfigMap("removed", true),
+			newConfigMap("foreign", false),
+		).Build()
+
+		removed, err := resources.GetRemovedAdditionalResources(context.Background(), c, storage, []runtime.RawExtension{{
+			Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "kept"}}`),
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(HaveLen(1))
+		Expect(removed[0].GetName()).To(Equal("removed"))
+	})
+})
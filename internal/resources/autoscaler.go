@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"errors"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+// maxScalingPolicyPeriodSeconds is the longest period of scaling policy
+// allowed by HorizontalPodAutoscaler
+const maxScalingPolicyPeriodSeconds = 1800
+
+// HorizontalPodAutoscalerBuilder builds autoscaler which scales Database
+// through its scale subresource, so that nodes are changed by operator.
+type HorizontalPodAutoscalerBuilder struct {
+	client.Object
+
+	Name   string
+	Labels map[string]string
+
+	Autoscaling                   *api.DatabaseAutoscaling
+	TerminationGracePeriodSeconds *int64
+}
+
+func (b *HorizontalPodAutoscalerBuilder) Build(obj client.Object) error {
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return errors.New("failed to cast to HorizontalPodAutoscaler object")
+	}
+
+	if hpa.ObjectMeta.Name == "" {
+		hpa.ObjectMeta.Name = b.Name
+	}
+	hpa.ObjectMeta.Namespace = b.GetNamespace()
+
+	hpa.Labels = b.Labels
+
+	targetCPUUtilization := int32(api.DefaultTargetCPUUtilization)
+	if b.Autoscaling.TargetCPUUtilizationPercentage != nil {
+		targetCPUUtilization = *b.Autoscaling.TargetCPUUtilizationPercentage
+	}
+
+	// Database node drains its tablets to other nodes on shutdown, so nodes
+	// are removed one by one and not faster than the pod grace period
+	scaleDownPeriod := int64(api.DefaultScaleDownPeriodSeconds)
+	if b.TerminationGracePeriodSeconds != nil && *b.TerminationGracePeriodSeconds > scaleDownPeriod {
+		scaleDownPeriod = *b.TerminationGracePeriodSeconds
+	}
+	if scaleDownPeriod > maxScalingPolicyPeriodSeconds {
+		scaleDownPeriod = maxScalingPolicyPeriodSeconds
+	}
+
+	hpa.Spec = autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: api.GroupVersion.String(),
+			Kind:       DatabaseKind,
+			Name:       b.GetName(),
+		},
+		MinReplicas: ptr.Int32(b.Autoscaling.MinNodes),
+		MaxReplicas: b.Autoscaling.MaxNodes,
+		Metrics: []autoscalingv2.MetricSpec{{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &targetCPUUtilization,
+				},
+			},
+		}},
+		Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+			ScaleDown: &autoscalingv2.HPAScalingRules{
+				StabilizationWindowSeconds: ptr.Int32(api.DefaultScaleDownStabilizationSeconds),
+				Policies: []autoscalingv2.HPAScalingPolicy{{
+					Type:          autoscalingv2.PodsScalingPolicy,
+					Value:         1,
+					PeriodSeconds: int32(scaleDownPeriod),
+				}},
+			},
+		},
+	}
+
+	return nil
+}
+
+func (b *HorizontalPodAutoscalerBuilder) Placeholder(cr client.Object) client.Object {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name,
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
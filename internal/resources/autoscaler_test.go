@@ -0,0 +1,46 @@
+package resources_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+var _ = Describe("HorizontalPodAutoscalerBuilder", func() {
+	build := func(terminationGracePeriodSeconds *int64) *autoscalingv2.HorizontalPodAutoscaler {
+		database := &api.Database{}
+		database.Name = "database"
+		database.Namespace = "ydb"
+		builder := &resources.HorizontalPodAutoscalerBuilder{
+			Object:                        database,
+			Name:                          database.Name,
+			Autoscaling:                   &api.DatabaseAutoscaling{MinNodes: 2, MaxNodes: 6},
+			TerminationGracePeriodSeconds: terminationGracePeriodSeconds,
+		}
+		hpa := builder.Placeholder(database).(*autoscalingv2.HorizontalPodAutoscaler)
+		Expect(builder.Build(hpa)).To(Succeed())
+		return hpa
+	}
+
+	It("removes nodes one by one", func() {
+		scaleDown := build(nil).Spec.Behavior.ScaleDown
+		Expect(*scaleDown.StabilizationWindowSeconds).To(Equal(int32(api.DefaultScaleDownStabilizationSeconds)))
+		Expect(scaleDown.Policies).To(Equal([]autoscalingv2.HPAScalingPolicy{{
+			Type:          autoscalingv2.PodsScalingPolicy,
+			Value:         1,
+			PeriodSeconds: api.DefaultScaleDownPeriodSeconds,
+		}}))
+	})
+
+	It("waits for grace period of removed node", func() {
+		scaleDown := build(ptr.Int64(180)).Spec.Behavior.ScaleDown
+		Expect(scaleDown.Policies[0].PeriodSeconds).To(Equal(int32(180)))
+
+		scaleDown = build(ptr.Int64(10)).Spec.Behavior.ScaleDown
+		Expect(scaleDown.Policies[0].PeriodSeconds).To(Equal(int32(api.DefaultScaleDownPeriodSeconds)))
+	})
+})
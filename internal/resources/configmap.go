@@ -11,21 +11,33 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration/schema"
 )
 
-const keyConfigTmpl = `Keys {
-    ContainerPath: "{{ .ContainerPath }}"
-    Pin: "{{ .Pin }}"
-    Id: "{{ .ID }}"
-    Version: {{ .Version }}
-}`
+const keyConfigTmpl = `{{ range $i, $key := .Keys }}{{ if $i }}
+{{ end }}Keys {
+    ContainerPath: "{{ $key.ContainerPath }}"
+    Pin: "{{ $key.Pin }}"
+    Id: "{{ $key.ID }}"
+    Version: {{ $key.Version }}
+}{{ end }}`
+
+// MaxConfigMapDataSize is the size of ConfigMap data which is accepted by
+// the builder, a bit lower than 1MiB limit of API object to leave room
+// for metadata
+const MaxConfigMapDataSize = 1000 * 1024
+
+// ErrConfigTooLarge is returned by ConfigMapBuilder when data would not
+// fit into ConfigMap
+var ErrConfigTooLarge = errors.New("configuration is too large for ConfigMap")
 
 type ConfigMapBuilder struct {
 	client.Object
 
-	Name   string
-	Labels map[string]string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
 
 	Data map[string]string
 }
@@ -52,11 +64,45 @@ func (b *ConfigMapBuilder) Build(obj client.Object) error {
 
 	cm.Labels = b.Labels
 
+	// Annotations set by other controllers or users are kept
+	if len(b.Annotations) > 0 {
+		cmAnnotations := CopyDict(cm.Annotations)
+		for k, v := range b.Annotations {
+			cmAnnotations[k] = v
+		}
+		cm.Annotations = cmAnnotations
+	}
+
+	size := 0
+	for key, value := range b.Data {
+		size += len(key) + len(value)
+	}
+	if size > MaxConfigMapDataSize {
+		return fmt.Errorf("%w: data of ConfigMap %s is %d bytes, limit is %d bytes",
+			ErrConfigTooLarge, cm.ObjectMeta.Name, size, MaxConfigMapDataSize)
+	}
+
 	cm.Data = b.Data
 
 	return nil
 }
 
+// GetConfigMapChecksumAnnotations returns annotations of configuration
+// ConfigMap with checksum of the configuration applied by operator
+func GetConfigMapChecksumAnnotations(configuration string) map[string]string {
+	return map[string]string{
+		annotations.ConfigurationChecksum: SHAChecksum(configuration),
+	}
+}
+
+// IsConfigMapDrifted reports whether configuration of ConfigMap was changed
+// out of band, i.e. does not match the checksum stored by operator on apply.
+// ConfigMap without checksum is not considered drifted.
+func IsConfigMapDrifted(cm *v1.ConfigMap) bool {
+	checksum, ok := cm.Annotations[annotations.ConfigurationChecksum]
+	return ok && checksum != SHAChecksum(cm.Data[api.ConfigFileName])
+}
+
 func (b *EncryptionConfigBuilder) Build(obj client.Object) error {
 	cm, ok := obj.(*v1.ConfigMap)
 	if !ok {
@@ -76,7 +122,7 @@ func (b *EncryptionConfigBuilder) Build(obj client.Object) error {
 	}
 
 	var buf bytes.Buffer
-	err = t.Execute(&buf, b.KeyConfig.Keys[0])
+	err = t.Execute(&buf, b.KeyConfig)
 	if err != nil {
 		return fmt.Errorf("failed to execute keyConfig template: %w", err)
 	}
@@ -0,0 +1,63 @@
+package resources_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+var _ = Describe("ConfigMap builder", func() {
+	It("rejects data exceeding ConfigMap size limit", func() {
+		builder := &resources.ConfigMapBuilder{
+			Object: newTestStorage(),
+			Name:   "storage",
+			Data:   map[string]string{"config.yaml": strings.Repeat("a", resources.MaxConfigMapDataSize)},
+		}
+		err := builder.Build(&corev1.ConfigMap{})
+		Expect(err).To(MatchError(resources.ErrConfigTooLarge))
+		Expect(err).To(MatchError(ContainSubstring("1024011 bytes")))
+	})
+
+	It("detects out-of-band edits of configuration", func() {
+		configuration := "domains_config: {}\n"
+		builder := &resources.ConfigMapBuilder{
+			Object:      newTestStorage(),
+			Name:        "storage",
+			Data:        map[string]string{"config.yaml": configuration},
+			Annotations: resources.GetConfigMapChecksumAnnotations(configuration),
+		}
+		configMap := &corev1.ConfigMap{}
+		Expect(builder.Build(configMap)).To(Succeed())
+		Expect(resources.IsConfigMapDrifted(configMap)).To(BeFalse())
+
+		configMap.Data["config.yaml"] = "domains_config: {}\nlog_config: {}\n"
+		Expect(resources.IsConfigMapDrifted(configMap)).To(BeTrue())
+
+		configMap.Annotations = nil
+		Expect(resources.IsConfigMapDrifted(configMap)).To(BeFalse())
+	})
+
+	It("keeps annotations of ConfigMap set outside of operator", func() {
+		configuration := "domains_config: {}\n"
+		builder := &resources.ConfigMapBuilder{
+			Object:      newTestStorage(),
+			Name:        "storage",
+			Data:        map[string]string{"config.yaml": configuration},
+			Annotations: resources.GetConfigMapChecksumAnnotations(configuration),
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"example.com/owner": "team"},
+		}}
+		Expect(builder.Build(configMap)).To(Succeed())
+		Expect(configMap.Annotations).To(Equal(map[string]string{
+			"example.com/owner":               "team",
+			annotations.ConfigurationChecksum: resources.SHAChecksum(configuration),
+		}))
+	})
+})
@@ -2,13 +2,17 @@ package resources
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 
 	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration/schema"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
 )
@@ -32,18 +36,55 @@ func (b *DatabaseBuilder) Unwrap() *api.Database {
 	return b.DeepCopy()
 }
 
-func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []ResourceBuilder {
-	if b.Spec.ServerlessResources != nil {
-		return []ResourceBuilder{}
+// GetConfiguration returns the configuration mounted into database nodes:
+// from own ConfigMap if any, otherwise from the Storage ConfigMap
+func (b *DatabaseBuilder) GetConfiguration() string {
+	if b.Storage == nil {
+		return ""
+	}
+
+	if !b.Spec.HasOwnConfiguration() {
+		storage := NewCluster(b.Storage)
+		return storage.GetConfiguration()
+	}
+
+	// YDBOPS-9722 backward compatibility
+	cfg, _ := api.BuildConfiguration(b.Storage, b.Unwrap())
+	return string(cfg)
+}
+
+// GetSchemaOperationQuotasHash returns checksum of quotas of schema
+// operations of dedicated database, empty when no quotas are set
+func (b *DatabaseBuilder) GetSchemaOperationQuotasHash() string {
+	if b.Spec.Resources == nil || len(b.Spec.Resources.SchemaOperationQuotas) == 0 {
+		return ""
 	}
 
+	var quotas strings.Builder
+	for _, quota := range b.Spec.Resources.SchemaOperationQuotas {
+		fmt.Fprintf(&quotas, "%d/%d;", quota.BucketSize, quota.BucketSeconds)
+	}
+	return SHAChecksum(quotas.String())
+}
+
+func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []ResourceBuilder {
 	databaseLabels := labels.DatabaseLabels(b.Unwrap())
+	databaseSelectorLabels := labels.DatabaseSelectorLabels(b.Unwrap())
+
+	additionalResourceBuilders := getAdditionalResourceBuilders(b, b.Spec.AdditionalResources, databaseLabels)
+
+	if b.Spec.ServerlessResources != nil {
+		return append(b.getConnectionSecretBuilders(databaseLabels), additionalResourceBuilders...)
+	}
 
 	statefulSetLabels := databaseLabels.Copy()
 	statefulSetLabels.Merge(map[string]string{labels.StatefulsetComponent: b.Name})
 
 	statefulSetAnnotations := CopyDict(b.Spec.AdditionalAnnotations)
 	statefulSetAnnotations[annotations.ConfigurationChecksum] = SHAChecksum(b.Spec.Configuration)
+	if b.hasEncryptionKeyVersions() {
+		statefulSetAnnotations[annotations.EncryptionKeyVersion] = fmt.Sprint(b.Status.EncryptionKeyVersion)
+	}
 
 	grpcServiceLabels := databaseLabels.Copy()
 	grpcServiceLabels.Merge(b.Spec.Service.GRPC.AdditionalLabels)
@@ -57,16 +98,20 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 	statusServiceLabels.Merge(b.Spec.Service.Status.AdditionalLabels)
 	statusServiceLabels.Merge(map[string]string{labels.ServiceComponent: labels.StatusComponent})
 
+	statusServiceSelectorLabels := databaseSelectorLabels.Copy()
+	statusServiceSelectorLabels.Merge(map[string]string{labels.ServiceComponent: labels.StatusComponent})
+
 	datastreamsServiceLabels := databaseLabels.Copy()
 	datastreamsServiceLabels.Merge(b.Spec.Service.Datastreams.AdditionalLabels)
 	datastreamsServiceLabels.Merge(map[string]string{labels.ServiceComponent: labels.DatastreamsComponent})
 
-	var optionalBuilders []ResourceBuilder
+	postgresServiceLabels := databaseLabels.Copy()
+	postgresServiceLabels.Merge(b.Spec.Service.Postgres.AdditionalLabels)
+	postgresServiceLabels.Merge(map[string]string{labels.ServiceComponent: labels.PostgresComponent})
 
-	if b.Spec.Configuration != "" {
-		// YDBOPS-9722 backward compatibility
-		cfg, _ := api.BuildConfiguration(b.Storage, b.Unwrap())
+	var optionalBuilders []ResourceBuilder
 
+	if b.Spec.HasOwnConfiguration() {
 		optionalBuilders = append(
 			optionalBuilders,
 			&ConfigMapBuilder{
@@ -74,9 +119,10 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 
 				Name: b.GetName(),
 				Data: map[string]string{
-					api.ConfigFileName: string(cfg),
+					api.ConfigFileName: b.GetConfiguration(),
 				},
-				Labels: databaseLabels,
+				Labels:      databaseLabels,
+				Annotations: GetConfigMapChecksumAnnotations(b.GetConfiguration()),
 			},
 		)
 	}
@@ -91,7 +137,7 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 				Options:         b.Spec.Monitoring,
 
 				Labels:         databaseLabels,
-				SelectorLabels: statusServiceLabels,
+				SelectorLabels: statusServiceSelectorLabels,
 			},
 		)
 	}
@@ -129,6 +175,21 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 				},
 			},
 		}
+		if b.hasEncryptionKeyVersions() {
+			keyConfig.Keys = nil
+			for version := 1; version <= int(b.Status.EncryptionKeyVersion); version++ {
+				keyConfig.Keys = append(keyConfig.Keys, schema.Key{
+					ContainerPath: fmt.Sprintf("%s/%s/%s",
+						wellKnownDirForAdditionalSecrets,
+						api.DatabaseEncryptionKeySecretDir,
+						EncryptionKeyVersionName(version),
+					),
+					ID:      SHAChecksum(b.Spec.StorageClusterRef.Name),
+					Pin:     b.Spec.Encryption.Pin,
+					Version: version,
+				})
+			}
+		}
 
 		optionalBuilders = append(
 			optionalBuilders,
@@ -149,7 +210,7 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 			Object:         b,
 			NameFormat:     GRPCServiceNameFormat,
 			Labels:         grpcServiceLabels,
-			SelectorLabels: databaseLabels,
+			SelectorLabels: databaseSelectorLabels,
 			Annotations:    b.Spec.Service.GRPC.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.GRPCServicePortName,
@@ -162,7 +223,7 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 			Object:         b,
 			NameFormat:     InterconnectServiceNameFormat,
 			Labels:         interconnectServiceLabels,
-			SelectorLabels: databaseLabels,
+			SelectorLabels: databaseSelectorLabels,
 			Annotations:    b.Spec.Service.Interconnect.AdditionalAnnotations,
 			Headless:       true,
 			Ports: []corev1.ServicePort{{
@@ -176,7 +237,7 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 			Object:         b,
 			NameFormat:     StatusServiceNameFormat,
 			Labels:         statusServiceLabels,
-			SelectorLabels: databaseLabels,
+			SelectorLabels: databaseSelectorLabels,
 			Annotations:    b.Spec.Service.Status.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.StatusServicePortName,
@@ -194,7 +255,7 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 				Object:         b,
 				NameFormat:     DatastreamsServiceNameFormat,
 				Labels:         datastreamsServiceLabels,
-				SelectorLabels: databaseLabels,
+				SelectorLabels: databaseSelectorLabels,
 				Annotations:    b.Spec.Service.Datastreams.AdditionalAnnotations,
 				Ports: []corev1.ServicePort{{
 					Name: api.DatastreamsServicePortName,
@@ -206,11 +267,32 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 		)
 	}
 
+	if b.Spec.IsPostgresEnabled() {
+		optionalBuilders = append(
+			optionalBuilders,
+			&ServiceBuilder{
+				Object:         b,
+				NameFormat:     PostgresServiceNameFormat,
+				Labels:         postgresServiceLabels,
+				SelectorLabels: databaseSelectorLabels,
+				Annotations:    b.Spec.Service.Postgres.AdditionalAnnotations,
+				Ports: []corev1.ServicePort{{
+					Name: api.PostgresServicePortName,
+					Port: b.Spec.Postgres.GetPort(),
+				}},
+				IPFamilies:     b.Spec.Service.Postgres.IPFamilies,
+				IPFamilyPolicy: b.Spec.Service.Postgres.IPFamilyPolicy,
+			},
+		)
+	}
+
 	if b.Spec.NodeSets == nil {
+		database := b.Unwrap()
+		database.Spec.Affinity = b.withStorageAffinity(database.Spec.Affinity)
 		optionalBuilders = append(
 			optionalBuilders,
 			&DatabaseStatefulSetBuilder{
-				Database:   b.Unwrap(),
+				Database:   database,
 				RestConfig: restConfig,
 
 				Name:        b.Name,
@@ -222,9 +304,52 @@ func (b *DatabaseBuilder) GetResourceBuilders(restConfig *rest.Config) []Resourc
 		optionalBuilders = append(optionalBuilders, b.getNodeSetBuilders(databaseLabels)...)
 	}
 
+	if b.Spec.Autoscaling != nil {
+		optionalBuilders = append(
+			optionalBuilders,
+			&HorizontalPodAutoscalerBuilder{
+				Object: b,
+
+				Name:   b.Name,
+				Labels: databaseLabels,
+
+				Autoscaling:                   b.Spec.Autoscaling,
+				TerminationGracePeriodSeconds: b.Spec.TerminationGracePeriodSeconds,
+			},
+		)
+	}
+
+	optionalBuilders = append(optionalBuilders, b.getConnectionSecretBuilders(databaseLabels)...)
+	optionalBuilders = append(optionalBuilders, additionalResourceBuilders...)
+
 	return optionalBuilders
 }
 
+func (b *DatabaseBuilder) getConnectionSecretBuilders(databaseLabels labels.Labels) []ResourceBuilder {
+	if b.Spec.ConnectionSecret == nil || !b.Spec.ConnectionSecret.Enabled {
+		return []ResourceBuilder{}
+	}
+
+	// Connection parameters make sense only when database already exists
+	if !meta.IsStatusConditionTrue(b.Status.Conditions, DatabaseInitializedCondition) {
+		return []ResourceBuilder{}
+	}
+
+	secretName := fmt.Sprintf(ConnectionSecretNameFormat, b.Name)
+	if b.Spec.ConnectionSecret.Name != "" {
+		secretName = b.Spec.ConnectionSecret.Name
+	}
+
+	return []ResourceBuilder{
+		&DatabaseConnectionSecretBuilder{
+			Database: b.Unwrap(),
+
+			Name:   secretName,
+			Labels: databaseLabels,
+		},
+	}
+}
+
 func (b *DatabaseBuilder) getNodeSetBuilders(databaseLabels labels.Labels) []ResourceBuilder {
 	var nodeSetBuilders []ResourceBuilder
 
@@ -308,6 +433,12 @@ func (b *DatabaseBuilder) recastDatabaseNodeSetSpecInline(nodeSetSpecInline *api
 		nodeSetSpec.Affinity = nodeSetSpecInline.Affinity
 	}
 
+	if nodeSetSpecInline.Remote == nil {
+		nodeSetSpec.Affinity = b.withStorageAffinity(nodeSetSpec.Affinity)
+	} else {
+		nodeSetSpec.NodePool = nil
+	}
+
 	if nodeSetSpecInline.TopologySpreadConstraints != nil {
 		nodeSetSpec.TopologySpreadConstraints = nodeSetSpecInline.TopologySpreadConstraints
 	}
@@ -329,6 +460,75 @@ func (b *DatabaseBuilder) recastDatabaseNodeSetSpecInline(nodeSetSpecInline *api
 			nodeSetSpec.AdditionalAnnotations[k] = v
 		}
 	}
+	if b.hasEncryptionKeyVersions() {
+		nodeSetSpec.AdditionalAnnotations[annotations.EncryptionKeyVersion] = fmt.Sprint(b.Status.EncryptionKeyVersion)
+	}
 
 	return nodeSetSpec
 }
+
+// hasEncryptionKeyVersions reports whether versions of the encryption key
+// from `spec.encryption.key` are kept by operator, so that Database nodes
+// read every version from the Secret with encryption keys
+func (b *DatabaseBuilder) hasEncryptionKeyVersions() bool {
+	return b.Spec.Encryption != nil && b.Spec.Encryption.Enabled &&
+		b.Spec.Encryption.Key != nil && b.Status.EncryptionKeyVersion > 0
+}
+
+// EncryptionKeyVersionName returns the key of the Secret with encryption
+// keys which holds the version of the key
+func EncryptionKeyVersionName(version int) string {
+	return fmt.Sprintf(wellKnownFormatForEncryptionKeyVersion, version)
+}
+
+// withStorageAffinity returns a copy of affinity extended with pod affinity
+// towards the pods of referenced Storage, if requested in Database spec
+func (b *DatabaseBuilder) withStorageAffinity(affinity *corev1.Affinity) *corev1.Affinity {
+	if b.Spec.StorageAffinity == nil || b.Storage == nil {
+		return affinity
+	}
+
+	storageLabels := labels.StorageLabels(b.Storage)
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				labels.InstanceKey:  storageLabels[labels.InstanceKey],
+				labels.ComponentKey: storageLabels[labels.ComponentKey],
+			},
+		},
+		Namespaces:  []string{b.Storage.Namespace},
+		TopologyKey: api.DefaultStorageAffinityTopologyKey,
+	}
+	if b.Spec.StorageAffinity.TopologyKey != "" {
+		term.TopologyKey = b.Spec.StorageAffinity.TopologyKey
+	}
+
+	result := &corev1.Affinity{}
+	if affinity != nil {
+		result = affinity.DeepCopy()
+	}
+	if result.PodAffinity == nil {
+		result.PodAffinity = &corev1.PodAffinity{}
+	}
+
+	if b.Spec.StorageAffinity.Mode == api.StorageAffinityRequired {
+		result.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			result.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			term,
+		)
+		return result
+	}
+
+	weight := int32(api.DefaultStorageAffinityWeight)
+	if b.Spec.StorageAffinity.Weight != 0 {
+		weight = b.Spec.StorageAffinity.Weight
+	}
+	result.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		result.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.WeightedPodAffinityTerm{
+			Weight:          weight,
+			PodAffinityTerm: term,
+		},
+	)
+	return result
+}
@@ -0,0 +1,203 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+)
+
+const (
+	cloneDumpVolumeName      = "clone-dump"
+	cloneDumpVolumeMountPath = "/opt/ydb/clone"
+)
+
+// DatabaseCloneJobBuilder builds Job which copies schema, and optionally
+// data, of the source Database into the Database being created
+type DatabaseCloneJobBuilder struct {
+	*api.Database
+
+	Source *api.Database
+	// WithOperatorToken mounts token of the operator, which is required
+	// when Storage has `operatorConnection` set
+	WithOperatorToken bool
+
+	Name   string
+	Labels map[string]string
+}
+
+func (b *DatabaseCloneJobBuilder) Build(obj client.Object) error {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return errors.New("failed to cast to Job object")
+	}
+
+	if job.ObjectMeta.Name == "" {
+		job.ObjectMeta.Name = b.Name
+	}
+	job.ObjectMeta.Namespace = b.GetNamespace()
+	job.ObjectMeta.Labels = b.Labels
+
+	job.Spec = batchv1.JobSpec{
+		Parallelism:  ptr.Int32(1),
+		Completions:  ptr.Int32(1),
+		BackoffLimit: ptr.Int32(api.DefaultInitJobBackoffLimit),
+		Template:     b.buildPodTemplateSpec(),
+	}
+
+	return nil
+}
+
+func (b *DatabaseCloneJobBuilder) Placeholder(cr client.Object) client.Object {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name,
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
+
+func GetDatabaseCloneJobBuilder(database, source *api.Database, withOperatorToken bool) ResourceBuilder {
+	return &DatabaseCloneJobBuilder{
+		Database: database,
+
+		Source:            source,
+		WithOperatorToken: withOperatorToken,
+
+		Name:   fmt.Sprintf(CloneJobNameFormat, database.Name),
+		Labels: labels.Common(database.Name, make(map[string]string)),
+	}
+}
+
+func (b *DatabaseCloneJobBuilder) buildPodTemplateSpec() corev1.PodTemplateSpec {
+	podTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: b.Labels,
+		},
+		Spec: corev1.PodSpec{
+			Containers:    []corev1.Container{b.buildContainer()},
+			Volumes:       b.buildVolumes(),
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector:  b.Spec.NodeSelector,
+			Tolerations:   b.Spec.Tolerations,
+		},
+	}
+
+	if b.Spec.Image.PullSecret != nil {
+		podTemplate.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: *b.Spec.Image.PullSecret}}
+	}
+
+	return podTemplate
+}
+
+func (b *DatabaseCloneJobBuilder) buildVolumes() []corev1.Volume {
+	volumes := []corev1.Volume{
+		{
+			Name: cloneDumpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+
+	if b.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		volumes = append(volumes, buildTLSVolume(grpcTLSVolumeName, b.Spec.Service.GRPC.TLSConfiguration))
+	}
+
+	if b.WithOperatorToken {
+		volumes = append(volumes, corev1.Volume{
+			Name: operatorTokenVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: fmt.Sprintf(OperatorTokenSecretNameFormat, b.Database.Name),
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+func (b *DatabaseCloneJobBuilder) buildContainer() corev1.Container {
+	imagePullPolicy := corev1.PullIfNotPresent
+	if b.Spec.Image.PullPolicyName != nil {
+		imagePullPolicy = *b.Spec.Image.PullPolicyName
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      cloneDumpVolumeName,
+			MountPath: cloneDumpVolumeMountPath,
+		},
+	}
+	if b.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      grpcTLSVolumeName,
+			ReadOnly:  true,
+			MountPath: grpcTLSVolumeMountPath,
+		})
+	}
+	if b.WithOperatorToken {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      operatorTokenVolumeName,
+			ReadOnly:  true,
+			MountPath: fmt.Sprintf("%s/%s", wellKnownDirForAdditionalSecrets, operatorTokenVolumeName),
+		})
+	}
+
+	return corev1.Container{
+		Name:            "ydb-clone-database",
+		Image:           b.Spec.Image.Name,
+		ImagePullPolicy: imagePullPolicy,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{b.buildCloneScript()},
+		VolumeMounts:    volumeMounts,
+	}
+}
+
+// buildCloneScript dumps the source Database into the scratch volume and
+// restores the dump into the Database. Source is reached via discovery
+// through the grpc endpoint of the Database, as both are served by the
+// same Storage.
+func (b *DatabaseCloneJobBuilder) buildCloneScript() string {
+	proto := api.GRPCProto
+	if b.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		proto = api.GRPCSProto
+	}
+
+	connectionArgs := []string{
+		fmt.Sprintf("%s/%s", api.BinariesDir, api.CLIBinaryName),
+		"-e",
+		fmt.Sprintf("%s%s:%d", proto, fmt.Sprintf(api.GRPCServiceFQDNFormat, b.Database.Name, b.GetNamespace()), api.GRPCPort),
+	}
+	if b.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		connectionArgs = append(connectionArgs,
+			"--ca-file",
+			fmt.Sprintf("%s/%s", grpcTLSVolumeMountPath, wellKnownNameForTLSCertificateAuthority),
+		)
+	}
+	if b.WithOperatorToken {
+		connectionArgs = append(connectionArgs,
+			"--token-file",
+			fmt.Sprintf("%s/%s/%s", wellKnownDirForAdditionalSecrets, operatorTokenVolumeName, wellKnownNameForOperatorToken),
+		)
+	}
+	connection := strings.Join(connectionArgs, " ")
+
+	dumpDir := fmt.Sprintf("%s/dump", cloneDumpVolumeMountPath)
+	dumpArgs := []string{connection, "-d", b.Source.GetDatabasePath(), "tools", "dump", "-p", ".", "-o", dumpDir}
+	if !b.Spec.SourceDatabaseRef.WithData {
+		dumpArgs = append(dumpArgs, "--scheme-only")
+	}
+	restoreArgs := []string{connection, "-d", b.GetDatabasePath(), "tools", "restore", "-p", ".", "-i", dumpDir}
+
+	return fmt.Sprintf("set -e\nrm -rf %s\n%s\n%s\n", dumpDir, strings.Join(dumpArgs, " "), strings.Join(restoreArgs, " "))
+}
@@ -0,0 +1,51 @@
+package resources_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+func buildDatabaseCloneJob(withData, withOperatorToken bool) *batchv1.Job {
+	database := newTestDatabase()
+	database.Spec.Domain = "Root"
+	database.Spec.SourceDatabaseRef = &api.SourceDatabaseRef{
+		NamespacedRef: api.NamespacedRef{Name: "source", Namespace: "ydb"},
+		WithData:      withData,
+	}
+	source := newTestDatabase()
+	source.ObjectMeta = metav1.ObjectMeta{Name: "source", Namespace: "ydb"}
+	source.Spec.Domain = "Root"
+
+	builder := resources.GetDatabaseCloneJobBuilder(database, source, withOperatorToken)
+	job := builder.Placeholder(database).(*batchv1.Job)
+	Expect(builder.Build(job)).To(Succeed())
+	return job
+}
+
+var _ = Describe("Database clone Job", func() {
+	It("dumps only schema of the source Database by default", func() {
+		job := buildDatabaseCloneJob(false, false)
+
+		Expect(job.Name).To(Equal("database-clone"))
+		script := job.Spec.Template.Spec.Containers[0].Args[0]
+		Expect(script).To(ContainSubstring("-d /Root/source tools dump -p . -o /opt/ydb/clone/dump --scheme-only"))
+		Expect(script).To(ContainSubstring("-d /Root/database tools restore -p . -i /opt/ydb/clone/dump"))
+		Expect(script).NotTo(ContainSubstring("--token-file"))
+	})
+
+	It("copies data and uses operator token when requested", func() {
+		job := buildDatabaseCloneJob(true, true)
+
+		script := job.Spec.Template.Spec.Containers[0].Args[0]
+		Expect(script).NotTo(ContainSubstring("--scheme-only"))
+		Expect(script).To(ContainSubstring("--token-file"))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(
+			HaveField("Secret.SecretName", "database-operator-token"),
+		))
+	})
+})
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
 )
@@ -58,7 +59,7 @@ func (b *DatabaseStatefulSetBuilder) Build(obj client.Object) error {
 			},
 		},
 		PodManagementPolicy:  appsv1.ParallelPodManagement,
-		RevisionHistoryLimit: ptr.Int32(10),
+		RevisionHistoryLimit: b.getRevisionHistoryLimit(),
 		ServiceName:          fmt.Sprintf(InterconnectServiceNameFormat, b.Database.Name),
 		Template:             b.buildPodTemplateSpec(),
 	}
@@ -69,9 +70,27 @@ func (b *DatabaseStatefulSetBuilder) Build(obj client.Object) error {
 		}
 	}
 
+	if b.Spec.ScratchSpace != nil && b.Spec.ScratchSpace.VolumeClaimTemplate != nil {
+		sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: scratchSpaceVolumeName,
+				},
+				Spec: *b.Spec.ScratchSpace.VolumeClaimTemplate,
+			},
+		}
+	}
+
 	return nil
 }
 
+func (b *DatabaseStatefulSetBuilder) getRevisionHistoryLimit() *int32 {
+	if b.Spec.RevisionHistoryLimit != nil {
+		return ptr.Int32(*b.Spec.RevisionHistoryLimit)
+	}
+	return ptr.Int32(api.DefaultRevisionHistoryLimit)
+}
+
 func (b *DatabaseStatefulSetBuilder) buildEnv() []corev1.EnvVar {
 	var envVars []corev1.EnvVar
 
@@ -106,13 +125,15 @@ func (b *DatabaseStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSp
 			Annotations: b.Annotations,
 		},
 		Spec: corev1.PodSpec{
-			Containers:                    []corev1.Container{b.buildContainer()},
-			NodeSelector:                  b.Spec.NodeSelector,
+			Containers:                    append([]corev1.Container{b.buildContainer()}, b.Spec.Sidecars...),
+			NodeSelector:                  b.buildNodeSelector(),
 			Affinity:                      b.Spec.Affinity,
 			Tolerations:                   b.Spec.Tolerations,
 			PriorityClassName:             b.Spec.PriorityClassName,
+			SchedulerName:                 b.Spec.SchedulerName,
 			TopologySpreadConstraints:     b.Spec.TopologySpreadConstraints,
 			TerminationGracePeriodSeconds: b.Spec.TerminationGracePeriodSeconds,
+			AutomountServiceAccountToken:  b.Spec.AutomountServiceAccountToken,
 
 			Volumes: b.buildVolumes(),
 
@@ -124,6 +145,10 @@ func (b *DatabaseStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSp
 		},
 	}
 
+	if b.Spec.InjectGOMAXPROCS {
+		SetGOMAXPROCS(podTemplate.Spec.Containers)
+	}
+
 	// InitContainer only needed for CaBundle manipulation for now,
 	// may be probably used for other stuff later
 	if b.AnyCertificatesAdded() {
@@ -154,7 +179,7 @@ func (b *DatabaseStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSp
 
 func (b *DatabaseStatefulSetBuilder) buildVolumes() []corev1.Volume {
 	configMapName := b.Spec.StorageClusterRef.Name
-	if b.Spec.Configuration != "" {
+	if b.Spec.HasOwnConfiguration() {
 		configMapName = b.GetName()
 	}
 
@@ -203,6 +228,18 @@ func (b *DatabaseStatefulSetBuilder) buildVolumes() []corev1.Volume {
 		volumes = append(volumes, *volume)
 	}
 
+	if b.Spec.ScratchSpace != nil && b.Spec.ScratchSpace.VolumeClaimTemplate == nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: scratchSpaceVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    b.Spec.ScratchSpace.Medium,
+					SizeLimit: b.Spec.ScratchSpace.SizeLimit,
+				},
+			},
+		})
+	}
+
 	for _, secret := range b.Spec.Secrets {
 		volumes = append(volumes, corev1.Volume{
 			Name: secret.Name,
@@ -245,7 +282,7 @@ func (b *DatabaseStatefulSetBuilder) buildCaStorePatchingInitContainer() corev1.
 		imagePullPolicy = *b.Spec.Image.PullPolicyName
 	}
 	container := corev1.Container{
-		Name:            "ydb-storage-init-container",
+		Name:            api.CaStorePatchingContainerName,
 		Image:           b.Spec.Image.Name,
 		ImagePullPolicy: imagePullPolicy,
 		Command:         command,
@@ -381,6 +418,14 @@ func (b *DatabaseStatefulSetBuilder) buildEncryptionVolumes() []corev1.Volume {
 		},
 	}
 
+	// Every version of rotated key is read from the Secret with
+	// encryption keys kept by operator
+	if _, ok := b.Annotations[annotations.EncryptionKeyVersion]; ok && b.Spec.Encryption.Key != nil {
+		encryptionKeySecret.VolumeSource.Secret = &corev1.SecretVolumeSource{
+			SecretName: fmt.Sprintf(EncryptionSecretNameFormat, b.GetName()),
+		}
+	}
+
 	encryptionKeyConfig := corev1.Volume{
 		Name: encryptionKeyConfigVolumeName,
 		VolumeSource: corev1.VolumeSource{
@@ -402,7 +447,7 @@ func (b *DatabaseStatefulSetBuilder) buildContainer() corev1.Container {
 		imagePullPolicy = *b.Spec.Image.PullPolicyName
 	}
 	container := corev1.Container{
-		Name:            "ydb-dynamic",
+		Name:            api.DatabaseContainerName,
 		Image:           b.Spec.Image.Name,
 		ImagePullPolicy: imagePullPolicy,
 		Command:         command,
@@ -442,6 +487,12 @@ func (b *DatabaseStatefulSetBuilder) buildContainer() corev1.Container {
 		})
 	}
 
+	if b.Spec.IsPostgresEnabled() {
+		ports = append(ports, corev1.ContainerPort{
+			Name: "postgres", ContainerPort: b.Spec.Postgres.GetPort(),
+		})
+	}
+
 	container.Ports = ports
 
 	if b.Spec.Resources != nil {
@@ -458,7 +509,7 @@ func (b *DatabaseStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 	volumeMounts = append(volumeMounts, corev1.VolumeMount{
 		Name:      configVolumeName,
 		ReadOnly:  true,
-		MountPath: fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName),
+		MountPath: fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.ConfigFileName),
 		SubPath:   api.ConfigFileName,
 	})
 
@@ -490,7 +541,7 @@ func (b *DatabaseStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      encryptionKeyConfigVolumeName,
 			ReadOnly:  true,
-			MountPath: fmt.Sprintf("%s/%s", api.ConfigDir, api.DatabaseEncryptionKeyConfigFile),
+			MountPath: fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.DatabaseEncryptionKeyConfigFile),
 			SubPath:   api.DatabaseEncryptionKeyConfigFile,
 		})
 
@@ -518,6 +569,13 @@ func (b *DatabaseStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if b.Spec.ScratchSpace != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      scratchSpaceVolumeName,
+			MountPath: api.ScratchSpaceDir,
+		})
+	}
+
 	if b.AnyCertificatesAdded() {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      localCertsVolumeName,
@@ -540,6 +598,18 @@ func (b *DatabaseStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 	return volumeMounts
 }
 
+// buildNodeSelector returns node selector of Database pods extended with
+// the label of node pool, if it is set in Database spec
+func (b *DatabaseStatefulSetBuilder) buildNodeSelector() map[string]string {
+	if b.Spec.NodePool == nil {
+		return b.Spec.NodeSelector
+	}
+
+	nodeSelector := CopyDict(b.Spec.NodeSelector)
+	nodeSelector[b.Spec.NodePool.GetLabelKey()] = b.Spec.NodePool.Name
+	return nodeSelector
+}
+
 func (b *DatabaseStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 	command := []string{fmt.Sprintf("%s/%s", api.BinariesDir, api.DaemonBinaryName)}
 
@@ -553,7 +623,7 @@ func (b *DatabaseStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		fmt.Sprintf("%d", api.InterconnectPort),
 
 		"--yaml-config",
-		fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName),
+		fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.ConfigFileName),
 
 		"--tenant",
 		b.GetDatabasePath(),
@@ -577,10 +647,17 @@ func (b *DatabaseStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		)
 	}
 
+	if b.Spec.NodePool != nil {
+		args = append(args,
+			"--label",
+			fmt.Sprintf("%s=%s", api.LabelNodePoolKey, b.Spec.NodePool.Name),
+		)
+	}
+
 	if b.Spec.Encryption != nil && b.Spec.Encryption.Enabled {
 		args = append(args,
 			"--key-file",
-			fmt.Sprintf("%s/%s", api.ConfigDir, api.DatabaseEncryptionKeyConfigFile),
+			fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.DatabaseEncryptionKeyConfigFile),
 		)
 	}
 
@@ -0,0 +1,216 @@
+package resources_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+func newTestDatabase() *api.Database {
+	return &api.Database{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "database",
+			Namespace: "ydb",
+		},
+		Spec: api.DatabaseSpec{
+			DatabaseClusterSpec: api.DatabaseClusterSpec{
+				StorageClusterRef: api.StorageClusterRef{NamespacedRef: api.NamespacedRef{Name: "storage", Namespace: "ydb"}},
+				Image:             &api.PodImage{Name: "cr.yandex/ydb/ydb:stable"},
+				Service: &api.DatabaseServices{
+					GRPC: api.GRPCService{
+						TLSConfiguration: &api.TLSConfiguration{},
+					},
+					Interconnect: api.InterconnectService{
+						TLSConfiguration: &api.TLSConfiguration{},
+					},
+					Datastreams: api.DatastreamsService{
+						TLSConfiguration: &api.TLSConfiguration{},
+					},
+				},
+			},
+			DatabaseNodeSpec: api.DatabaseNodeSpec{
+				Nodes: 1,
+			},
+		},
+	}
+}
+
+func buildDatabaseAffinity(database *api.Database, storage *api.Storage) *corev1.Affinity {
+	builder := resources.NewDatabase(database)
+	builder.Storage = storage
+
+	for _, resourceBuilder := range builder.GetResourceBuilders(nil) {
+		if stsBuilder, ok := resourceBuilder.(*resources.DatabaseStatefulSetBuilder); ok {
+			return stsBuilder.Spec.Affinity
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Database storage affinity", func() {
+	It("is not set unless requested", func() {
+		Expect(buildDatabaseAffinity(newTestDatabase(), newTestStorage())).To(BeNil())
+	})
+
+	It("prefers topology domains of Storage pods by default", func() {
+		database := newTestDatabase()
+		database.Spec.StorageAffinity = &api.StorageAffinity{}
+
+		affinity := buildDatabaseAffinity(database, newTestStorage())
+		Expect(affinity).NotTo(BeNil())
+		Expect(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		Expect(affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+
+		term := affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0]
+		Expect(term.Weight).To(Equal(int32(api.DefaultStorageAffinityWeight)))
+		Expect(term.PodAffinityTerm.TopologyKey).To(Equal(api.DefaultStorageAffinityTopologyKey))
+		Expect(term.PodAffinityTerm.Namespaces).To(Equal([]string{"ydb"}))
+		Expect(term.PodAffinityTerm.LabelSelector.MatchLabels).To(Equal(map[string]string{
+			labels.InstanceKey:  "storage",
+			labels.ComponentKey: labels.StorageComponent,
+		}))
+	})
+
+	It("requires topology domains of Storage pods and keeps user affinity", func() {
+		database := newTestDatabase()
+		database.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{},
+		}
+		database.Spec.StorageAffinity = &api.StorageAffinity{
+			Mode:        api.StorageAffinityRequired,
+			TopologyKey: "kubernetes.io/hostname",
+		}
+
+		affinity := buildDatabaseAffinity(database, newTestStorage())
+		Expect(affinity.NodeAffinity).NotTo(BeNil())
+		Expect(affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		Expect(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+		Expect(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey).
+			To(Equal("kubernetes.io/hostname"))
+		Expect(database.Spec.Affinity.PodAffinity).To(BeNil())
+	})
+})
+
+var _ = Describe("Database schema operation quotas", func() {
+	It("changes checksum together with quotas", func() {
+		database := newTestDatabase()
+		database.Spec.Resources = &api.DatabaseResources{}
+		builder := resources.NewDatabase(database)
+		Expect(builder.GetSchemaOperationQuotasHash()).To(BeEmpty())
+
+		database.Spec.Resources.SchemaOperationQuotas = []api.SchemaOperationQuota{{BucketSize: 10, BucketSeconds: 60}}
+		builder = resources.NewDatabase(database)
+		hash := builder.GetSchemaOperationQuotasHash()
+		Expect(hash).NotTo(BeEmpty())
+
+		database.Spec.Resources.SchemaOperationQuotas[0].BucketSeconds = 3600
+		builder = resources.NewDatabase(database)
+		Expect(builder.GetSchemaOperationQuotasHash()).NotTo(Equal(hash))
+	})
+})
+
+// buildDatabase builds encryption key config and StatefulSet of Database
+func buildDatabase(database *api.Database) (*corev1.ConfigMap, *appsv1.StatefulSet) {
+	builder := resources.NewDatabase(database)
+	builder.Storage = newTestStorage()
+
+	keyConfig := &corev1.ConfigMap{}
+	sts := &appsv1.StatefulSet{}
+	for _, resourceBuilder := range builder.GetResourceBuilders(nil) {
+		switch resourceBuilder.(type) {
+		case *resources.EncryptionConfigBuilder:
+			Expect(resourceBuilder.Build(keyConfig)).To(Succeed())
+		case *resources.DatabaseStatefulSetBuilder:
+			Expect(resourceBuilder.Build(sts)).To(Succeed())
+		}
+	}
+	return keyConfig, sts
+}
+
+func encryptionKeySecretVolume(sts *appsv1.StatefulSet) *corev1.SecretVolumeSource {
+	for _, volume := range sts.Spec.Template.Spec.Volumes {
+		if volume.Name == "encryption-key" {
+			return volume.Secret
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Database encryption key rotation", func() {
+	It("reads the only key from referenced Secret before versions are kept", func() {
+		database := newTestDatabase()
+		database.Spec.Encryption = &api.EncryptionConfig{
+			Enabled: true,
+			Key:     &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "key"}, Key: "key"},
+		}
+
+		keyConfig, sts := buildDatabase(database)
+		Expect(strings.Count(keyConfig.Data[api.DatabaseEncryptionKeyConfigFile], "Keys {")).To(Equal(1))
+		Expect(sts.Spec.Template.Annotations).NotTo(HaveKey(annotations.EncryptionKeyVersion))
+		Expect(encryptionKeySecretVolume(sts).SecretName).To(Equal("key"))
+	})
+
+	It("reads every version of rotated key from Secret with encryption keys", func() {
+		database := newTestDatabase()
+		database.Spec.Encryption = &api.EncryptionConfig{
+			Enabled: true,
+			Key:     &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "key"}, Key: "key"},
+		}
+		database.Status.EncryptionKeyVersion = 2
+
+		keyConfig, sts := buildDatabase(database)
+		Expect(keyConfig.Data[api.DatabaseEncryptionKeyConfigFile]).To(ContainSubstring("/database_encryption/v1\""))
+		Expect(keyConfig.Data[api.DatabaseEncryptionKeyConfigFile]).To(ContainSubstring("/database_encryption/v2\""))
+		Expect(keyConfig.Data[api.DatabaseEncryptionKeyConfigFile]).To(ContainSubstring("Version: 2"))
+		Expect(sts.Spec.Template.Annotations).To(HaveKeyWithValue(annotations.EncryptionKeyVersion, "2"))
+		Expect(encryptionKeySecretVolume(sts).SecretName).To(Equal("database-encryption-keys"))
+		Expect(encryptionKeySecretVolume(sts).Items).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Database node pool", func() {
+	It("schedules pods to nodes of the pool and labels Database nodes", func() {
+		database := newTestDatabase()
+		database.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+		database.Spec.NodePool = &api.NodePool{Name: "compute"}
+
+		_, sts := buildDatabase(database)
+		Expect(sts.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{
+			"disktype":                  "ssd",
+			api.DefaultNodePoolLabelKey: "compute",
+		}))
+		Expect(database.Spec.NodeSelector).NotTo(HaveKey(api.DefaultNodePoolLabelKey))
+		Expect(strings.Join(sts.Spec.Template.Spec.Containers[0].Args, " ")).
+			To(ContainSubstring("--label node_pool=compute"))
+	})
+
+	It("uses custom label key of the pool", func() {
+		database := newTestDatabase()
+		database.Spec.NodePool = &api.NodePool{Name: "compute", LabelKey: "example.com/pool"}
+
+		_, sts := buildDatabase(database)
+		Expect(sts.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"example.com/pool": "compute"}))
+	})
+})
+
+var _ = Describe("Database StatefulSet revision history", func() {
+	It("keeps default number of revisions unless requested", func() {
+		database := newTestDatabase()
+		_, sts := buildDatabase(database)
+		Expect(*sts.Spec.RevisionHistoryLimit).To(Equal(int32(api.DefaultRevisionHistoryLimit)))
+
+		database.Spec.RevisionHistoryLimit = ptr.Int32(0)
+		_, sts = buildDatabase(database)
+		Expect(*sts.Spec.RevisionHistoryLimit).To(Equal(int32(0)))
+	})
+})
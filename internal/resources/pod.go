@@ -0,0 +1,134 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+)
+
+// PodProblem describes a pod which is not expected to become ready
+// without user intervention.
+type PodProblem struct {
+	Reason  string
+	Message string
+}
+
+// FindPodProblem returns problem of the first pod which is stuck pulling
+// image, crash looping or can not be scheduled.
+func FindPodProblem(pods []corev1.Pod) (PodProblem, bool) {
+	for _, pod := range pods {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled &&
+				condition.Status == corev1.ConditionFalse &&
+				condition.Reason == corev1.PodReasonUnschedulable {
+				return PodProblem{
+					Reason:  ReasonUnschedulable,
+					Message: fmt.Sprintf("Pod %s is unschedulable: %s", pod.Name, condition.Message),
+				}, true
+			}
+		}
+
+		statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.State.Waiting == nil {
+				continue
+			}
+			switch status.State.Waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+				return PodProblem{
+					Reason: ReasonImagePullError,
+					Message: fmt.Sprintf("Pod %s failed to pull image of container %s: %s",
+						pod.Name, status.Name, status.State.Waiting.Message),
+				}, true
+			case "CrashLoopBackOff":
+				return PodProblem{
+					Reason: ReasonCrashLoopBackOff,
+					Message: fmt.Sprintf("Pod %s container %s is crash looping: %s",
+						pod.Name, status.Name, status.State.Waiting.Message),
+				}, true
+			}
+		}
+	}
+	return PodProblem{}, false
+}
+
+// ReadyPodsByOrdinal returns running Pods which report Ready condition,
+// ordered by StatefulSet ordinal, so that commands are tried in the first
+// pod and fall back to the next ones. Terminating Pods are skipped even if
+// still Ready, since they are about to be restarted by rolling update.
+func ReadyPodsByOrdinal(pods []corev1.Pod) []corev1.Pod {
+	var ready []corev1.Pod
+	for i := range pods {
+		if pods[i].Status.Phase != corev1.PodRunning || pods[i].DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pods[i].Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready = append(ready, pods[i])
+				break
+			}
+		}
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		left, right := podOrdinal(ready[i].Name), podOrdinal(ready[j].Name)
+		if left != right {
+			return left < right
+		}
+		return ready[i].Name < ready[j].Name
+	})
+	return ready
+}
+
+// podOrdinal returns ordinal of StatefulSet pod, pods without one go last
+func podOrdinal(name string) int {
+	idx := strings.LastIndex(name, "-")
+	if idx < 0 {
+		return math.MaxInt
+	}
+	ordinal, err := strconv.Atoi(name[idx+1:])
+	if err != nil {
+		return math.MaxInt
+	}
+	return ordinal
+}
+
+// SetGOMAXPROCS sets GOMAXPROCS environment variable of containers with CPU
+// limit to the limit rounded up to a whole CPU, e.g. `2` for `1500m`, so
+// that their processes respect cgroup CPU quota. Explicitly set values are
+// kept, containers are modified in place.
+func SetGOMAXPROCS(containers []corev1.Container) {
+	for i := range containers {
+		limit, ok := containers[i].Resources.Limits[corev1.ResourceCPU]
+		if !ok || limit.IsZero() {
+			continue
+		}
+		if hasEnv(containers[i].Env, gomaxprocsEnvName) {
+			continue
+		}
+		procs := (limit.MilliValue() + 999) / 1000
+		// Env may share backing array with spec of the resource, so the
+		// new slice is always allocated
+		env := make([]corev1.EnvVar, 0, len(containers[i].Env)+1)
+		env = append(env, containers[i].Env...)
+		containers[i].Env = append(env, corev1.EnvVar{
+			Name:  gomaxprocsEnvName,
+			Value: strconv.FormatInt(procs, 10),
+		})
+	}
+}
+
+func hasEnv(env []corev1.EnvVar, name string) bool {
+	for _, e := range env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
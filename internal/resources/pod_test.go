@@ -0,0 +1,134 @@
+package resources_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+func newWaitingPod(reason string) corev1.Pod {
+	pod := corev1.Pod{}
+	pod.Name = "storage-0"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: "ydb-storage",
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: "details"},
+		},
+	}}
+	return pod
+}
+
+var _ = Describe("Pod problems", func() {
+	It("detects image pull error", func() {
+		problem, found := resources.FindPodProblem([]corev1.Pod{newWaitingPod("ImagePullBackOff")})
+		Expect(found).To(BeTrue())
+		Expect(problem.Reason).To(Equal(ReasonImagePullError))
+		Expect(problem.Message).To(ContainSubstring("storage-0"))
+	})
+
+	It("detects crash looping container", func() {
+		problem, found := resources.FindPodProblem([]corev1.Pod{newWaitingPod("CrashLoopBackOff")})
+		Expect(found).To(BeTrue())
+		Expect(problem.Reason).To(Equal(ReasonCrashLoopBackOff))
+	})
+
+	It("detects unschedulable pod", func() {
+		pod := corev1.Pod{}
+		pod.Name = "storage-1"
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type:    corev1.PodScheduled,
+			Status:  corev1.ConditionFalse,
+			Reason:  corev1.PodReasonUnschedulable,
+			Message: "0/3 nodes are available",
+		}}
+		problem, found := resources.FindPodProblem([]corev1.Pod{pod})
+		Expect(found).To(BeTrue())
+		Expect(problem.Reason).To(Equal(ReasonUnschedulable))
+		Expect(problem.Message).To(ContainSubstring("0/3 nodes are available"))
+	})
+
+	It("ignores starting containers", func() {
+		_, found := resources.FindPodProblem([]corev1.Pod{newWaitingPod("ContainerCreating")})
+		Expect(found).To(BeFalse())
+	})
+})
+
+func newPod(name string, phase corev1.PodPhase, ready bool) corev1.Pod {
+	pod := corev1.Pod{}
+	pod.Name = name
+	pod.Status.Phase = phase
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+	return pod
+}
+
+var _ = Describe("Ready pods", func() {
+	It("orders ready pods by ordinal skipping not ready ones", func() {
+		pods := resources.ReadyPodsByOrdinal([]corev1.Pod{
+			newPod("storage-10", corev1.PodRunning, true),
+			newPod("storage-0", corev1.PodRunning, false),
+			newPod("storage-2", corev1.PodRunning, true),
+			newPod("storage-1", corev1.PodPending, false),
+		})
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		Expect(names).To(Equal([]string{"storage-2", "storage-10"}))
+	})
+
+	It("skips terminating pods", func() {
+		terminating := newPod("storage-0", corev1.PodRunning, true)
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		pods := resources.ReadyPodsByOrdinal([]corev1.Pod{
+			terminating,
+			newPod("storage-1", corev1.PodRunning, true),
+		})
+		Expect(pods).To(HaveLen(1))
+		Expect(pods[0].Name).To(Equal("storage-1"))
+	})
+})
+
+func newContainerWithCPULimit(limit string) corev1.Container {
+	return corev1.Container{
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(limit)},
+		},
+	}
+}
+
+var _ = Describe("GOMAXPROCS", func() {
+	It("is set to CPU limit rounded up to a whole CPU", func() {
+		containers := []corev1.Container{
+			newContainerWithCPULimit("2"),
+			newContainerWithCPULimit("1500m"),
+			newContainerWithCPULimit("100m"),
+			{},
+		}
+		resources.SetGOMAXPROCS(containers)
+
+		Expect(containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: "GOMAXPROCS", Value: "2"}))
+		Expect(containers[1].Env).To(ConsistOf(corev1.EnvVar{Name: "GOMAXPROCS", Value: "2"}))
+		Expect(containers[2].Env).To(ConsistOf(corev1.EnvVar{Name: "GOMAXPROCS", Value: "1"}))
+		Expect(containers[3].Env).To(BeEmpty())
+	})
+
+	It("keeps explicitly set value", func() {
+		container := newContainerWithCPULimit("4")
+		container.Env = []corev1.EnvVar{{Name: "GOMAXPROCS", Value: "8"}}
+		containers := []corev1.Container{container}
+		resources.SetGOMAXPROCS(containers)
+
+		Expect(containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: "GOMAXPROCS", Value: "8"}))
+	})
+})
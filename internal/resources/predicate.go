@@ -78,7 +78,8 @@ func IsRemoteDatabaseNodeSetCreatePredicate() predicate.Predicate {
 func IgnoreDeleteStateUnknownPredicate() predicate.Predicate {
 	return predicate.Funcs{
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			// Evaluates to false if the object has been confirmed deleted.
+			// Evaluates to true if the object has been confirmed deleted,
+			// so that deleted owned resources are recreated immediately.
 			return !e.DeleteStateUnknown
 		},
 	}
@@ -106,7 +106,7 @@ func (b *RemoteDatabaseNodeSetResource) GetRemoteObjects(
 	}
 
 	// sync ConfigMap
-	if b.Spec.Configuration != "" {
+	if b.Spec.HasOwnConfiguration() {
 		remoteObjects = append(remoteObjects,
 			&corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
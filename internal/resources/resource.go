@@ -3,14 +3,22 @@ package resources
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"time"
 
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	"github.com/golang-jwt/jwt/v4"
 	ydb "github.com/ydb-platform/ydb-go-sdk/v3"
+	ydbConfig "github.com/ydb-platform/ydb-go-sdk/v3/config"
 	ydbCredentials "github.com/ydb-platform/ydb-go-sdk/v3/credentials"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -18,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -26,6 +35,7 @@ import (
 
 	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	ydbannotations "github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/connection"
 )
 
@@ -34,6 +44,7 @@ const (
 	InterconnectServiceNameFormat = "%s-interconnect"
 	StatusServiceNameFormat       = "%s-status"
 	DatastreamsServiceNameFormat  = "%s-datastreams"
+	PostgresServiceNameFormat     = "%s-postgres"
 
 	grpcTLSVolumeName         = "grpc-tls-volume"
 	interconnectTLSVolumeName = "interconnect-tls-volume"
@@ -42,18 +53,23 @@ const (
 	statusOriginTLSVolumeName = "status-origin-tls-volume"
 
 	grpcTLSVolumeMountPath         = "/tls/grpc"
-	interconnectTLSVolumeMountPath = "/tls/interconnect"
+	interconnectTLSVolumeMountPath = api.InterconnectTLSDir
 	datastreamsTLSVolumeMountPath  = "/tls/datastreams"
 	statusTLSVolumeMountPath       = "/tls/status"
 	statusOriginTLSVolumeMountPath = "/tls/status-origin"
 
 	InitJobNameFormat             = "%s-blobstorage-init"
+	CloneJobNameFormat            = "%s-clone"
 	OperatorTokenSecretNameFormat = "%s-operator-token"
+	ConnectionSecretNameFormat    = "%s-connection"
 	EncryptionKeyConfigNameFormat = "%s-encryption-key"
+	EncryptionSecretNameFormat    = "%s-encryption-keys"
 
 	systemCertsVolumeName   = "init-main-shared-certs-volume"
 	localCertsVolumeName    = "init-main-shared-source-dir-volume"
 	operatorTokenVolumeName = "operator-token-volume"
+	scratchSpaceVolumeName  = "scratch-space-volume"
+	logVolumeName           = "log-volume"
 
 	wellKnownDirForAdditionalSecrets        = "/opt/ydb/secrets"
 	wellKnownDirForAdditionalVolumes        = "/opt/ydb/volumes"
@@ -62,6 +78,7 @@ const (
 	wellKnownNameForTLSCertificate          = "tls.crt"
 	wellKnownNameForTLSPrivateKey           = "tls.key"
 	wellKnownNameForEncryptionKeySecret     = "key"
+	wellKnownFormatForEncryptionKeyVersion  = "v%d"
 
 	caBundleEnvName         = "CA_BUNDLE"
 	caBundleFileName        = "userCABundle.crt"
@@ -72,6 +89,8 @@ const (
 	localCertsDir  = "/usr/local/share/ca-certificates"
 	systemCertsDir = "/etc/ssl/certs"
 
+	gomaxprocsEnvName = "GOMAXPROCS"
+
 	encryptionKeyConfigVolumeName = "encryption-config"
 	encryptionKeySecretVolumeName = "encryption-key"
 )
@@ -179,10 +198,20 @@ func CreateOrUpdateOrMaybeIgnore(
 		}
 	}
 
+	// Selector of Service is mutable and is always brought to the desired one,
+	// so that Services created with an older labels schema keep routing to pods
+	selectorChanged := false
+	if updated, ok := obj.(*corev1.Service); ok {
+		selectorChanged = !CompareMaps(updated.Spec.Selector, existing.(*corev1.Service).Spec.Selector)
+	}
+
 	changed, err := CheckObjectUpdatedIgnoreStatus(existing, obj)
-	if err != nil || !changed {
+	if err != nil {
 		return ctrlutil.OperationResultNone, err
 	}
+	if !changed && !selectorChanged {
+		return ctrlutil.OperationResultNone, nil
+	}
 	if err := annotator.SetLastAppliedAnnotation(obj); err != nil {
 		return ctrlutil.OperationResultNone, err
 	}
@@ -443,6 +472,43 @@ func getYDBOauth2Credentials(
 		))
 }
 
+func getYDBServiceAccountKeyCredentials(
+	ctx context.Context,
+	storage *api.Storage,
+	restConfig *rest.Config,
+) (ydbCredentials.Credentials, error) {
+	auth := storage.Spec.OperatorConnection
+	data, err := GetSecretKey(
+		ctx,
+		storage.Namespace,
+		restConfig,
+		auth.ServiceAccountKey.SecretKeyRef,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get service account key for ServiceAccountKey from secret: %s, key: %s, error: %w",
+			auth.ServiceAccountKey.SecretKeyRef.Name,
+			auth.ServiceAccountKey.SecretKeyRef.Key,
+			err)
+	}
+
+	key, err := cms.ParseServiceAccountKey([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse service account key from secret: %s, key: %s, error: %w",
+			auth.ServiceAccountKey.SecretKeyRef.Name,
+			auth.ServiceAccountKey.SecretKeyRef.Key,
+			err,
+		)
+	}
+
+	return cms.SharedIAMTokenCredentials(
+		key,
+		auth.ServiceAccountKey.Endpoint,
+		auth.ServiceAccountKey.Audience,
+	)
+}
+
 func GetYDBCredentials(
 	ctx context.Context,
 	storage *api.Storage,
@@ -479,6 +545,10 @@ func GetYDBCredentials(
 		return getYDBOauth2Credentials(ctx, storage, restConfig)
 	}
 
+	if auth.ServiceAccountKey != nil {
+		return getYDBServiceAccountKeyCredentials(ctx, storage, restConfig)
+	}
+
 	return nil, errors.New("unsupported auth type for GetYDBCredentials")
 }
 
@@ -523,6 +593,81 @@ func GetYDBTLSOption(
 	return ydb.WithCertificatesFromPem(caBundle), nil
 }
 
+// GetYDBKeepaliveOption returns keepalive of operator gRPC connections to
+// Storage matching `spec.keepalive.grpc`, see GetYDBKeepaliveParams.
+// Defaults of SDK are kept when keepalive is not set.
+func GetYDBKeepaliveOption(storage *api.Storage) ydb.Option {
+	params, ok := GetYDBKeepaliveParams(storage)
+	if !ok {
+		return ydb.MergeOptions()
+	}
+	return ydb.With(ydbConfig.WithGrpcOptions(grpc.WithKeepaliveParams(params)))
+}
+
+// GetYDBKeepaliveParams returns keepalive parameters matching
+// `spec.keepalive.grpc`: ping is sent after the idle timeout and connection
+// is closed when no ack is received until all probes of nodes would be
+// exhausted. ok is false when keepalive is not set.
+func GetYDBKeepaliveParams(storage *api.Storage) (keepalive.ClientParameters, bool) {
+	if storage.Spec.Keepalive == nil || storage.Spec.Keepalive.GRPC == nil {
+		return keepalive.ClientParameters{}, false
+	}
+	spec := storage.Spec.Keepalive.GRPC
+
+	probeInterval, maxProbeCount := int32(10), int32(3)
+	if spec.ProbeIntervalSeconds != nil {
+		probeInterval = *spec.ProbeIntervalSeconds
+	}
+	if spec.MaxProbeCount != nil {
+		maxProbeCount = *spec.MaxProbeCount
+	}
+
+	params := ydbConfig.DefaultGrpcConnectionPolicy
+	if spec.IdleTimeoutSeconds != nil {
+		params.Time = time.Duration(*spec.IdleTimeoutSeconds) * time.Second
+	}
+	params.Timeout = time.Duration(probeInterval*maxProbeCount) * time.Second
+
+	return params, true
+}
+
+// GetStatusServiceTLSConfig returns TLS config trusting CA of the Storage
+// status service, or nil if status service is insecure.
+func GetStatusServiceTLSConfig(
+	ctx context.Context,
+	storage *api.Storage,
+	restConfig *rest.Config,
+) (*tls.Config, error) {
+	if !storage.IsStatusEndpointSecure() {
+		return nil, nil
+	}
+
+	tlsConfig := storage.Spec.Service.Status.TLSConfiguration
+	caBody, err := GetSecretKey(
+		ctx,
+		storage.Namespace,
+		restConfig,
+		&tlsConfig.CertificateAuthority,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get CA for storage status service from secret: %s, key: %s, error: %w",
+			tlsConfig.CertificateAuthority.Name,
+			tlsConfig.CertificateAuthority.Key,
+			err)
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM([]byte(caBody)) {
+		return nil, errors.New("failed to parse CA for storage status service")
+	}
+
+	return &tls.Config{
+		RootCAs:    certPool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
 func buildCAStorePatchingCommandArgs(
 	caBundle string,
 	grpcService api.GRPCService,
@@ -564,6 +709,40 @@ func buildCAStorePatchingCommandArgs(
 	return command, args
 }
 
+// SyncErrors are errors of syncing resources by builders keyed by resource,
+// collected so that failure of one resource does not prevent syncing others
+type SyncErrors map[string]error
+
+// Add records the error of syncing obj
+func (e SyncErrors) Add(obj client.Object, err error) {
+	e[fmt.Sprintf("%s %s", reflect.TypeOf(obj).Elem().Name(), obj.GetName())] = err
+}
+
+// Aggregate returns errors of all the resources ordered by resource, or nil
+func (e SyncErrors) Aggregate() error {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		errs = append(errs, fmt.Errorf("%s: %w", key, e[key]))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ManagedResourceOf returns reference to obj reported in status of the
+// resource it was created for
+func ManagedResourceOf(obj client.Object) api.ManagedResource {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.TypeOf(obj).Elem().Name()
+	}
+	return api.ManagedResource{Kind: kind, Name: obj.GetName()}
+}
+
 func SHAChecksum(text string) string {
 	hasher := sha256.New()
 	hasher.Write([]byte(text))
@@ -0,0 +1,212 @@
+package resources_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+var _ = Describe("Resources sync errors", func() {
+	It("are nil when every resource is synced", func() {
+		Expect(resources.SyncErrors{}.Aggregate()).To(BeNil())
+	})
+
+	It("are aggregated in order of resources", func() {
+		syncErrors := resources.SyncErrors{}
+		syncErrors.Add(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "storage-grpc"}}, errors.New("invalid port"))
+		syncErrors.Add(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "storage"}}, errors.New("forbidden"))
+		syncErrors.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "storage"}}, errors.New("too long"))
+
+		Expect(syncErrors.Aggregate()).To(MatchError(
+			"[ConfigMap storage: too long, Service storage-grpc: invalid port, StatefulSet storage: forbidden]",
+		))
+	})
+})
+
+var _ = Describe("Managed resources", func() {
+	It("list every object built for Storage", func() {
+		storage := newTestStorage()
+		storage.Spec.Monitoring = &api.MonitoringOptions{}
+		cluster := resources.NewCluster(storage)
+
+		var managed []api.ManagedResource
+		for _, builder := range cluster.GetResourceBuilders(nil) {
+			managed = append(managed, resources.ManagedResourceOf(builder.Placeholder(storage)))
+		}
+		Expect(managed).To(ContainElements(
+			api.ManagedResource{Kind: "ConfigMap", Name: "storage"},
+			api.ManagedResource{Kind: "Service", Name: "storage-grpc"},
+			api.ManagedResource{Kind: "StatefulSet", Name: "storage"},
+		))
+	})
+})
+
+var _ = Describe("Storage resources labels", func() {
+	buildServices := func(storage *api.Storage) []*corev1.Service {
+		cluster := resources.NewCluster(storage)
+		var services []*corev1.Service
+		for _, builder := range cluster.GetResourceBuilders(nil) {
+			if serviceBuilder, ok := builder.(*resources.ServiceBuilder); ok {
+				service := serviceBuilder.Placeholder(storage).(*corev1.Service)
+				Expect(serviceBuilder.Build(service)).To(Succeed())
+				services = append(services, service)
+			}
+		}
+		return services
+	}
+
+	It("are reapplied to Services without changing their selectors", func() {
+		storage := newTestStorage()
+		storage.Spec.Monitoring = &api.MonitoringOptions{}
+		before := buildServices(storage)
+
+		storage.Spec.AdditionalLabels = map[string]string{"team": "ydb"}
+		after := buildServices(storage)
+
+		Expect(after).To(HaveLen(len(before)))
+		for i, service := range after {
+			Expect(service.Labels).To(HaveKeyWithValue("team", "ydb"))
+			Expect(service.Spec.Selector).To(Equal(before[i].Spec.Selector))
+			Expect(service.Spec.Selector).NotTo(HaveKey("team"))
+		}
+	})
+
+	It("applies annotations of each Service separately", func() {
+		storage := newTestStorage()
+		storage.Spec.Monitoring = &api.MonitoringOptions{}
+		storage.Spec.Service.GRPC.AdditionalAnnotations = map[string]string{
+			"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+		}
+
+		for _, service := range buildServices(storage) {
+			if service.Name == "storage-grpc" {
+				Expect(service.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+			} else {
+				Expect(service.Annotations).To(BeEmpty())
+			}
+		}
+	})
+})
+
+var _ = Describe("Service selector", func() {
+	It("is reconciled from outdated labels schema", func() {
+		storage := newTestStorage()
+		storage.Spec.Monitoring = &api.MonitoringOptions{}
+		cluster := resources.NewCluster(storage)
+
+		var builder *resources.ServiceBuilder
+		for _, resourceBuilder := range cluster.GetResourceBuilders(nil) {
+			if serviceBuilder, ok := resourceBuilder.(*resources.ServiceBuilder); ok {
+				builder = serviceBuilder
+				break
+			}
+		}
+		Expect(builder).NotTo(BeNil())
+
+		// Service applied by an older operator version, which selector had
+		// a label missing in the current schema
+		outdated := builder.Placeholder(storage).(*corev1.Service)
+		Expect(builder.Build(outdated)).To(Succeed())
+		Expect(patch.NewAnnotator(annotations.LastAppliedAnnotation).SetLastAppliedAnnotation(outdated)).To(Succeed())
+		outdated.Spec.Selector = resources.CopyDict(builder.SelectorLabels)
+		outdated.Spec.Selector["ydb-cluster"] = "kind-storage"
+		c := fake.NewClientBuilder().WithObjects(outdated).Build()
+
+		service := builder.Placeholder(storage)
+		result, err := resources.CreateOrUpdateOrMaybeIgnore(context.Background(), c, service, func() error {
+			return builder.Build(service)
+		}, func(_, _ runtime.Object) bool { return false })
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(controllerutil.OperationResultUpdated))
+
+		reconciled := &corev1.Service{}
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(service), reconciled)).To(Succeed())
+		Expect(reconciled.Spec.Selector).To(Equal(builder.SelectorLabels))
+	})
+})
+
+var _ = Describe("Storage DefineBox hash", func() {
+	defineBoxHash := func(storage *api.Storage) string {
+		cluster := resources.NewCluster(storage)
+		return cluster.GetDefineBoxHash()
+	}
+
+	var storage *api.Storage
+
+	BeforeEach(func() {
+		storage = newTestStorage()
+		storage.Spec.Configuration = "host_configs:\n- host_config_id: 1\n  drive:\n  - path: /dev/kikimr_ssd_00\n    type: SSD\n"
+	})
+
+	It("changes with topology of the Storage", func() {
+		initialHash := defineBoxHash(storage)
+		Expect(initialHash).ToNot(BeEmpty())
+
+		storage.Spec.Nodes = 3
+		Expect(defineBoxHash(storage)).ToNot(Equal(initialHash))
+	})
+
+	It("ignores configuration outside of the box definition", func() {
+		initialHash := defineBoxHash(storage)
+
+		storage.Spec.Configuration += "log_config:\n  default_level: 5\n"
+		Expect(defineBoxHash(storage)).To(Equal(initialHash))
+	})
+
+	It("is empty for configuration v2", func() {
+		storage.Spec.ConfigurationVersion = api.ConfigurationV2
+		Expect(defineBoxHash(storage)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("YDB keepalive", func() {
+	It("is not set without gRPC keepalive of Storage", func() {
+		storage := newTestStorage()
+		_, ok := resources.GetYDBKeepaliveParams(storage)
+		Expect(ok).To(BeFalse())
+
+		storage.Spec.Keepalive = &api.KeepaliveSpec{Interconnect: &api.InterconnectKeepalive{}}
+		_, ok = resources.GetYDBKeepaliveParams(storage)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("uses defaults of probes", func() {
+		storage := newTestStorage()
+		storage.Spec.Keepalive = &api.KeepaliveSpec{GRPC: &api.GRPCKeepalive{
+			IdleTimeoutSeconds: ptr.Int32(90),
+		}}
+		params, ok := resources.GetYDBKeepaliveParams(storage)
+		Expect(ok).To(BeTrue())
+		Expect(params.Time).To(Equal(90 * time.Second))
+		Expect(params.Timeout).To(Equal(30 * time.Second))
+	})
+
+	It("closes connection after all probes are exhausted", func() {
+		storage := newTestStorage()
+		storage.Spec.Keepalive = &api.KeepaliveSpec{GRPC: &api.GRPCKeepalive{
+			IdleTimeoutSeconds:   ptr.Int32(30),
+			ProbeIntervalSeconds: ptr.Int32(5),
+			MaxProbeCount:        ptr.Int32(4),
+		}}
+		params, ok := resources.GetYDBKeepaliveParams(storage)
+		Expect(ok).To(BeTrue())
+		Expect(params.Time).To(Equal(30 * time.Second))
+		Expect(params.Timeout).To(Equal(20 * time.Second))
+	})
+})
@@ -0,0 +1,94 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
+)
+
+// RolloutStuckTimeoutFromAnnotations returns the period after which rollout
+// without progress is reported as stuck, DefaultRolloutStuckTimeout is
+// returned together with error when AnnotationRolloutStuckTimeout is invalid
+func RolloutStuckTimeoutFromAnnotations(annotations map[string]string) (time.Duration, error) {
+	timeout, ok, err := api.ParseDurationAnnotation(annotations, api.AnnotationRolloutStuckTimeout)
+	if err != nil || !ok {
+		return DefaultRolloutStuckTimeout, err
+	}
+	return timeout, nil
+}
+
+// IsRollingOut reports whether StatefulSet controller is expected to replace
+// pods with the update revision. Rollout is awaited by user with OnDelete
+// strategy and once pods up to the partition are updated.
+func IsRollingOut(sts *appsv1.StatefulSet) bool {
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType ||
+		sts.Status.UpdateRevision == "" ||
+		sts.Status.UpdateRevision == sts.Status.CurrentRevision {
+		return false
+	}
+
+	if sts.Spec.UpdateStrategy.RollingUpdate != nil &&
+		sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
+		sts.Spec.Replicas != nil {
+		partition := *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+		if partition > 0 && sts.Status.UpdatedReplicas >= *sts.Spec.Replicas-partition &&
+			sts.Status.ReadyReplicas == *sts.Spec.Replicas {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetRolloutStuckCondition tracks rollout of StatefulSet in RolloutStuck
+// condition. Condition is False while rollout advances, its transition time
+// is reset on every progress, and becomes True when rollout has not advanced
+// for timeout. Condition is removed once rollout is finished. Returns whether
+// conditions are changed.
+func SetRolloutStuckCondition(conditions *[]metav1.Condition, sts *appsv1.StatefulSet, timeout time.Duration) bool {
+	condition := meta.FindStatusCondition(*conditions, RolloutStuckCondition)
+	if !IsRollingOut(sts) {
+		if condition == nil {
+			return false
+		}
+		meta.RemoveStatusCondition(conditions, RolloutStuckCondition)
+		return true
+	}
+
+	progress := fmt.Sprintf(
+		"Rollout of StatefulSet %s to revision %s: %d/%d pods updated, %d ready",
+		sts.Name,
+		sts.Status.UpdateRevision,
+		sts.Status.UpdatedReplicas,
+		sts.Status.Replicas,
+		sts.Status.ReadyReplicas,
+	)
+	if condition == nil || condition.Message != progress {
+		meta.RemoveStatusCondition(conditions, RolloutStuckCondition)
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    RolloutStuckCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInProgress,
+			Message: progress,
+		})
+		return true
+	}
+
+	if condition.Status != metav1.ConditionTrue && time.Since(condition.LastTransitionTime.Time) >= timeout {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    RolloutStuckCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonFailed,
+			Message: progress,
+		})
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package resources_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+func newRollingOutStatefulSet() *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "database"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.Int32(3),
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        3,
+			ReadyReplicas:   2,
+			UpdatedReplicas: 1,
+			CurrentRevision: "database-1",
+			UpdateRevision:  "database-2",
+		},
+	}
+}
+
+var _ = Describe("StatefulSet rollout stuck detection", func() {
+	It("does not track StatefulSet without rollout", func() {
+		sts := newRollingOutStatefulSet()
+		sts.Status.CurrentRevision = sts.Status.UpdateRevision
+
+		var conditions []metav1.Condition
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeFalse())
+		Expect(conditions).To(BeEmpty())
+	})
+
+	It("does not track rollout awaited by user", func() {
+		sts := newRollingOutStatefulSet()
+		sts.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+
+		var conditions []metav1.Condition
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeFalse())
+
+		sts = newRollingOutStatefulSet()
+		sts.Status.ReadyReplicas = 3
+		sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.Int32(2)}
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeFalse())
+		Expect(conditions).To(BeEmpty())
+	})
+
+	It("reports rollout without progress for timeout as stuck", func() {
+		sts := newRollingOutStatefulSet()
+
+		var conditions []metav1.Condition
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conditions, constants.RolloutStuckCondition)).To(BeTrue())
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeFalse())
+
+		conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(conditions, constants.RolloutStuckCondition)).To(BeTrue())
+		Expect(conditions[0].Message).To(ContainSubstring("1/3 pods updated"))
+	})
+
+	It("resets timeout when rollout progresses and forgets finished rollout", func() {
+		sts := newRollingOutStatefulSet()
+
+		var conditions []metav1.Condition
+		resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)
+		conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+		sts.Status.UpdatedReplicas = 2
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conditions, constants.RolloutStuckCondition)).To(BeTrue())
+		Expect(time.Since(conditions[0].LastTransitionTime.Time)).To(BeNumerically("<", time.Minute))
+
+		sts.Status.CurrentRevision = sts.Status.UpdateRevision
+		Expect(resources.SetRolloutStuckCondition(&conditions, sts, time.Minute)).To(BeTrue())
+		Expect(conditions).To(BeEmpty())
+	})
+
+	It("reads timeout from annotation", func() {
+		timeout, err := resources.RolloutStuckTimeoutFromAnnotations(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeout).To(Equal(constants.DefaultRolloutStuckTimeout))
+
+		timeout, err = resources.RolloutStuckTimeoutFromAnnotations(map[string]string{
+			api.AnnotationRolloutStuckTimeout: "30m",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeout).To(Equal(30 * time.Minute))
+
+		timeout, err = resources.RolloutStuckTimeoutFromAnnotations(map[string]string{
+			api.AnnotationRolloutStuckTimeout: "-1m",
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(timeout).To(Equal(constants.DefaultRolloutStuckTimeout))
+	})
+})
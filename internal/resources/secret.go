@@ -11,6 +11,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 )
 
 func CheckSecretKey(
@@ -104,3 +106,58 @@ func GetOperatorTokenSecretBuilder(obj client.Object, operatorToken string) Reso
 		Token: operatorToken,
 	}
 }
+
+type DatabaseConnectionSecretBuilder struct {
+	*api.Database
+
+	Name   string
+	Labels map[string]string
+}
+
+func (b *DatabaseConnectionSecretBuilder) Build(obj client.Object) error {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return errors.New("failed to cast to Secret object")
+	}
+
+	if secret.ObjectMeta.Name == "" {
+		secret.ObjectMeta.Name = b.Name
+	}
+	secret.ObjectMeta.Namespace = b.GetNamespace()
+
+	secret.Labels = b.Labels
+
+	proto := api.GRPCProto
+	if b.Spec.Service.GRPC.TLSConfiguration.Enabled {
+		proto = api.GRPCSProto
+	}
+
+	// Serverless database has no nodes of its own, clients
+	// connect to it through the shared database
+	serviceName, serviceNamespace := b.GetName(), b.GetNamespace()
+	if b.Spec.ServerlessResources != nil {
+		serviceName = b.Spec.ServerlessResources.SharedDatabaseRef.Name
+		serviceNamespace = b.Spec.ServerlessResources.SharedDatabaseRef.Namespace
+	}
+
+	secret.Data = map[string][]byte{
+		"endpoint": []byte(fmt.Sprintf("%s%s:%d",
+			proto,
+			fmt.Sprintf(api.GRPCServiceFQDNFormat, serviceName, serviceNamespace),
+			api.GRPCPort,
+		)),
+		"database": []byte(b.GetDatabasePath()),
+	}
+	secret.Type = corev1.SecretTypeOpaque
+
+	return nil
+}
+
+func (b *DatabaseConnectionSecretBuilder) Placeholder(cr client.Object) client.Object {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name,
+			Namespace: cr.GetNamespace(),
+		},
+	}
+}
@@ -1,12 +1,18 @@
 package resources
 
 import (
+	"fmt"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 
 	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	. "github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants" //nolint:revive,stylecheck
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/metrics"
 )
@@ -15,6 +21,72 @@ type StorageClusterBuilder struct {
 	*api.Storage
 }
 
+// GetConfiguration returns the configuration stored in the Storage ConfigMap
+func (b *StorageClusterBuilder) GetConfiguration() string {
+	success, dynconfig, _ := api.ParseDynConfig(b.Spec.Configuration)
+	if !success {
+		// YDBOPS-9722 backward compatibility
+		cfg, _ := api.BuildConfiguration(b.Unwrap(), nil)
+		return string(cfg)
+	}
+
+	cfg, _ := yaml.Marshal(dynconfig.Config)
+	return string(cfg)
+}
+
+// GetDefineBoxHash returns checksum of the box definition which is derived
+// from `hosts` and `host_configs` of the configuration by blobstorage init.
+// Configuration v2 has no explicit box definition, empty hash is returned
+func (b *StorageClusterBuilder) GetDefineBoxHash() string {
+	if b.Spec.ConfigurationVersion == api.ConfigurationV2 {
+		return ""
+	}
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(b.GetConfiguration()), &config); err != nil {
+		return ""
+	}
+
+	box, _ := yaml.Marshal(map[string]interface{}{
+		"hosts":        config["hosts"],
+		"host_configs": config["host_configs"],
+	})
+	return SHAChecksum(string(box))
+}
+
+// GetConfigValidateCommand returns command validating the configuration
+// against the running cluster with `config validate`, to be executed in a
+// Storage pod. Configuration is passed as an argument of the shell, which
+// writes it to a temporary file for ydbd
+func (b *StorageClusterBuilder) GetConfigValidateCommand(config string) []string {
+	tokenArgs := ""
+	if args := b.operatorTokenArgs(); len(args) > 0 {
+		tokenArgs = " " + strings.Join(args, " ")
+	}
+	return []string{
+		"sh", "-c",
+		fmt.Sprintf(
+			`f=$(mktemp) && printf '%%s' "$0" > "$f" && %s/%s%s -s %s admin config validate --yaml-file "$f"; rc=$?; rm -f "$f"; exit $rc`,
+			api.BinariesDir, api.DaemonBinaryName, tokenArgs, b.GetStorageEndpointWithProto(),
+		),
+		config,
+	}
+}
+
+// operatorTokenArgs returns ydbd arguments with the token file of operator
+// connection, which is mounted from the operator token Secret in Storage
+// pods as in the blobstorage init job
+func (b *StorageClusterBuilder) operatorTokenArgs() []string {
+	if b.Spec.OperatorConnection == nil {
+		return nil
+	}
+	secretName := fmt.Sprintf(OperatorTokenSecretNameFormat, b.Name)
+	return []string{
+		"-f",
+		fmt.Sprintf("%s/%s/%s", wellKnownDirForAdditionalSecrets, secretName, wellKnownNameForOperatorToken),
+	}
+}
+
 func NewCluster(ydbCr *api.Storage) StorageClusterBuilder {
 	cr := ydbCr.DeepCopy()
 
@@ -29,8 +101,23 @@ func (b *StorageClusterBuilder) Unwrap() *api.Storage {
 	return b.DeepCopy()
 }
 
+// IsUpgradeRolledBack reports whether the image from the current spec failed
+// to roll out, so that Storage nodes are kept on the previous image until
+// the spec is changed
+func (b *StorageClusterBuilder) IsUpgradeRolledBack() bool {
+	if b.Status.PreviousImage == nil {
+		return false
+	}
+
+	condition := meta.FindStatusCondition(b.Status.Conditions, UpgradeRolledBackCondition)
+	return condition != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == b.Generation
+}
+
 func (b *StorageClusterBuilder) GetResourceBuilders(restConfig *rest.Config) []ResourceBuilder {
 	storageLabels := labels.StorageLabels(b.Unwrap())
+	storageSelectorLabels := labels.StorageSelectorLabels(b.Unwrap())
 
 	statefulSetLabels := storageLabels.Copy()
 	statefulSetLabels.Merge(map[string]string{labels.StatefulsetComponent: b.Name})
@@ -50,37 +137,23 @@ func (b *StorageClusterBuilder) GetResourceBuilders(restConfig *rest.Config) []R
 	statusServiceLabels.Merge(b.Spec.Service.Status.AdditionalLabels)
 	statusServiceLabels.Merge(map[string]string{labels.ServiceComponent: labels.StatusComponent})
 
+	statusServiceSelectorLabels := storageSelectorLabels.Copy()
+	statusServiceSelectorLabels.Merge(map[string]string{labels.ServiceComponent: labels.StatusComponent})
+
 	var optionalBuilders []ResourceBuilder
 
-	success, dynconfig, _ := api.ParseDynConfig(b.Spec.Configuration)
-	if !success {
-		// YDBOPS-9722 backward compatibility
-		cfg, _ := api.BuildConfiguration(b.Unwrap(), nil)
-		optionalBuilders = append(
-			optionalBuilders,
-			&ConfigMapBuilder{
-				Object: b,
-				Name:   b.Storage.GetName(),
-				Data: map[string]string{
-					api.ConfigFileName: string(cfg),
-				},
-				Labels: storageLabels,
-			},
-		)
-	} else {
-		cfg, _ := yaml.Marshal(dynconfig.Config)
-		optionalBuilders = append(
-			optionalBuilders,
-			&ConfigMapBuilder{
-				Object: b,
-				Name:   b.Storage.GetName(),
-				Data: map[string]string{
-					api.ConfigFileName: string(cfg),
-				},
-				Labels: storageLabels,
+	optionalBuilders = append(
+		optionalBuilders,
+		&ConfigMapBuilder{
+			Object: b,
+			Name:   b.Storage.GetName(),
+			Data: map[string]string{
+				api.ConfigFileName: b.GetConfiguration(),
 			},
-		)
-	}
+			Labels:      storageLabels,
+			Annotations: GetConfigMapChecksumAnnotations(b.GetConfiguration()),
+		},
+	)
 
 	if b.Spec.Monitoring.Enabled {
 		optionalBuilders = append(optionalBuilders,
@@ -92,34 +165,69 @@ func (b *StorageClusterBuilder) GetResourceBuilders(restConfig *rest.Config) []R
 				Options:         b.Spec.Monitoring,
 
 				Labels:         storageLabels,
-				SelectorLabels: statusServiceLabels,
+				SelectorLabels: statusServiceSelectorLabels,
 			},
 		)
 	}
 
 	if b.Spec.NodeSets == nil {
+		storage := b.Unwrap()
+		if b.IsUpgradeRolledBack() {
+			storage.Spec.Image = b.Status.PreviousImage.DeepCopy()
+		}
 		optionalBuilders = append(
 			optionalBuilders,
 			&StorageStatefulSetBuilder{
-				Storage:    b.Unwrap(),
+				Storage:    storage,
 				RestConfig: restConfig,
 
 				Name:        b.Name,
 				Labels:      statefulSetLabels,
 				Annotations: statefulSetAnnotations,
+
+				WithOperatorToken: b.Spec.OperatorConnection != nil,
 			},
 		)
 	} else {
 		optionalBuilders = append(optionalBuilders, b.getNodeSetBuilders(storageLabels)...)
 	}
 
+	optionalBuilders = append(
+		optionalBuilders,
+		getAdditionalResourceBuilders(b, b.Spec.AdditionalResources, storageLabels)...,
+	)
+
+	for _, port := range b.Spec.AdditionalPorts {
+		portServiceLabels := storageLabels.Copy()
+		portServiceLabels.Merge(port.AdditionalLabels)
+		portServiceLabels.Merge(map[string]string{labels.ServiceComponent: port.Name})
+
+		optionalBuilders = append(
+			optionalBuilders,
+			&ServiceBuilder{
+				Object:         b,
+				NameFormat:     "%s-" + port.Name,
+				Labels:         portServiceLabels,
+				SelectorLabels: storageSelectorLabels,
+				Annotations:    port.AdditionalAnnotations,
+				Ports: []corev1.ServicePort{{
+					Name:     port.Name,
+					Port:     port.Port,
+					Protocol: port.Protocol,
+				}},
+				IPFamilies:     port.IPFamilies,
+				IPFamilyPolicy: port.IPFamilyPolicy,
+			},
+		)
+	}
+
 	return append(
 		optionalBuilders,
 		&ServiceBuilder{
 			Object:         b,
 			NameFormat:     GRPCServiceNameFormat,
 			Labels:         grpcServiceLabels,
-			SelectorLabels: storageLabels,
+			SelectorLabels: storageSelectorLabels,
 			Annotations:    b.Spec.Service.GRPC.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.GRPCServicePortName,
@@ -132,7 +240,7 @@ func (b *StorageClusterBuilder) GetResourceBuilders(restConfig *rest.Config) []R
 			Object:         b,
 			NameFormat:     InterconnectServiceNameFormat,
 			Labels:         interconnectServiceLabels,
-			SelectorLabels: storageLabels,
+			SelectorLabels: storageSelectorLabels,
 			Annotations:    b.Spec.Service.Interconnect.AdditionalAnnotations,
 			Headless:       true,
 			Ports: []corev1.ServicePort{{
@@ -146,7 +254,7 @@ func (b *StorageClusterBuilder) GetResourceBuilders(restConfig *rest.Config) []R
 			Object:         b,
 			NameFormat:     StatusServiceNameFormat,
 			Labels:         statusServiceLabels,
-			SelectorLabels: storageLabels,
+			SelectorLabels: storageSelectorLabels,
 			Annotations:    b.Spec.Service.Status.AdditionalAnnotations,
 			Ports: []corev1.ServicePort{{
 				Name: api.StatusServicePortName,
@@ -176,6 +284,12 @@ func (b *StorageClusterBuilder) getNodeSetBuilders(storageLabels labels.Labels)
 			}
 		}
 
+		// Operator connection is not a part of node set spec, pods of local
+		// node sets mount its token by annotation
+		if nodeSetSpecInline.Remote == nil && b.Spec.OperatorConnection != nil {
+			nodeSetAnnotations[annotations.OperatorToken] = "true"
+		}
+
 		storageNodeSetSpec := b.recastStorageNodeSetSpecInline(nodeSetSpecInline.DeepCopy())
 		if nodeSetSpecInline.Remote != nil {
 			nodeSetBuilders = append(
@@ -226,6 +340,10 @@ func (b *StorageClusterBuilder) recastStorageNodeSetSpecInline(nodeSetSpecInline
 		nodeSetSpec.DataStore = nodeSetSpecInline.DataStore
 	}
 
+	if nodeSetSpecInline.StoragePools != nil {
+		nodeSetSpec.StoragePools = nodeSetSpecInline.StoragePools
+	}
+
 	if nodeSetSpecInline.Resources != nil {
 		nodeSetSpec.Resources = nodeSetSpecInline.Resources
 	}
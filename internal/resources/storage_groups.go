@@ -0,0 +1,138 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+)
+
+const (
+	storagePoolBlockPrefix = "StoragePool {"
+
+	// Box defined by blobstorage init of the operator
+	defaultBoxID = 1
+)
+
+var (
+	storagePoolNumGroupsRegexp = regexp.MustCompile(`\bNumGroups:\s*(\d+)`)
+	storagePoolNameRegexp      = regexp.MustCompile(`\bName:\s*"([^"]*)"`)
+
+	storageConfigInvokeFailureRegexp = regexp.MustCompile(`\bSuccess:\s*false\b`)
+)
+
+// StoragePoolConfig is a storage pool as reported by BS controller
+type StoragePoolConfig struct {
+	Name      string
+	NumGroups int
+	// Text proto of the pool which is reused to redefine it
+	Definition string
+}
+
+// GetDesiredStorageGroups returns the number of storage groups the cluster
+// should have: static groups of the configuration or `spec.groups` if greater
+func (b *StorageClusterBuilder) GetDesiredStorageGroups() int {
+	expected := b.GetExpectedStorageGroups()
+	if b.Spec.Groups != nil && int(*b.Spec.Groups) > expected {
+		return int(*b.Spec.Groups)
+	}
+	return expected
+}
+
+// GetReadStoragePoolsCommand returns command listing storage pools of the
+// cluster, to be executed in a Storage pod
+func (b *StorageClusterBuilder) GetReadStoragePoolsCommand() []string {
+	return b.GetConfigInvokeCommand(
+		fmt.Sprintf("Command { ReadStoragePool { BoxId: %d } }", defaultBoxID),
+	)
+}
+
+// GetDefineStoragePoolCommand returns command redefining storage pool with
+// the new number of groups, BS controller creates missing groups
+func (b *StorageClusterBuilder) GetDefineStoragePoolCommand(pool StoragePoolConfig, numGroups int) []string {
+	definition := pool.Definition
+	if storagePoolNumGroupsRegexp.MatchString(definition) {
+		definition = storagePoolNumGroupsRegexp.ReplaceAllString(definition, fmt.Sprintf("NumGroups: %d", numGroups))
+	} else {
+		definition = fmt.Sprintf("%s NumGroups: %d", definition, numGroups)
+	}
+	return b.GetConfigInvokeCommand(
+		fmt.Sprintf("Command { DefineStoragePool { %s } }", definition),
+	)
+}
+
+// GetConfigInvokeCommand returns command sending `bs config invoke` request
+// with the text proto to BS controller, to be executed in a Storage pod
+func (b *StorageClusterBuilder) GetConfigInvokeCommand(proto string) []string {
+	command := []string{fmt.Sprintf("%s/%s", api.BinariesDir, api.DaemonBinaryName)}
+	command = append(command, b.operatorTokenArgs()...)
+	return append(command,
+		"-s", b.GetStorageEndpointWithProto(),
+		"admin", "blobstorage", "config", "invoke", "--proto", proto,
+	)
+}
+
+// CheckConfigInvokeResult returns error if BS controller reports failure of
+// any command of `bs config invoke` request
+func CheckConfigInvokeResult(output string) error {
+	if storageConfigInvokeFailureRegexp.MatchString(output) {
+		return fmt.Errorf("bs config invoke failed: %s", strings.TrimSpace(output))
+	}
+	return nil
+}
+
+// ParseStoragePools reads storage pools from the text output of
+// ReadStoragePool command
+func ParseStoragePools(output string) ([]StoragePoolConfig, error) {
+	var pools []StoragePoolConfig
+	rest := output
+	for {
+		start := strings.Index(rest, storagePoolBlockPrefix)
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len(storagePoolBlockPrefix):]
+
+		end, err := findBlockEnd(rest)
+		if err != nil {
+			return nil, err
+		}
+		definition := strings.TrimSpace(rest[:end])
+		rest = rest[end+1:]
+
+		pool := StoragePoolConfig{Definition: definition}
+		if match := storagePoolNameRegexp.FindStringSubmatch(definition); match != nil {
+			pool.Name = match[1]
+		}
+		if match := storagePoolNumGroupsRegexp.FindStringSubmatch(definition); match != nil {
+			pool.NumGroups, _ = strconv.Atoi(match[1])
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// findBlockEnd returns index of the brace closing the text proto block
+func findBlockEnd(text string) (int, error) {
+	depth := 1
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && inString:
+			i++
+		case c == '"':
+			inString = !inString
+		case c == '{' && !inString:
+			depth++
+		case c == '}' && !inString:
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, errors.New("unterminated StoragePool block in BS controller response")
+}
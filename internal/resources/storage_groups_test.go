@@ -0,0 +1,105 @@
+package resources_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+const readStoragePoolResponse = `Status {
+  Success: true
+  StoragePool {
+    BoxId: 1
+    StoragePoolId: 1
+    Name: "/Root:ssd"
+    ErasureSpecies: "block-4-2"
+    VDiskKind: "Default"
+    Kind: "ssd"
+    NumGroups: 2
+    PDiskFilter {
+      Property {
+        Type: SSD
+      }
+    }
+    ItemConfigGeneration: 3
+  }
+}
+Success: true
+`
+
+var _ = Describe("Storage groups", func() {
+	It("parses storage pools of BS controller response", func() {
+		pools, err := resources.ParseStoragePools(readStoragePoolResponse)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pools).To(HaveLen(1))
+		Expect(pools[0].Name).To(Equal("/Root:ssd"))
+		Expect(pools[0].NumGroups).To(Equal(2))
+		Expect(pools[0].Definition).To(HaveSuffix("ItemConfigGeneration: 3"))
+	})
+
+	It("rejects truncated response", func() {
+		_, err := resources.ParseStoragePools("StoragePool { Name: \"/Root:ssd\" PDiskFilter {")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("redefines storage pool with the new number of groups", func() {
+		storage := resources.NewCluster(newTestStorage())
+		pools, err := resources.ParseStoragePools(readStoragePoolResponse)
+		Expect(err).ToNot(HaveOccurred())
+
+		command := storage.GetDefineStoragePoolCommand(pools[0], 5)
+		Expect(command).To(ContainElements("admin", "blobstorage", "config", "invoke", "--proto"))
+		proto := command[len(command)-1]
+		Expect(proto).To(HavePrefix("Command { DefineStoragePool { BoxId: 1"))
+		Expect(proto).To(ContainSubstring("NumGroups: 5"))
+		Expect(proto).ToNot(ContainSubstring("NumGroups: 2"))
+		Expect(proto).To(ContainSubstring("ItemConfigGeneration: 3"))
+	})
+
+	It("reports failed bs config invoke request", func() {
+		Expect(resources.CheckConfigInvokeResult(readStoragePoolResponse)).To(Succeed())
+		Expect(resources.CheckConfigInvokeResult(
+			"Status {\n  Success: false\n  ErrorDescription: \"unknown pool\"\n}\nSuccess: false\n",
+		)).To(MatchError(ContainSubstring("unknown pool")))
+	})
+
+	It("validates configuration with config validate in Storage pod", func() {
+		storage := resources.NewCluster(newTestStorage())
+		cmd := storage.GetConfigValidateCommand("domains_config: {}\n")
+		Expect(cmd).To(HaveLen(4))
+		Expect(cmd[:2]).To(Equal([]string{"sh", "-c"}))
+		Expect(cmd[2]).To(ContainSubstring("admin config validate --yaml-file"))
+		Expect(cmd[2]).To(ContainSubstring(storage.GetStorageEndpointWithProto()))
+		Expect(cmd[3]).To(Equal("domains_config: {}\n"))
+	})
+
+	It("reads token of operator connection from file in Storage pod", func() {
+		storage := resources.NewCluster(newTestStorage())
+		storage.Spec.OperatorConnection = &api.ConnectionOptions{
+			StaticCredentials: &api.StaticCredentialsAuth{Username: "root"},
+		}
+		tokenFile := fmt.Sprintf("/opt/ydb/secrets/%s/token-file", fmt.Sprintf(resources.OperatorTokenSecretNameFormat, storage.Name))
+
+		command := storage.GetReadStoragePoolsCommand()
+		Expect(command[1:3]).To(Equal([]string{"-f", tokenFile}))
+		for _, arg := range command {
+			Expect(arg).ToNot(ContainSubstring("YDB_TOKEN"))
+		}
+
+		cmd := storage.GetConfigValidateCommand("domains_config: {}\n")
+		Expect(cmd[2]).To(ContainSubstring("-f " + tokenFile + " -s "))
+	})
+
+	It("desires groups of spec only when they exceed static groups", func() {
+		storage := resources.NewCluster(newTestStorage())
+		Expect(storage.GetDesiredStorageGroups()).To(Equal(1))
+
+		storage.Spec.Groups = ptr.Int32(8)
+		Expect(storage.GetDesiredStorageGroups()).To(Equal(8))
+	})
+})
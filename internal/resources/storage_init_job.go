@@ -38,11 +38,16 @@ func (b *StorageInitJobBuilder) Build(obj client.Object) error {
 	job.ObjectMeta.Labels = b.Labels
 	job.ObjectMeta.Annotations = b.Annotations
 
+	backoffLimit := ptr.Int32(api.DefaultInitJobBackoffLimit)
+	if b.Spec.InitJob != nil && b.Spec.InitJob.BackoffLimit != nil {
+		backoffLimit = ptr.Int32(*b.Spec.InitJob.BackoffLimit)
+	}
+
 	job.Spec = batchv1.JobSpec{
 		Parallelism:           ptr.Int32(1),
 		Completions:           ptr.Int32(1),
 		ActiveDeadlineSeconds: ptr.Int64(300),
-		BackoffLimit:          ptr.Int32(6),
+		BackoffLimit:          backoffLimit,
 		Template:              b.buildInitJobPodTemplateSpec(),
 	}
 
@@ -236,7 +241,7 @@ func (b *StorageInitJobBuilder) buildJobVolumeMounts() []corev1.VolumeMount {
 		{
 			Name:      configVolumeName,
 			ReadOnly:  true,
-			MountPath: fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName),
+			MountPath: fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.ConfigFileName),
 			SubPath:   api.ConfigFileName,
 		},
 	}
@@ -336,6 +341,10 @@ func (b *StorageInitJobBuilder) buildCaStorePatchingInitContainerVolumeMounts()
 }
 
 func (b *StorageInitJobBuilder) buildBlobStorageInitCommandArgs() ([]string, []string) {
+	if b.Spec.ConfigurationVersion == api.ConfigurationV2 {
+		return b.buildClusterBootstrapCommandArgs()
+	}
+
 	command := []string{
 		fmt.Sprintf("%s/%s", api.BinariesDir, api.DaemonBinaryName),
 	}
@@ -360,7 +369,36 @@ func (b *StorageInitJobBuilder) buildBlobStorageInitCommandArgs() ([]string, []s
 	args = append(
 		args,
 		"admin", "blobstorage", "config", "init", "--yaml-file",
-		fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName),
+		fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.ConfigFileName),
+	)
+
+	return command, args
+}
+
+// buildClusterBootstrapCommandArgs returns ydb CLI invocation which bootstraps
+// cluster with unified config, static group is defined by the cluster itself.
+func (b *StorageInitJobBuilder) buildClusterBootstrapCommandArgs() ([]string, []string) {
+	command := []string{
+		fmt.Sprintf("%s/%s", api.BinariesDir, api.CLIBinaryName),
+	}
+
+	args := []string{
+		"-e",
+		b.Storage.GetStorageEndpointWithProto(),
+	}
+
+	if b.Storage.Spec.OperatorConnection != nil {
+		secretName := fmt.Sprintf(OperatorTokenSecretNameFormat, b.Storage.Name)
+		args = append(
+			args,
+			"--token-file",
+			fmt.Sprintf("%s/%s/%s", wellKnownDirForAdditionalSecrets, secretName, wellKnownNameForOperatorToken),
+		)
+	}
+
+	args = append(
+		args,
+		"admin", "cluster", "bootstrap", "--uuid", string(b.Storage.UID),
 	)
 
 	return command, args
@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/labels"
 	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
 )
@@ -30,6 +31,10 @@ type StorageStatefulSetBuilder struct {
 	Name        string
 	Labels      map[string]string
 	Annotations map[string]string
+
+	// WithOperatorToken mounts token of operator connection, which is read
+	// by commands the operator executes in Storage pods
+	WithOperatorToken bool
 }
 
 func StringRJust(str, pad string, length int) string {
@@ -41,6 +46,24 @@ func StringRJust(str, pad string, length int) string {
 	}
 }
 
+// buildPersistentVolumeClaimRetentionPolicy returns the policy from spec,
+// PVCs with cluster data are retained unless Delete is set explicitly
+func (b *StorageStatefulSetBuilder) buildPersistentVolumeClaimRetentionPolicy() *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	policy := &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	if b.Spec.PersistentVolumeClaimRetentionPolicy != nil {
+		if b.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted != "" {
+			policy.WhenDeleted = b.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted
+		}
+		if b.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled != "" {
+			policy.WhenScaled = b.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled
+		}
+	}
+	return policy
+}
+
 func (b *StorageStatefulSetBuilder) GeneratePVCName(index int) string {
 	return b.Name + "-" + StringRJust(strconv.Itoa(index), "0", api.DiskNumberMaxDigits)
 }
@@ -49,6 +72,77 @@ func (b *StorageStatefulSetBuilder) GenerateDeviceName(index int) string {
 	return api.DiskPathPrefix + "_" + StringRJust(strconv.Itoa(index), "0", api.DiskNumberMaxDigits)
 }
 
+// getUpdatePartition returns the partition from spec. In Auto mode it is
+// lowered to 0 once the Pods above the partition run the update revision
+// and all Pods are ready, so the rest of the nodes are rolled. Lowered
+// partition is recorded in status and kept until the rollout is finished,
+// as readiness of the Pods no longer holds while they are being restarted.
+func (b *StorageStatefulSetBuilder) getUpdatePartition(sts *appsv1.StatefulSet) int32 {
+	if b.Spec.RollingUpdate.Mode == api.RollingUpdateModeMaintenance {
+		return b.getMaintenanceUpdatePartition(sts)
+	}
+
+	partition := *b.Spec.RollingUpdate.Partition
+	if b.Spec.RollingUpdate.Mode == api.RollingUpdateModeManual {
+		return partition
+	}
+
+	status := sts.Status
+	if status.UpdateRevision == status.CurrentRevision {
+		return partition
+	}
+
+	if b.Status.UpdatePartition != nil && *b.Status.UpdatePartition == 0 {
+		return 0
+	}
+
+	if status.ObservedGeneration != sts.Generation {
+		return partition
+	}
+
+	if status.UpdatedReplicas >= b.Spec.Nodes-partition && status.ReadyReplicas == b.Spec.Nodes {
+		return 0
+	}
+
+	return partition
+}
+
+// getMaintenanceUpdatePartition keeps all the Pods on the current revision
+// until the operator lowers the partition in status, which is done one Pod
+// at a time after CMS grants maintenance of the node.
+func (b *StorageStatefulSetBuilder) getMaintenanceUpdatePartition(sts *appsv1.StatefulSet) int32 {
+	if sts.Status.UpdateRevision == sts.Status.CurrentRevision || b.Status.UpdatePartition == nil {
+		return b.Spec.Nodes
+	}
+
+	return *b.Status.UpdatePartition
+}
+
+func (b *StorageStatefulSetBuilder) getRevisionHistoryLimit() *int32 {
+	if b.Spec.RevisionHistoryLimit != nil {
+		return ptr.Int32(*b.Spec.RevisionHistoryLimit)
+	}
+	return ptr.Int32(api.DefaultRevisionHistoryLimit)
+}
+
+// getPodManagementPolicy keeps the policy of the existing StatefulSet, as
+// it is immutable, StatefulSets created before the policy was configurable
+// are Parallel.
+func (b *StorageStatefulSetBuilder) getPodManagementPolicy(sts *appsv1.StatefulSet) appsv1.PodManagementPolicyType {
+	if sts.Spec.PodManagementPolicy != "" {
+		return sts.Spec.PodManagementPolicy
+	}
+	if b.Spec.PodManagementPolicy != "" {
+		return b.Spec.PodManagementPolicy
+	}
+	return appsv1.OrderedReadyPodManagement
+}
+
+// dataStore returns volume claim templates of the pdisks backed by data store
+func (b *StorageStatefulSetBuilder) dataStore() []corev1.PersistentVolumeClaimSpec {
+	return api.ExpandDataStore(b.Spec.DataStore, b.Spec.PDisksPerNode)
+}
+
 func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
 	sts, ok := obj.(*appsv1.StatefulSet)
 	if !ok {
@@ -62,8 +156,10 @@ func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
 	sts.ObjectMeta.Labels = b.Labels
 	sts.ObjectMeta.Annotations = b.Annotations
 
+	appliedChecksum, hasAppliedChecksum := sts.Spec.Template.Annotations[annotations.ConfigurationChecksum]
+
 	replicas := ptr.Int32(b.Spec.Nodes)
-	if b.Spec.Pause {
+	if b.Spec.Pause && b.Spec.PauseMode != api.PauseModeFreeze {
 		replicas = ptr.Int32(0)
 	}
 
@@ -74,10 +170,33 @@ func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
 				labels.StatefulsetComponent: b.Name,
 			},
 		},
-		PodManagementPolicy:  appsv1.ParallelPodManagement,
-		RevisionHistoryLimit: ptr.Int32(10),
+		PodManagementPolicy:  b.getPodManagementPolicy(sts),
+		RevisionHistoryLimit: b.getRevisionHistoryLimit(),
 		ServiceName:          fmt.Sprintf(InterconnectServiceNameFormat, b.Storage.Name),
 		Template:             b.buildPodTemplateSpec(),
+
+		PersistentVolumeClaimRetentionPolicy: b.buildPersistentVolumeClaimRetentionPolicy(),
+	}
+
+	// Configuration checksum of the Pod template is not changed unless
+	// configuration changes are applied with rolling restart, new
+	// configuration is read from ConfigMap when the Pods are recreated
+	if hasAppliedChecksum &&
+		b.Spec.ConfigChangePolicy != "" &&
+		b.Spec.ConfigChangePolicy != api.ConfigChangePolicyImmediate {
+		templateAnnotations := CopyDict(sts.Spec.Template.Annotations)
+		templateAnnotations[annotations.ConfigurationChecksum] = appliedChecksum
+		sts.Spec.Template.Annotations = templateAnnotations
+	}
+
+	if b.Spec.RollingUpdate != nil &&
+		(b.Spec.RollingUpdate.Partition != nil || b.Spec.RollingUpdate.Mode == api.RollingUpdateModeMaintenance) {
+		sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: ptr.Int32(b.getUpdatePartition(sts)),
+			},
+		}
 	}
 
 	if value, ok := b.ObjectMeta.Annotations[api.AnnotationUpdateStrategyOnDelete]; ok && value == api.AnnotationValueTrue {
@@ -86,8 +205,13 @@ func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
 		}
 	}
 
-	pvcList := make([]corev1.PersistentVolumeClaim, 0, len(b.Spec.DataStore))
-	for i, pvcSpec := range b.Spec.DataStore {
+	dataStore := b.dataStore()
+	pvcList := make([]corev1.PersistentVolumeClaim, 0, len(dataStore)+len(b.Spec.StoragePools))
+	for i, pvcSpec := range dataStore {
+		// Ephemeral data store is backed by emptyDir volumes of the pod
+		if b.Spec.EphemeralDataStore {
+			break
+		}
 		pvcList = append(
 			pvcList,
 			corev1.PersistentVolumeClaim{
@@ -98,6 +222,28 @@ func (b *StorageStatefulSetBuilder) Build(obj client.Object) error {
 			},
 		)
 	}
+	for i, pool := range b.Spec.StoragePools {
+		pvcList = append(
+			pvcList,
+			corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: b.GeneratePVCName(len(dataStore) + i),
+				},
+				Spec: pool.VolumeClaimTemplate,
+			},
+		)
+	}
+	if b.Spec.LogVolume != nil {
+		pvcList = append(
+			pvcList,
+			corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: logVolumeName,
+				},
+				Spec: b.Spec.LogVolume.VolumeClaimTemplate,
+			},
+		)
+	}
 	sts.Spec.VolumeClaimTemplates = pvcList
 
 	return nil
@@ -114,13 +260,15 @@ func (b *StorageStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSpe
 			Annotations: b.Annotations,
 		},
 		Spec: corev1.PodSpec{
-			Containers:                    []corev1.Container{b.buildContainer()},
+			Containers:                    append([]corev1.Container{b.buildContainer()}, b.Spec.Sidecars...),
 			NodeSelector:                  b.Spec.NodeSelector,
 			Affinity:                      b.Spec.Affinity,
 			Tolerations:                   b.Spec.Tolerations,
 			PriorityClassName:             b.Spec.PriorityClassName,
+			SchedulerName:                 b.Spec.SchedulerName,
 			TopologySpreadConstraints:     b.buildTopologySpreadConstraints(),
 			TerminationGracePeriodSeconds: b.Spec.TerminationGracePeriodSeconds,
+			AutomountServiceAccountToken:  b.Spec.AutomountServiceAccountToken,
 
 			Volumes: b.buildVolumes(),
 
@@ -130,6 +278,10 @@ func (b *StorageStatefulSetBuilder) buildPodTemplateSpec() corev1.PodTemplateSpe
 		},
 	}
 
+	if b.Spec.InjectGOMAXPROCS {
+		SetGOMAXPROCS(podTemplate.Spec.Containers)
+	}
+
 	// InitContainer only needed for CaBundle manipulation for now,
 	// may be probably used for other stuff later
 	if b.AnyCertificatesAdded() {
@@ -221,6 +373,20 @@ func (b *StorageStatefulSetBuilder) buildVolumes() []corev1.Volume {
 		)
 	}
 
+	// Token Secret is created before the first command executed in pods,
+	// it is optional so that pods are started without it
+	if b.WithOperatorToken {
+		volumes = append(volumes, corev1.Volume{
+			Name: operatorTokenVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: fmt.Sprintf(OperatorTokenSecretNameFormat, b.Storage.Name),
+					Optional:   ptr.Bool(true),
+				},
+			},
+		})
+	}
+
 	for _, secret := range b.Spec.Secrets {
 		volumes = append(volumes, corev1.Volume{
 			Name: secret.Name,
@@ -236,6 +402,21 @@ func (b *StorageStatefulSetBuilder) buildVolumes() []corev1.Volume {
 		volumes = append(volumes, *volume)
 	}
 
+	if b.Spec.EphemeralDataStore {
+		for i, pvcSpec := range b.Spec.DataStore {
+			emptyDir := &corev1.EmptyDirVolumeSource{}
+			if size, ok := pvcSpec.Resources.Requests[corev1.ResourceStorage]; ok {
+				emptyDir.SizeLimit = &size
+			}
+			volumes = append(volumes, corev1.Volume{
+				Name: b.GeneratePVCName(i),
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: emptyDir,
+				},
+			})
+		}
+	}
+
 	if b.AnyCertificatesAdded() {
 		volumes = append(volumes, corev1.Volume{
 			Name: systemCertsVolumeName,
@@ -272,7 +453,7 @@ func (b *StorageStatefulSetBuilder) buildCaStorePatchingInitContainer() corev1.C
 	}
 
 	container := corev1.Container{
-		Name:            "ydb-storage-init-container",
+		Name:            api.CaStorePatchingContainerName,
 		Image:           b.Spec.Image.Name,
 		ImagePullPolicy: imagePullPolicy,
 		Command:         command,
@@ -354,7 +535,7 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 	}
 
 	container := corev1.Container{
-		Name:            "ydb-storage",
+		Name:            api.StorageContainerName,
 		Image:           b.Spec.Image.Name,
 		ImagePullPolicy: imagePullPolicy,
 		Command:         command,
@@ -367,13 +548,7 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 			},
 		},
 
-		Ports: []corev1.ContainerPort{{
-			Name: "grpc", ContainerPort: api.GRPCPort,
-		}, {
-			Name: "interconnect", ContainerPort: api.InterconnectPort,
-		}, {
-			Name: "status", ContainerPort: api.StatusPort,
-		}},
+		Ports: b.buildContainerPorts(),
 
 		VolumeMounts: b.buildVolumeMounts(),
 		Resources:    containerResources,
@@ -387,12 +562,14 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 				},
 			},
 		}
+		container.StartupProbe = b.buildStartupProbe()
 	}
 
 	var volumeDeviceList []corev1.VolumeDevice // todo decide on PVC volumeMode?
 	var volumeMountList []corev1.VolumeMount
-	for i, spec := range b.Spec.DataStore {
-		if *spec.VolumeMode == corev1.PersistentVolumeFilesystem {
+	dataStore := b.dataStore()
+	for i, spec := range dataStore {
+		if b.Spec.EphemeralDataStore || *spec.VolumeMode == corev1.PersistentVolumeFilesystem {
 			volumeMountList = append(
 				volumeMountList,
 				corev1.VolumeMount{
@@ -400,6 +577,7 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 					MountPath: api.DiskFilePath,
 				},
 			)
+			continue
 		}
 		if *spec.VolumeMode == corev1.PersistentVolumeBlock {
 			volumeDeviceList = append(
@@ -411,18 +589,48 @@ func (b *StorageStatefulSetBuilder) buildContainer() corev1.Container { // todo
 			)
 		}
 	}
+	for i := range b.Spec.StoragePools {
+		index := len(dataStore) + i
+		volumeDeviceList = append(
+			volumeDeviceList,
+			corev1.VolumeDevice{
+				Name:       b.GeneratePVCName(index),
+				DevicePath: b.GenerateDeviceName(index),
+			},
+		)
+	}
 	container.VolumeDevices = append(container.VolumeDevices, volumeDeviceList...)
 	container.VolumeMounts = append(container.VolumeMounts, volumeMountList...)
 
 	return container
 }
 
+func (b *StorageStatefulSetBuilder) buildStartupProbe() *corev1.Probe {
+	probe := &corev1.Probe{
+		PeriodSeconds:    api.DefaultStartupProbePeriod,
+		FailureThreshold: api.DefaultStartupProbeFailures,
+	}
+	if b.Spec.StartupProbe != nil {
+		probe = b.Spec.StartupProbe.DeepCopy()
+	}
+
+	if probe.ProbeHandler == (corev1.ProbeHandler{}) {
+		probe.ProbeHandler = corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(api.GRPCPort),
+			},
+		}
+	}
+
+	return probe
+}
+
 func (b *StorageStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      configVolumeName,
 			ReadOnly:  true,
-			MountPath: fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName),
+			MountPath: fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.ConfigFileName),
 			SubPath:   api.ConfigFileName,
 		},
 	}
@@ -463,6 +671,15 @@ func (b *StorageStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if b.WithOperatorToken {
+		secretName := fmt.Sprintf(OperatorTokenSecretNameFormat, b.Storage.Name)
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      operatorTokenVolumeName,
+			ReadOnly:  true,
+			MountPath: fmt.Sprintf("%s/%s", wellKnownDirForAdditionalSecrets, secretName),
+		})
+	}
+
 	for _, secret := range b.Spec.Secrets {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      secret.Name,
@@ -477,6 +694,13 @@ func (b *StorageStatefulSetBuilder) buildVolumeMounts() []corev1.VolumeMount {
 		})
 	}
 
+	if b.Spec.LogVolume != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      logVolumeName,
+			MountPath: api.LogVolumeDir,
+		})
+	}
+
 	return volumeMounts
 }
 
@@ -494,7 +718,7 @@ func (b *StorageStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		fmt.Sprintf("%d", api.InterconnectPort),
 
 		"--yaml-config",
-		fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName),
+		fmt.Sprintf("%s/%s", b.Spec.GetConfigDir(), api.ConfigFileName),
 
 		"--node",
 		"static",
@@ -503,6 +727,15 @@ func (b *StorageStatefulSetBuilder) buildContainerArgs() ([]string, []string) {
 		fmt.Sprintf("%s=%s", api.LabelDeploymentKey, api.LabelDeploymentValueKubernetes),
 	)
 
+	// Log file is passed in args since configuration is shared with
+	// database nodes which do not mount log volume
+	if b.Spec.LogVolume != nil {
+		args = append(args,
+			"--log-file-name",
+			fmt.Sprintf("%s/%s", api.LogVolumeDir, api.LogFileName),
+		)
+	}
+
 	if b.Spec.Service.Status.TLSConfiguration.Enabled {
 		args = append(args,
 			"--mon-cert",
@@ -550,3 +783,23 @@ func (b *StorageStatefulSetBuilder) Placeholder(cr client.Object) client.Object
 		},
 	}
 }
+
+func (b *StorageStatefulSetBuilder) buildContainerPorts() []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{{
+		Name: "grpc", ContainerPort: api.GRPCPort,
+	}, {
+		Name: "interconnect", ContainerPort: api.InterconnectPort,
+	}, {
+		Name: "status", ContainerPort: api.StatusPort,
+	}}
+
+	for _, port := range b.Spec.AdditionalPorts {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.Port,
+			Protocol:      port.Protocol,
+		})
+	}
+
+	return ports
+}
@@ -0,0 +1,550 @@
+package resources_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/ydb-platform/ydb-kubernetes-operator/api/v1alpha1"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/annotations"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/configuration/schema"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/controllers/constants"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ptr"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/resources"
+)
+
+func TestResources(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resources suite")
+}
+
+func newTestStorage() *api.Storage {
+	return &api.Storage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "storage",
+			Namespace: "ydb",
+		},
+		Spec: api.StorageSpec{
+			StorageClusterSpec: api.StorageClusterSpec{
+				Image: &api.PodImage{Name: "cr.yandex/ydb/ydb:stable"},
+				Service: &api.StorageServices{
+					GRPC: api.GRPCService{
+						TLSConfiguration: &api.TLSConfiguration{},
+					},
+					Interconnect: api.InterconnectService{
+						TLSConfiguration: &api.TLSConfiguration{},
+					},
+					Status: api.StatusService{
+						TLSConfiguration: &api.TLSConfiguration{},
+					},
+				},
+			},
+			StorageNodeSpec: api.StorageNodeSpec{
+				Nodes: 1,
+			},
+		},
+	}
+}
+
+func buildStorageContainer(storage *api.Storage) corev1.Container {
+	builder := &resources.StorageStatefulSetBuilder{
+		Storage: storage,
+		Name:    storage.Name,
+	}
+
+	sts := &appsv1.StatefulSet{}
+	Expect(builder.Build(sts)).To(Succeed())
+	Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+	return sts.Spec.Template.Spec.Containers[0]
+}
+
+func yamlConfigArg(container corev1.Container) string {
+	for i, arg := range container.Args {
+		if arg == "--yaml-config" && i+1 < len(container.Args) {
+			return container.Args[i+1]
+		}
+	}
+	return ""
+}
+
+func configVolumeMount(container corev1.Container) *corev1.VolumeMount {
+	for i, mount := range container.VolumeMounts {
+		if mount.SubPath == api.ConfigFileName {
+			return &container.VolumeMounts[i]
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Testing storage StatefulSet builder", func() {
+	It("mounts configuration read-only at the default path", func() {
+		container := buildStorageContainer(newTestStorage())
+
+		mount := configVolumeMount(container)
+		Expect(mount).NotTo(BeNil())
+		Expect(mount.ReadOnly).To(BeTrue())
+		Expect(mount.MountPath).To(Equal(fmt.Sprintf("%s/%s", api.ConfigDir, api.ConfigFileName)))
+		Expect(yamlConfigArg(container)).To(Equal(mount.MountPath))
+	})
+
+	It("mounts configuration at the custom path expected by ydbd", func() {
+		storage := newTestStorage()
+		storage.Spec.ConfigDir = "/etc/ydb"
+		container := buildStorageContainer(storage)
+
+		mount := configVolumeMount(container)
+		Expect(mount).NotTo(BeNil())
+		Expect(mount.ReadOnly).To(BeTrue())
+		Expect(mount.MountPath).To(Equal("/etc/ydb/" + api.ConfigFileName))
+		Expect(yamlConfigArg(container)).To(Equal(mount.MountPath))
+	})
+
+	It("delays liveness probe with generous startup probe by default", func() {
+		container := buildStorageContainer(newTestStorage())
+		Expect(container.LivenessProbe).ToNot(BeNil())
+		Expect(container.StartupProbe).ToNot(BeNil())
+		Expect(container.StartupProbe.TCPSocket).ToNot(BeNil())
+		Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(api.DefaultStartupProbeFailures)))
+	})
+
+	It("uses startup probe thresholds from spec with default handler", func() {
+		storage := newTestStorage()
+		storage.Spec.StartupProbe = &corev1.Probe{FailureThreshold: 120}
+		container := buildStorageContainer(storage)
+		Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(120)))
+		Expect(container.StartupProbe.TCPSocket).ToNot(BeNil())
+	})
+
+	It("mounts operator token Secret when requested", func() {
+		storage := newTestStorage()
+		secretName := fmt.Sprintf(resources.OperatorTokenSecretNameFormat, storage.Name)
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name, WithOperatorToken: true}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(WithTransform(
+			func(volume corev1.Volume) string {
+				if volume.Secret == nil || volume.Secret.Optional == nil || !*volume.Secret.Optional {
+					return ""
+				}
+				return volume.Secret.SecretName
+			}, Equal(secretName),
+		)))
+		Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(WithTransform(
+			func(mount corev1.VolumeMount) string { return mount.MountPath },
+			Equal("/opt/ydb/secrets/"+secretName),
+		)))
+
+		container := buildStorageContainer(newTestStorage())
+		for _, mount := range container.VolumeMounts {
+			Expect(mount.MountPath).ToNot(Equal("/opt/ydb/secrets/" + secretName))
+		}
+	})
+
+	It("uses OrderedReady pod management policy by default", func() {
+		storage := newTestStorage()
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.PodManagementPolicy).To(Equal(appsv1.OrderedReadyPodManagement))
+	})
+
+	It("keeps a few revisions of StatefulSet unless configured", func() {
+		storage := newTestStorage()
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.RevisionHistoryLimit).To(Equal(int32(api.DefaultRevisionHistoryLimit)))
+
+		storage.Spec.RevisionHistoryLimit = ptr.Int32(0)
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.RevisionHistoryLimit).To(Equal(int32(0)))
+	})
+
+	It("mounts service account token as configured", func() {
+		storage := newTestStorage()
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.Template.Spec.AutomountServiceAccountToken).To(BeNil())
+
+		storage.Spec.AutomountServiceAccountToken = ptr.Bool(false)
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.Template.Spec.AutomountServiceAccountToken).To(BeFalse())
+	})
+
+	It("keeps Storage Pods running when paused with Freeze mode", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		storage.Spec.Pause = true
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.Replicas).To(Equal(int32(0)))
+
+		storage.Spec.PauseMode = api.PauseModeFreeze
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.Replicas).To(Equal(int32(3)))
+	})
+
+	It("keeps pod management policy of existing StatefulSet", func() {
+		storage := newTestStorage()
+		storage.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		sts.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.PodManagementPolicy).To(Equal(appsv1.ParallelPodManagement))
+	})
+
+	It("keeps rolling update partition in Manual mode", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		partition := int32(2)
+		storage.Spec.RollingUpdate = &api.StorageRollingUpdate{
+			Mode:      api.RollingUpdateModeManual,
+			Partition: &partition,
+		}
+
+		sts := canaryUpdatedStatefulSet(storage.Spec.Nodes)
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+	})
+
+	It("advances rolling update partition in Auto mode once canary is ready", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		partition := int32(2)
+		storage.Spec.RollingUpdate = &api.StorageRollingUpdate{
+			Mode:      api.RollingUpdateModeAuto,
+			Partition: &partition,
+		}
+
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+
+		sts = canaryUpdatedStatefulSet(storage.Spec.Nodes)
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(0)))
+	})
+
+	It("lowers rolling update partition in Maintenance mode only as granted in status", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		storage.Spec.RollingUpdate = &api.StorageRollingUpdate{
+			Mode: api.RollingUpdateModeMaintenance,
+		}
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
+
+		sts = canaryUpdatedStatefulSet(storage.Spec.Nodes)
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
+
+		storage.Status.UpdatePartition = ptr.Int32(1)
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(1)))
+	})
+
+	It("keeps advanced rolling update partition in Auto mode until rollout is finished", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		partition := int32(2)
+		storage.Spec.RollingUpdate = &api.StorageRollingUpdate{
+			Mode:      api.RollingUpdateModeAuto,
+			Partition: &partition,
+		}
+		storage.Status.UpdatePartition = ptr.Int32(0)
+
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		// Pod with lower ordinal is restarting, so not all Pods are ready
+		sts := canaryUpdatedStatefulSet(storage.Spec.Nodes)
+		sts.Status.ReadyReplicas = storage.Spec.Nodes - 1
+		sts.Status.UpdatedReplicas = 2
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(0)))
+
+		sts = canaryUpdatedStatefulSet(storage.Spec.Nodes)
+		sts.Status.UpdatedReplicas = storage.Spec.Nodes
+		sts.Status.CurrentRevision = sts.Status.UpdateRevision
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+	})
+
+	It("mounts separate log volume and writes log file to it", func() {
+		storageClassName := "logs"
+		storage := newTestStorage()
+		storage.Spec.LogVolume = &api.LogVolumeSpec{
+			VolumeClaimTemplate: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClassName,
+			},
+		}
+		builder := &resources.StorageStatefulSetBuilder{
+			Storage: storage,
+			Name:    storage.Name,
+		}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+		Expect(*sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal(storageClassName))
+
+		container := sts.Spec.Template.Spec.Containers[0]
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      sts.Spec.VolumeClaimTemplates[0].Name,
+			MountPath: api.LogVolumeDir,
+		}))
+		Expect(container.Args).To(ContainElements("--log-file-name", api.LogVolumeDir+"/"+api.LogFileName))
+	})
+
+	It("keeps ephemeral data store in emptyDir volumes", func() {
+		storage := newTestStorage()
+		storage.Spec.EphemeralDataStore = true
+		storage.Spec.DataStore = []corev1.PersistentVolumeClaimSpec{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10Gi"),
+				},
+			},
+		}}
+		builder := &resources.StorageStatefulSetBuilder{
+			Storage: storage,
+			Name:    storage.Name,
+		}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.VolumeClaimTemplates).To(BeEmpty())
+
+		volumeName := builder.GeneratePVCName(0)
+		var dataVolume *corev1.Volume
+		for i := range sts.Spec.Template.Spec.Volumes {
+			if sts.Spec.Template.Spec.Volumes[i].Name == volumeName {
+				dataVolume = &sts.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		Expect(dataVolume).NotTo(BeNil())
+		Expect(dataVolume.EmptyDir).NotTo(BeNil())
+		Expect(dataVolume.EmptyDir.SizeLimit.String()).To(Equal("10Gi"))
+		Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: api.DiskFilePath,
+		}))
+	})
+
+	It("keeps previous image while upgrade of the current spec is rolled back", func() {
+		storage := newTestStorage()
+		storage.Generation = 2
+		storage.Spec.Monitoring = &api.MonitoringOptions{}
+		storage.Status.PreviousImage = &api.PodImage{Name: "cr.yandex/ydb/ydb:previous"}
+
+		buildImage := func() string {
+			builder := resources.StorageClusterBuilder{Storage: storage}
+			for _, resourceBuilder := range builder.GetResourceBuilders(nil) {
+				if stsBuilder, ok := resourceBuilder.(*resources.StorageStatefulSetBuilder); ok {
+					return stsBuilder.Spec.Image.Name
+				}
+			}
+			return ""
+		}
+		Expect(buildImage()).To(Equal("cr.yandex/ydb/ydb:stable"))
+
+		storage.Status.Conditions = []metav1.Condition{{
+			Type:               constants.UpgradeRolledBackCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: 2,
+		}}
+		Expect(buildImage()).To(Equal("cr.yandex/ydb/ydb:previous"))
+
+		storage.Generation = 3
+		Expect(buildImage()).To(Equal("cr.yandex/ydb/ydb:stable"))
+	})
+
+	It("dispatches pods by custom scheduler", func() {
+		storage := newTestStorage()
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.Template.Spec.SchedulerName).To(BeEmpty())
+
+		storage.Spec.SchedulerName = "volcano"
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.Template.Spec.SchedulerName).To(Equal("volcano"))
+	})
+
+	It("retains PVCs on scale-down and deletion unless Delete is set", func() {
+		storage := newTestStorage()
+		builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: storage.Name}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(*sts.Spec.PersistentVolumeClaimRetentionPolicy).To(Equal(appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenDeleted: appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+			WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		}))
+
+		storage.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenScaled: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+		}
+		Expect(builder.Build(sts)).To(Succeed())
+		Expect(sts.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted).To(Equal(appsv1.RetainPersistentVolumeClaimRetentionPolicyType))
+		Expect(sts.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled).To(Equal(appsv1.DeletePersistentVolumeClaimRetentionPolicyType))
+	})
+
+	It("restarts pods on configuration change only with Immediate policy", func() {
+		storage := newTestStorage()
+		buildChecksum := func(sts *appsv1.StatefulSet, configuration string) string {
+			builder := &resources.StorageStatefulSetBuilder{
+				Storage:     storage,
+				Name:        storage.Name,
+				Annotations: map[string]string{annotations.ConfigurationChecksum: resources.SHAChecksum(configuration)},
+			}
+			Expect(builder.Build(sts)).To(Succeed())
+			return sts.Spec.Template.Annotations[annotations.ConfigurationChecksum]
+		}
+
+		sts := &appsv1.StatefulSet{}
+		Expect(buildChecksum(sts, "v1")).To(Equal(resources.SHAChecksum("v1")))
+		Expect(buildChecksum(sts, "v2")).To(Equal(resources.SHAChecksum("v2")))
+
+		for _, policy := range []api.ConfigChangePolicy{api.ConfigChangePolicyManual, api.ConfigChangePolicyOnPodRestart} {
+			storage.Spec.ConfigChangePolicy = policy
+			Expect(buildChecksum(sts, "v3")).To(Equal(resources.SHAChecksum("v2")))
+			Expect(sts.Annotations[annotations.ConfigurationChecksum]).To(Equal(resources.SHAChecksum("v3")))
+		}
+
+		storage.Spec.ConfigChangePolicy = api.ConfigChangePolicyImmediate
+		Expect(buildChecksum(sts, "v3")).To(Equal(resources.SHAChecksum("v3")))
+	})
+
+	It("applies configuration change policy to StatefulSets of node sets", func() {
+		storage := newTestStorage()
+		storage.Spec.ConfigChangePolicy = api.ConfigChangePolicyManual
+		storage.Spec.NodeSets = []api.StorageNodeSetSpecInline{{
+			Name:            "sas",
+			StorageNodeSpec: api.StorageNodeSpec{Nodes: 1},
+		}}
+
+		buildNodeSet := func(configuration string) *api.StorageNodeSet {
+			storage.Spec.Configuration = configuration
+			cluster := resources.NewCluster(storage)
+			for _, builder := range cluster.GetResourceBuilders(nil) {
+				if nodeSetBuilder, ok := builder.(*resources.StorageNodeSetBuilder); ok {
+					nodeSet := &api.StorageNodeSet{}
+					Expect(nodeSetBuilder.Build(nodeSet)).To(Succeed())
+					return nodeSet
+				}
+			}
+			Fail("StorageNodeSet is not built")
+			return nil
+		}
+		buildChecksum := func(sts *appsv1.StatefulSet, nodeSet *api.StorageNodeSet) string {
+			nodeSetResource := resources.NewStorageNodeSet(nodeSet)
+			for _, builder := range nodeSetResource.GetResourceBuilders(nil) {
+				if statefulSetBuilder, ok := builder.(*resources.StorageStatefulSetBuilder); ok {
+					Expect(statefulSetBuilder.Build(sts)).To(Succeed())
+				}
+			}
+			return sts.Spec.Template.Annotations[annotations.ConfigurationChecksum]
+		}
+
+		nodeSet := buildNodeSet("v1")
+		Expect(nodeSet.Spec.ConfigChangePolicy).To(Equal(api.ConfigChangePolicyManual))
+
+		sts := &appsv1.StatefulSet{}
+		Expect(buildChecksum(sts, nodeSet)).To(Equal(resources.SHAChecksum("v1")))
+		Expect(buildChecksum(sts, buildNodeSet("v2"))).To(Equal(resources.SHAChecksum("v1")))
+		Expect(sts.Annotations[annotations.ConfigurationChecksum]).To(Equal(resources.SHAChecksum("v2")))
+	})
+})
+
+func canaryUpdatedStatefulSet(nodes int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 2,
+			ReadyReplicas:      nodes,
+			UpdatedReplicas:    1,
+			CurrentRevision:    "storage-1",
+			UpdateRevision:     "storage-2",
+		},
+	}
+}
+
+var _ = Describe("Storage hosts DNS", func() {
+	// Hosts of the configuration are short pod names, resolved via search
+	// domain of the interconnect service, which must be the governing
+	// service of StatefulSet to give pods `<pod>.<service>` DNS names
+	expectHostsMatchPodDNS := func(storage *api.Storage, statefulSetNames ...string) {
+		configuration, err := api.BuildConfiguration(storage, nil)
+		Expect(err).ToNot(HaveOccurred())
+		parsed := schema.Configuration{}
+		Expect(yaml.Unmarshal(configuration, &parsed)).To(Succeed())
+
+		var podDNSNames []string
+		for _, name := range statefulSetNames {
+			builder := &resources.StorageStatefulSetBuilder{Storage: storage, Name: name}
+			sts := &appsv1.StatefulSet{}
+			Expect(builder.Build(sts)).To(Succeed())
+
+			serviceFQDN := fmt.Sprintf(api.InterconnectServiceFQDNFormat, storage.Name, storage.Namespace)
+			Expect(sts.Spec.ServiceName).To(Equal(fmt.Sprintf(resources.InterconnectServiceNameFormat, storage.Name)))
+			Expect(sts.Spec.Template.Spec.DNSConfig.Searches).To(ContainElement(serviceFQDN))
+
+			nodes := int(storage.Spec.Nodes)
+			for _, nodeSet := range storage.Spec.NodeSets {
+				if storage.Name+"-"+nodeSet.Name == name {
+					nodes = int(nodeSet.Nodes)
+				}
+			}
+			for i := 0; i < nodes; i++ {
+				podDNSNames = append(podDNSNames, fmt.Sprintf("%s-%d.%s", name, i, serviceFQDN))
+			}
+		}
+
+		var hostDNSNames []string
+		for _, host := range parsed.Hosts {
+			hostDNSNames = append(hostDNSNames, fmt.Sprintf("%s.%s",
+				host.Host, fmt.Sprintf(api.InterconnectServiceFQDNFormat, storage.Name, storage.Namespace)))
+		}
+		Expect(hostDNSNames).To(Equal(podDNSNames))
+	}
+
+	It("matches pods of Storage StatefulSet", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		expectHostsMatchPodDNS(storage, "storage")
+	})
+
+	It("matches pods of StorageNodeSet StatefulSets", func() {
+		storage := newTestStorage()
+		storage.Spec.Nodes = 3
+		storage.Spec.NodeSets = []api.StorageNodeSetSpecInline{
+			{Name: "a", StorageNodeSpec: api.StorageNodeSpec{Nodes: 2}},
+			{Name: "b", StorageNodeSpec: api.StorageNodeSpec{Nodes: 1}},
+		}
+		expectHostsMatchPodDNS(storage, "storage-a", "storage-b")
+	})
+})
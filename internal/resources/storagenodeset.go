@@ -81,6 +81,8 @@ func (b *StorageNodeSetResource) GetResourceBuilders(restConfig *rest.Config) []
 			Name:        statefulSetName,
 			Labels:      statefulSetLabels,
 			Annotations: statefulSetAnnotations,
+
+			WithOperatorToken: b.Annotations[annotations.OperatorToken] == "true",
 		},
 	)
 
@@ -0,0 +1,64 @@
+package scripting
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Runner executes scripts in background, so that reconcile is not blocked
+// for the duration of a script and picks up the result on requeue. Script
+// which is running when operator restarts is executed again.
+// Zero value is ready to use.
+type Runner struct {
+	mu   sync.Mutex
+	runs map[string]*run
+}
+
+type run struct {
+	done chan struct{}
+	err  error
+}
+
+// Start executes script in background unless execution with the key is
+// already started. Execution is bound by ExecuteTimeoutSeconds.
+func (r *Runner) Start(key string, execute func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.runs[key]; ok {
+		return
+	}
+	if r.runs == nil {
+		r.runs = make(map[string]*run)
+	}
+
+	current := &run{done: make(chan struct{})}
+	r.runs[key] = current
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ExecuteTimeoutSeconds*time.Second)
+		defer cancel()
+		current.err = execute(ctx)
+		close(current.done)
+	}()
+}
+
+// Result reports whether execution with the key is started and finished.
+// Result of finished execution is returned once and forgotten, so that
+// the next Start executes the script again.
+func (r *Runner) Result(key string) (started bool, finished bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.runs[key]
+	if !ok {
+		return false, false, nil
+	}
+	select {
+	case <-current.done:
+		delete(r.runs, key)
+		return true, true, current.err
+	default:
+		return true, false, nil
+	}
+}
@@ -0,0 +1,89 @@
+package scripting_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/scripting"
+)
+
+func TestScripting(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scripting suite")
+}
+
+var _ = Describe("Runner", func() {
+	It("reports script which is not started", func() {
+		runner := &scripting.Runner{}
+		started, finished, err := runner.Result("db/hash")
+		Expect(started).To(BeFalse())
+		Expect(finished).To(BeFalse())
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("executes script in background and returns its result once", func() {
+		runner := &scripting.Runner{}
+		release := make(chan struct{})
+		executions := make(chan struct{}, 2)
+		execute := func(ctx context.Context) error {
+			executions <- struct{}{}
+			<-release
+			return errors.New("table already exists")
+		}
+
+		runner.Start("db/hash", execute)
+		runner.Start("db/hash", execute)
+		started, finished, _ := runner.Result("db/hash")
+		Expect(started).To(BeTrue())
+		Expect(finished).To(BeFalse())
+
+		close(release)
+		Eventually(func() bool {
+			_, finished, _ := runner.Result("db/hash")
+			return finished
+		}).Should(BeTrue())
+		Expect(executions).To(HaveLen(1))
+
+		started, _, _ = runner.Result("db/hash")
+		Expect(started).To(BeFalse())
+	})
+
+	It("returns error of finished script", func() {
+		runner := &scripting.Runner{}
+		runner.Start("db/hash", func(ctx context.Context) error {
+			return errors.New("table already exists")
+		})
+
+		var err error
+		Eventually(func() bool {
+			var finished bool
+			_, finished, err = runner.Result("db/hash")
+			return finished
+		}).Should(BeTrue())
+		Expect(err).To(MatchError("table already exists"))
+	})
+
+	It("bounds execution of script with timeout", func() {
+		runner := &scripting.Runner{}
+		runner.Start("db/hash", func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			if !ok || time.Until(deadline) > scripting.ExecuteTimeoutSeconds*time.Second {
+				return errors.New("execution is not bound")
+			}
+			return nil
+		})
+
+		var err error
+		Eventually(func() bool {
+			var finished bool
+			_, finished, err = runner.Result("db/hash")
+			return finished
+		}).Should(BeTrue())
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,44 @@
+package scripting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/connection"
+)
+
+const ExecuteTimeoutSeconds = 300
+
+// Execute runs YQL script on the database at endpoint, script may contain
+// both schema and data queries.
+func Execute(
+	ctx context.Context,
+	endpoint string,
+	script string,
+	opts ...ydb.Option,
+) error {
+	logger := log.FromContext(ctx)
+
+	conn, err := connection.Open(ctx, endpoint, ydb.MergeOptions(opts...))
+	if err != nil {
+		return fmt.Errorf("error connecting to YDB: %w", err)
+	}
+	defer func() {
+		connection.Close(ctx, conn)
+	}()
+
+	scriptCtx, scriptCtxCancel := context.WithTimeout(ctx, ExecuteTimeoutSeconds*time.Second)
+	defer scriptCtxCancel()
+
+	logger.Info("YQL script execute request", "endpoint", endpoint)
+	result, err := conn.Scripting().Execute(scriptCtx, script, table.NewQueryParameters())
+	if err != nil {
+		return err
+	}
+	return result.Close()
+}
@@ -0,0 +1,56 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultDrainTimeout is the default of DrainTimeout
+const DefaultDrainTimeout = 30 * time.Second
+
+// DrainTimeout is how long critical operations in flight, e.g. exec into
+// Storage pods or CMS requests, are allowed to run after the operator is
+// requested to stop, so that they are not abandoned mid-way
+var DrainTimeout = DefaultDrainTimeout
+
+// detached carries values of the parent context, but is never canceled
+type detached struct {
+	parent context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (d detached) Done() <-chan struct{}             { return nil }
+func (d detached) Err() error                        { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
+
+// Critical returns context of a critical operation, which is canceled
+// DrainTimeout after ctx is canceled on operator shutdown instead of
+// immediately. The returned cancel function must be called when the
+// operation is done.
+func Critical(ctx context.Context) (context.Context, context.CancelFunc) {
+	criticalCtx, cancel := context.WithCancel(detached{parent: ctx})
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		timer := time.NewTimer(DrainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return criticalCtx, func() {
+		once.Do(func() { close(done) })
+		cancel()
+	}
+}
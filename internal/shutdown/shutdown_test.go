@@ -0,0 +1,57 @@
+package shutdown_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/shutdown"
+)
+
+func TestShutdown(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shutdown suite")
+}
+
+var _ = Describe("Critical", func() {
+	var drainTimeout time.Duration
+
+	BeforeEach(func() {
+		drainTimeout = shutdown.DrainTimeout
+	})
+
+	AfterEach(func() {
+		shutdown.DrainTimeout = drainTimeout
+	})
+
+	It("is not canceled immediately with parent context", func() {
+		shutdown.DrainTimeout = time.Hour
+
+		ctx, cancelParent := context.WithCancel(context.Background())
+		criticalCtx, cancel := shutdown.Critical(ctx)
+		defer cancel()
+
+		cancelParent()
+		Consistently(criticalCtx.Done(), 100*time.Millisecond).ShouldNot(BeClosed())
+	})
+
+	It("is canceled after drain timeout", func() {
+		shutdown.DrainTimeout = 10 * time.Millisecond
+
+		ctx, cancelParent := context.WithCancel(context.Background())
+		criticalCtx, cancel := shutdown.Critical(ctx)
+		defer cancel()
+
+		cancelParent()
+		Eventually(criticalCtx.Done()).Should(BeClosed())
+	})
+
+	It("is canceled when operation is done", func() {
+		criticalCtx, cancel := shutdown.Critical(context.Background())
+		cancel()
+		Expect(criticalCtx.Done()).To(BeClosed())
+	})
+})
@@ -0,0 +1,60 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tracing suite")
+}
+
+var _ = Describe("Tracing", func() {
+	var provider trace.TracerProvider
+
+	BeforeEach(func() {
+		provider = otel.GetTracerProvider()
+	})
+
+	AfterEach(func() {
+		otel.SetTracerProvider(provider)
+	})
+
+	It("keeps no-op tracer when endpoint is not set", func() {
+		shutdown, err := tracing.Setup(context.Background(), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+		Expect(otel.GetTracerProvider()).To(BeIdenticalTo(provider))
+	})
+
+	It("records phases of reconcile as child spans", func() {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+		ctx, span := tracing.Start(context.Background(), "Database.Sync",
+			tracing.ObjectAttributes("Database", "ydb", "database")...)
+		_, child := tracing.Start(ctx, "handleTenantCreation")
+		tracing.End(child, errors.New("CMS is unavailable"))
+		tracing.End(span, nil)
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("handleTenantCreation"))
+		Expect(spans[0].Parent().SpanID()).To(Equal(spans[1].SpanContext().SpanID()))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[1].Status().Code).To(Equal(codes.Unset))
+		Expect(spans[1].Attributes()).To(ContainElement(HaveField("Key", BeEquivalentTo("k8s.name"))))
+	})
+})
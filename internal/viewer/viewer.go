@@ -0,0 +1,261 @@
+package viewer
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	SysInfoPath    = "/viewer/json/sysinfo"
+	ClusterPath    = "/viewer/json/cluster"
+	TenantInfoPath = "/viewer/json/tenantinfo"
+
+	// TenantTypeDomain is the type of the cluster root reported by
+	// tenantinfo, which is not a tenant
+	TenantTypeDomain = "Domain"
+
+	RequestTimeoutSeconds = 10
+
+	// AuthorizationScheme is the scheme of Authorization header
+	// accepted by YDB monitoring endpoints
+	AuthorizationScheme = "OAuth"
+)
+
+type NodeInfo struct {
+	NodeID      uint32 `json:"NodeId"`
+	Host        string `json:"Host"`
+	SystemState string `json:"SystemState"`
+	Version     string `json:"Version"`
+}
+
+type sysInfoResponse struct {
+	SystemStateInfo []NodeInfo `json:"SystemStateInfo"`
+}
+
+type TenantInfo struct {
+	Name          string
+	Type          string
+	StorageGroups uint64
+}
+
+// uint64Value is unmarshaled both from JSON number and string,
+// viewer serializes 64-bit integers quoted
+type uint64Value uint64
+
+func (v *uint64Value) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseUint(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = uint64Value(value)
+	return nil
+}
+
+type clusterResponse struct {
+	StorageGroups uint64Value `json:"StorageGroups"`
+}
+
+type tenantInfoResponse struct {
+	TenantInfo []struct {
+		Name          string      `json:"Name"`
+		Type          string      `json:"Type"`
+		StorageGroups uint64Value `json:"StorageGroups"`
+	} `json:"TenantInfo"`
+}
+
+// Client requests YDB viewer API served by the status service.
+// Connections are reused between requests until Close is called.
+type Client struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+func NewClient(endpoint, token string, tlsConfig *tls.Config) *Client {
+	return &Client{
+		endpoint: endpoint,
+		token:    token,
+		httpClient: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   RequestTimeoutSeconds * time.Second,
+		},
+	}
+}
+
+// Close releases idle connections kept by the Client.
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// GetNodesInfo returns node ID, host, state and YDB version for every node known to the cluster.
+func (c *Client) GetNodesInfo(ctx context.Context) ([]NodeInfo, error) {
+	logger := log.FromContext(ctx)
+
+	body, err := c.get(ctx, SysInfoPath)
+	if err != nil {
+		logger.Error(err, "Failed to request viewer sysinfo", "endpoint", c.endpoint)
+		return nil, err
+	}
+
+	response := &sysInfoResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal viewer sysinfo response: %w", err)
+	}
+
+	return response.SystemStateInfo, nil
+}
+
+// GetStorageGroupsCount returns the number of storage groups of the cluster.
+func (c *Client) GetStorageGroupsCount(ctx context.Context) (uint64, error) {
+	body, err := c.get(ctx, ClusterPath)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to request viewer cluster info", "endpoint", c.endpoint)
+		return 0, err
+	}
+
+	response := &clusterResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal viewer cluster response: %w", err)
+	}
+
+	return uint64(response.StorageGroups), nil
+}
+
+// GetTenantsInfo returns every tenant of the cluster with the number of
+// storage groups allocated to it, including the cluster root.
+func (c *Client) GetTenantsInfo(ctx context.Context) ([]TenantInfo, error) {
+	body, err := c.get(ctx, TenantInfoPath)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to request viewer tenantinfo", "endpoint", c.endpoint)
+		return nil, err
+	}
+
+	response := &tenantInfoResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal viewer tenantinfo response: %w", err)
+	}
+
+	tenants := make([]TenantInfo, 0, len(response.TenantInfo))
+	for _, tenant := range response.TenantInfo {
+		tenants = append(tenants, TenantInfo{
+			Name:          tenant.Name,
+			Type:          tenant.Type,
+			StorageGroups: uint64(tenant.StorageGroups),
+		})
+	}
+	return tenants, nil
+}
+
+// SummarizeTenants returns the number of tenants and storage groups
+// allocated to them. The cluster root is not counted as a tenant.
+func SummarizeTenants(tenants []TenantInfo) (int, uint64) {
+	count := 0
+	var allocatedGroups uint64
+	for _, tenant := range tenants {
+		if tenant.Type == TenantTypeDomain {
+			continue
+		}
+		count++
+		allocatedGroups += tenant.StorageGroups
+	}
+	return count, allocatedGroups
+}
+
+// GetSelfCheckResult requests healthcheck JSON by path and parses
+// it into SelfCheckResult.
+func (c *Client) GetSelfCheckResult(ctx context.Context, path string) (*Ydb_Monitoring.SelfCheckResult, error) {
+	logger := log.FromContext(ctx)
+
+	body, err := c.get(ctx, path)
+	if err != nil {
+		logger.Error(err, "Failed to request SelfCheck", "endpoint", c.endpoint)
+		return nil, err
+	}
+
+	result := &Ydb_Monitoring.SelfCheckResult{}
+	if err = (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, result); err != nil {
+		logger.Error(err, "Failed to unmarshal SelfCheck response")
+		return result, err
+	}
+
+	return result, nil
+}
+
+// FindNodeByPodName returns the node running in Pod. YDB reports either
+// the short Pod name or its FQDN as the node host.
+func FindNodeByPodName(nodesInfo []NodeInfo, podName string) (NodeInfo, bool) {
+	for _, nodeInfo := range nodesInfo {
+		if nodeInfo.Host == podName || strings.HasPrefix(nodeInfo.Host, podName+".") {
+			return nodeInfo, true
+		}
+	}
+	return NodeInfo{}, false
+}
+
+// SummarizeVersions returns the version if all the nodes run the same one,
+// otherwise every version with its share of nodes, e.g. "25.1 (3/8), 25.2 (5/8)".
+// Nodes with unknown (empty) version are not listed, but count towards
+// the total, e.g. "25.1 (7/8)" when version of one node is unknown.
+func SummarizeVersions(versions []string) string {
+	counts := map[string]int{}
+	for _, version := range versions {
+		if version != "" {
+			counts[version]++
+		}
+	}
+
+	total := len(versions)
+	if len(counts) == 1 {
+		for version, count := range counts {
+			if count == total {
+				return version
+			}
+		}
+	}
+
+	uniqueVersions := make([]string, 0, len(counts))
+	for version := range counts {
+		uniqueVersions = append(uniqueVersions, version)
+	}
+	sort.Strings(uniqueVersions)
+
+	summary := make([]string, 0, len(uniqueVersions))
+	for _, version := range uniqueVersions {
+		summary = append(summary, fmt.Sprintf("%s (%d/%d)", version, counts[version], total))
+	}
+	return strings.Join(summary, ", ")
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		request.Header.Set("Authorization", AuthorizationScheme+" "+c.token)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected viewer response status: %s", response.Status)
+	}
+
+	return io.ReadAll(response.Body)
+}
@@ -0,0 +1,158 @@
+package viewer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ydb-platform/ydb-go-genproto/protos/Ydb_Monitoring"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/viewer"
+)
+
+func TestViewer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Viewer suite")
+}
+
+//nolint:all
+var sysInfoExample = `
+{
+  "SystemStateInfo": [
+    {"NodeId": 1, "Host": "storage-0.storage-interconnect.ydb.svc.cluster.local", "SystemState": "Green"},
+    {"NodeId": 2, "Host": "storage-1", "SystemState": "Yellow", "Version": "25.1"},
+    {"NodeId": 50000, "Host": "database-0.database-interconnect.ydb.svc.cluster.local", "SystemState": "Green"}
+  ]
+}
+`
+
+//nolint:all
+var tenantInfoExample = `
+{
+  "TenantInfo": [
+    {"Name": "/root", "Type": "Domain", "StorageGroups": "2"},
+    {"Name": "/root/database", "Type": "Dedicated", "StorageGroups": "3"},
+    {"Name": "/root/serverless", "Type": "Serverless"}
+  ]
+}
+`
+
+var _ = Describe("Testing viewer client", func() {
+	var server *httptest.Server
+	var requests []*http.Request
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			switch r.URL.Path {
+			case viewer.SysInfoPath:
+				_, _ = w.Write([]byte(sysInfoExample))
+			case viewer.ClusterPath:
+				_, _ = w.Write([]byte(`{"StorageGroups":10}`))
+			case viewer.TenantInfoPath:
+				_, _ = w.Write([]byte(tenantInfoExample))
+			case "/viewer/json/healthcheck":
+				_, _ = w.Write([]byte(`{"self_check_result":"GOOD","unknown_field":1}`))
+			case "/healthcheck":
+				_, _ = w.Write([]byte("GOOD"))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Get nodes info from sysinfo", func() {
+		client := viewer.NewClient(server.URL, "token", nil)
+		defer client.Close()
+
+		nodesInfo, err := client.GetNodesInfo(context.Background())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodesInfo).Should(HaveLen(3))
+		Expect(nodesInfo[1]).Should(Equal(viewer.NodeInfo{NodeID: 2, Host: "storage-1", SystemState: "Yellow", Version: "25.1"}))
+		Expect(requests[0].Header.Get("Authorization")).Should(Equal("OAuth token"))
+	})
+
+	It("Parse SelfCheckResult from viewer JSON", func() {
+		client := viewer.NewClient(server.URL, "", nil)
+		defer client.Close()
+
+		result, err := client.GetSelfCheckResult(context.Background(), "/viewer/json/healthcheck")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.SelfCheckResult).Should(Equal(Ydb_Monitoring.SelfCheck_GOOD))
+		Expect(requests[0].Header).ShouldNot(HaveKey("Authorization"))
+	})
+
+	It("Fail on non-JSON healthcheck response", func() {
+		client := viewer.NewClient(server.URL, "", nil)
+		defer client.Close()
+
+		_, err := client.GetSelfCheckResult(context.Background(), "/healthcheck")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Fail on unexpected response status", func() {
+		client := viewer.NewClient(server.URL, "", nil)
+		defer client.Close()
+
+		_, err := client.GetSelfCheckResult(context.Background(), "/viewer/json/unknown")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Match Pod with node by short name or FQDN", func() {
+		client := viewer.NewClient(server.URL, "", nil)
+		defer client.Close()
+
+		nodesInfo, err := client.GetNodesInfo(context.Background())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		nodeInfo, found := viewer.FindNodeByPodName(nodesInfo, "storage-0")
+		Expect(found).Should(BeTrue())
+		Expect(nodeInfo.NodeID).Should(Equal(uint32(1)))
+
+		nodeInfo, found = viewer.FindNodeByPodName(nodesInfo, "storage-1")
+		Expect(found).Should(BeTrue())
+		Expect(nodeInfo.NodeID).Should(Equal(uint32(2)))
+
+		// storage-1 must not match storage-10 and vice versa
+		_, found = viewer.FindNodeByPodName(nodesInfo, "storage-10")
+		Expect(found).Should(BeFalse())
+		_, found = viewer.FindNodeByPodName(nodesInfo, "storage")
+		Expect(found).Should(BeFalse())
+	})
+
+	It("Summarize uniform and mixed versions of nodes", func() {
+		Expect(viewer.SummarizeVersions(nil)).Should(BeEmpty())
+		Expect(viewer.SummarizeVersions([]string{"25.1", "25.1"})).Should(Equal("25.1"))
+		Expect(viewer.SummarizeVersions([]string{"25.1", "25.1", ""})).Should(Equal("25.1 (2/3)"))
+		Expect(viewer.SummarizeVersions([]string{"", ""})).Should(BeEmpty())
+		Expect(viewer.SummarizeVersions([]string{"25.2", "25.1", ""})).Should(Equal("25.1 (1/3), 25.2 (1/3)"))
+		Expect(viewer.SummarizeVersions([]string{
+			"25.2", "25.1", "25.2", "25.1", "25.2", "25.1", "25.2", "25.2",
+		})).Should(Equal("25.1 (3/8), 25.2 (5/8)"))
+	})
+
+	It("Summarize storage groups allocated to tenants", func() {
+		client := viewer.NewClient(server.URL, "", nil)
+		defer client.Close()
+
+		storageGroups, err := client.GetStorageGroupsCount(context.Background())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(storageGroups).Should(Equal(uint64(10)))
+
+		tenants, err := client.GetTenantsInfo(context.Background())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tenants).Should(HaveLen(3))
+
+		count, allocatedGroups := viewer.SummarizeTenants(tenants)
+		Expect(count).Should(Equal(2))
+		Expect(allocatedGroups).Should(Equal(uint64(3)))
+	})
+})
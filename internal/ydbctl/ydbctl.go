@@ -0,0 +1,203 @@
+// Package ydbctl implements `ydbctl` subcommand of the operator binary
+// performing one-off operations with YDB cluster, e.g.
+//
+//	manager ydbctl tenant list --endpoint grpc://storage-grpc.ydb:2135
+//
+// so that there is no need to exec into YDB Pods for routine CMS requests.
+package ydbctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	ydb "github.com/ydb-platform/ydb-go-sdk/v3"
+	ydbCredentials "github.com/ydb-platform/ydb-go-sdk/v3/credentials"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/cms"
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/healthcheck"
+)
+
+// Command is the first argument of operator binary which runs ydbctl
+// instead of the controller manager
+const Command = "ydbctl"
+
+const usage = `Usage: manager ydbctl <command> [flags]
+
+Commands:
+  tenant list          List databases registered in CMS
+  healthcheck          Request SelfCheck of the cluster
+  maintenance drop     Drop CMS maintenance task releasing its locks
+
+Run 'manager ydbctl <command> -h' for flags of the command.
+`
+
+var errUsage = errors.New("invalid usage")
+
+// connectionFlags are common flags of all commands
+type connectionFlags struct {
+	endpoint  string
+	domain    string
+	tokenFile string
+	caFile    string
+}
+
+func (c *connectionFlags) bind(fs *flag.FlagSet) {
+	fs.StringVar(&c.endpoint, "endpoint", "", "Storage grpc endpoint with scheme, e.g. grpcs://storage-grpc.ydb:2135")
+	fs.StringVar(&c.domain, "domain", "Root", "Root storage domain")
+	fs.StringVar(&c.tokenFile, "token-file", "", "File with YDB access token, anonymous credentials are used when empty")
+	fs.StringVar(&c.caFile, "ca-file", "", "File with CA certificates for grpcs endpoint")
+}
+
+func (c *connectionFlags) credentials() (ydbCredentials.Credentials, error) {
+	if c.tokenFile == "" {
+		return ydbCredentials.NewAnonymousCredentials(), nil
+	}
+	token, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	return ydbCredentials.NewAccessTokenCredentials(strings.TrimSpace(string(token))), nil
+}
+
+// options returns credentials and the rest of connection options
+func (c *connectionFlags) options() (ydbCredentials.Credentials, []ydb.Option, error) {
+	if c.endpoint == "" {
+		return nil, nil, fmt.Errorf("%w: --endpoint is required", errUsage)
+	}
+	creds, err := c.credentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	var opts []ydb.Option
+	if c.caFile != "" {
+		opts = append(opts, ydb.WithCertificatesFromFile(c.caFile))
+	}
+	return creds, opts, nil
+}
+
+// Run executes ydbctl command given by args and returns exit code
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	err := run(ctx, args, stdout, stderr)
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	fmt.Fprintf(stderr, "ydbctl: %s\n", err)
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	return 1
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: command is required", errUsage)
+	}
+
+	switch {
+	case len(args) >= 2 && args[0] == "tenant" && args[1] == "list":
+		return listTenants(ctx, args[2:], stdout, stderr)
+	case args[0] == "healthcheck":
+		return runHealthCheck(ctx, args[1:], stdout, stderr)
+	case len(args) >= 2 && args[0] == "maintenance" && args[1] == "drop":
+		return dropMaintenance(ctx, args[2:], stdout, stderr)
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, strings.Join(args, " "))
+	}
+}
+
+func listTenants(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var conn connectionFlags
+	fs := flag.NewFlagSet("tenant list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	conn.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	creds, opts, err := conn.options()
+	if err != nil {
+		return err
+	}
+
+	tenant := &cms.Tenant{StorageEndpoint: conn.endpoint, Domain: conn.domain}
+	response, err := tenant.ListDatabases(ctx, append(opts, ydb.WithCredentials(creds))...)
+	if err != nil {
+		return err
+	}
+	paths, err := cms.GetDatabasePaths(response)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Fprintln(stdout, path)
+	}
+	return nil
+}
+
+func runHealthCheck(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var conn connectionFlags
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	conn.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	creds, opts, err := conn.options()
+	if err != nil {
+		return err
+	}
+
+	result, err := healthcheck.GetSelfCheckResult(ctx, healthcheck.Target{
+		Endpoint:    conn.endpoint,
+		Domain:      conn.domain,
+		Credentials: creds,
+		Options:     opts,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "SelfCheck result: %s\n", result.GetSelfCheckResult())
+	for _, issue := range result.GetIssueLog() {
+		fmt.Fprintf(stdout, "%s %s: %s\n", issue.GetStatus(), issue.GetType(), issue.GetMessage())
+	}
+	return nil
+}
+
+func dropMaintenance(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var conn connectionFlags
+	var taskUID string
+	fs := flag.NewFlagSet("maintenance drop", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	conn.bind(fs)
+	fs.StringVar(&taskUID, "task", "", "UID of the maintenance task, e.g. reported in status.maintenanceTask of Storage")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if taskUID == "" {
+		return fmt.Errorf("%w: --task is required", errUsage)
+	}
+	creds, opts, err := conn.options()
+	if err != nil {
+		return err
+	}
+
+	maintenance := &cms.Maintenance{
+		StorageEndpoint: conn.endpoint,
+		Domain:          conn.domain,
+		TaskUID:         taskUID,
+	}
+	if err := maintenance.DropMaintenance(ctx, append(opts, ydb.WithCredentials(creds))...); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Maintenance task %s dropped\n", taskUID)
+	return nil
+}
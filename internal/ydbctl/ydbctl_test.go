@@ -0,0 +1,44 @@
+package ydbctl_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/ydb-platform/ydb-kubernetes-operator/internal/ydbctl"
+)
+
+func TestYDBCtl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ydbctl suite")
+}
+
+var _ = Describe("ydbctl", func() {
+	run := func(args ...string) (int, string) {
+		var stdout, stderr bytes.Buffer
+		code := ydbctl.Run(context.Background(), args, &stdout, &stderr)
+		return code, stderr.String()
+	}
+
+	It("prints usage for unknown command", func() {
+		code, stderr := run("tenant", "remove")
+		Expect(code).To(Equal(2))
+		Expect(stderr).To(ContainSubstring(`unknown command "tenant remove"`))
+		Expect(stderr).To(ContainSubstring("Usage: manager ydbctl"))
+	})
+
+	It("requires endpoint", func() {
+		code, stderr := run("tenant", "list", "--domain", "Root")
+		Expect(code).To(Equal(2))
+		Expect(stderr).To(ContainSubstring("--endpoint is required"))
+	})
+
+	It("requires maintenance task", func() {
+		code, stderr := run("maintenance", "drop", "--endpoint", "grpc://localhost:2135")
+		Expect(code).To(Equal(2))
+		Expect(stderr).To(ContainSubstring("--task is required"))
+	})
+})